@@ -0,0 +1,8 @@
+// Package api містить опис HTTP API у форматі OpenAPI 3. Документ підтримується вручну;
+// тест у пакеті handlers звіряє його шляхи з маршрутами, які реєструють обробники
+package api
+
+import _ "embed"
+
+//go:embed openapi.json
+var OpenAPI []byte
@@ -0,0 +1,186 @@
+// Package chart малює прості SVG-діаграми (кругову, стовпчикову та прогрес бюджетів) без JavaScript,
+// тож їх можна вбудовувати в листи, експорти та сторінки як звичайні зображення
+package chart
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"math"
+)
+
+const (
+	width      = 640
+	height     = 360
+	titleSpace = 40
+	fontStyle  = `font-family="sans-serif" font-size="12"`
+)
+
+// Palette - кольори секторів і стовпчиків, що повторюються по колу
+var Palette = []string{"#4e79a7", "#f28e2b", "#e15759", "#76b7b2", "#59a14f", "#edc948", "#b07aa1", "#ff9da7", "#9c755f", "#bab0ac"}
+
+// Point - одне значення діаграми з підписом
+type Point struct {
+	Label string
+	Value float64
+}
+
+// Progress - використана частина ліміту (наприклад, витрати в межах бюджету категорії)
+type Progress struct {
+	Label string
+	Value float64
+	Limit float64
+}
+
+// errWriter запам'ятовує першу помилку запису, щоб не перевіряти її після кожного елемента
+type errWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (ew *errWriter) printf(format string, args ...interface{}) {
+	if ew.err != nil {
+		return
+	}
+	_, ew.err = fmt.Fprintf(ew.w, format, args...)
+}
+
+func begin(w io.Writer, title string) *errWriter {
+	ew := &errWriter{w: w}
+	ew.printf(`<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" %s>`, width, height, width, height, fontStyle)
+	ew.printf(`<rect width="100%%" height="100%%" fill="#ffffff"/>`)
+	ew.printf(`<text x="%d" y="24" text-anchor="middle" font-size="16" font-weight="bold">%s</text>`, width/2, html.EscapeString(title))
+	return ew
+}
+
+func (ew *errWriter) end() error {
+	ew.printf(`</svg>`)
+	return ew.err
+}
+
+func (ew *errWriter) empty() {
+	ew.printf(`<text x="%d" y="%d" text-anchor="middle" fill="#888888">No data</text>`, width/2, height/2)
+}
+
+func color(i int) string {
+	return Palette[i%len(Palette)]
+}
+
+// Pie малює кругову діаграму з легендою; від'ємні та нульові значення пропускаються
+func Pie(w io.Writer, title string, points []Point) error {
+	ew := begin(w, title)
+
+	total := 0.0
+	for _, p := range points {
+		if p.Value > 0 {
+			total += p.Value
+		}
+	}
+	if total == 0 {
+		ew.empty()
+		return ew.end()
+	}
+
+	cx, cy := 170.0, float64(titleSpace+(height-titleSpace)/2)
+	r := float64(height-titleSpace)/2 - 20
+
+	angle := -math.Pi / 2
+	legendY := titleSpace + 20
+	for i, p := range points {
+		if p.Value <= 0 {
+			continue
+		}
+
+		share := p.Value / total
+		if share >= 1 {
+			ew.printf(`<circle cx="%.2f" cy="%.2f" r="%.2f" fill="%s"/>`, cx, cy, r, color(i))
+		} else {
+			next := angle + share*2*math.Pi
+			largeArc := 0
+			if share > 0.5 {
+				largeArc = 1
+			}
+			ew.printf(`<path d="M%.2f,%.2f L%.2f,%.2f A%.2f,%.2f 0 %d,1 %.2f,%.2f Z" fill="%s" stroke="#ffffff"/>`,
+				cx, cy, cx+r*math.Cos(angle), cy+r*math.Sin(angle), r, r, largeArc, cx+r*math.Cos(next), cy+r*math.Sin(next), color(i))
+			angle = next
+		}
+
+		ew.printf(`<rect x="360" y="%d" width="12" height="12" fill="%s"/>`, legendY-10, color(i))
+		ew.printf(`<text x="380" y="%d">%s - %s (%.1f%%)</text>`, legendY, html.EscapeString(p.Label), formatValue(p.Value), share*100)
+		legendY += 20
+	}
+
+	return ew.end()
+}
+
+// Bars малює вертикальні стовпчики зі спільною шкалою від нуля
+func Bars(w io.Writer, title string, points []Point) error {
+	ew := begin(w, title)
+
+	maxValue := 0.0
+	for _, p := range points {
+		maxValue = math.Max(maxValue, p.Value)
+	}
+	if len(points) == 0 || maxValue == 0 {
+		ew.empty()
+		return ew.end()
+	}
+
+	left, bottom := 50.0, float64(height-40)
+	plotWidth, plotHeight := float64(width)-left-20, bottom-float64(titleSpace)-20
+	slot := plotWidth / float64(len(points))
+
+	ew.printf(`<line x1="%.2f" y1="%.2f" x2="%.2f" y2="%.2f" stroke="#333333"/>`, left, bottom, left+plotWidth, bottom)
+	ew.printf(`<text x="%.2f" y="%.2f" text-anchor="end">%s</text>`, left-6, bottom-plotHeight+4, formatValue(maxValue))
+	ew.printf(`<text x="%.2f" y="%.2f" text-anchor="end">0</text>`, left-6, bottom+4)
+
+	for i, p := range points {
+		barHeight := math.Max(p.Value, 0) / maxValue * plotHeight
+		x := left + float64(i)*slot + slot*0.15
+		ew.printf(`<rect x="%.2f" y="%.2f" width="%.2f" height="%.2f" fill="%s"><title>%s: %s</title></rect>`,
+			x, bottom-barHeight, slot*0.7, barHeight, color(0), html.EscapeString(p.Label), formatValue(p.Value))
+		ew.printf(`<text x="%.2f" y="%.2f" text-anchor="middle" font-size="10">%s</text>`, x+slot*0.35, bottom+16, html.EscapeString(p.Label))
+	}
+
+	return ew.end()
+}
+
+// ProgressBars малює горизонтальні смуги використання лімітів; перевищення ліміту виділяється червоним
+func ProgressBars(w io.Writer, title string, items []Progress) error {
+	ew := begin(w, title)
+
+	if len(items) == 0 {
+		ew.empty()
+		return ew.end()
+	}
+
+	left, barWidth := 140.0, float64(width)-140-120
+	rowHeight := math.Min(36, float64(height-titleSpace-10)/float64(len(items)))
+
+	for i, item := range items {
+		y := float64(titleSpace) + float64(i)*rowHeight + 10
+		share := 1.0
+		if item.Limit > 0 {
+			share = item.Value / item.Limit
+		}
+
+		fill := "#59a14f"
+		if share > 1 {
+			fill = "#e15759"
+		}
+
+		ew.printf(`<text x="%.2f" y="%.2f" text-anchor="end">%s</text>`, left-8, y+rowHeight/2, html.EscapeString(item.Label))
+		ew.printf(`<rect x="%.2f" y="%.2f" width="%.2f" height="%.2f" fill="#eeeeee"/>`, left, y+4, barWidth, rowHeight-12)
+		ew.printf(`<rect x="%.2f" y="%.2f" width="%.2f" height="%.2f" fill="%s"/>`, left, y+4, barWidth*math.Min(math.Max(share, 0), 1), rowHeight-12, fill)
+		ew.printf(`<text x="%.2f" y="%.2f">%s / %s</text>`, left+barWidth+8, y+rowHeight/2, formatValue(item.Value), formatValue(item.Limit))
+	}
+
+	return ew.end()
+}
+
+func formatValue(value float64) string {
+	if value == math.Trunc(value) {
+		return fmt.Sprintf("%.0f", value)
+	}
+	return fmt.Sprintf("%.2f", value)
+}
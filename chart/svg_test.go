@@ -0,0 +1,99 @@
+package chart
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+// checkSVG перевіряє, що результат є коректним XML
+func checkSVG(t *testing.T, svg string) {
+	t.Helper()
+	decoder := xml.NewDecoder(strings.NewReader(svg))
+	for {
+		_, err := decoder.Token()
+		if err != nil {
+			if err != io.EOF {
+				t.Fatalf("Received invalid SVG: %v\n%s", err, svg)
+			}
+			return
+		}
+	}
+}
+
+func TestPie(t *testing.T) {
+	// Arrange
+	var buf bytes.Buffer
+	points := []Point{{Label: "Food & drinks", Value: 75}, {Label: "Rent", Value: 25}, {Label: "Refund", Value: -10}}
+
+	// Act
+	err := Pie(&buf, "Categories", points)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Received an error: received %v, expected %v", err, nil)
+	}
+	svg := buf.String()
+	checkSVG(t, svg)
+
+	if strings.Count(svg, "<path") != 2 {
+		t.Errorf("Expected two pie slices in %s", svg)
+	}
+	if !strings.Contains(svg, "Food &amp; drinks - 75 (75.0%)") {
+		t.Errorf("Expected escaped legend with share in %s", svg)
+	}
+}
+
+func TestPie_SingleSlice(t *testing.T) {
+	var buf bytes.Buffer
+
+	err := Pie(&buf, "Categories", []Point{{Label: "Food", Value: 10}})
+
+	if err != nil || !strings.Contains(buf.String(), "<circle") {
+		t.Errorf("Single category must be drawn as a full circle: %v %s", err, buf.String())
+	}
+}
+
+func TestBars_Empty(t *testing.T) {
+	var buf bytes.Buffer
+
+	err := Bars(&buf, "Monthly", nil)
+
+	if err != nil || !strings.Contains(buf.String(), "No data") {
+		t.Errorf("Empty chart must show a placeholder: %v %s", err, buf.String())
+	}
+	checkSVG(t, buf.String())
+}
+
+func TestProgressBars_OverBudget(t *testing.T) {
+	// Arrange
+	var buf bytes.Buffer
+	items := []Progress{{Label: "Food", Value: 150, Limit: 100}, {Label: "Rent", Value: 50, Limit: 100}}
+
+	// Act
+	err := ProgressBars(&buf, "Budgets", items)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Received an error: received %v, expected %v", err, nil)
+	}
+	checkSVG(t, buf.String())
+	if strings.Count(buf.String(), "#e15759") != 1 {
+		t.Errorf("Expected exactly one over-budget bar in %s", buf.String())
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) { return 0, errors.New("closed") }
+
+func TestBars_WriteError(t *testing.T) {
+	err := Bars(failingWriter{}, "Monthly", []Point{{Label: "Jan", Value: 1}})
+
+	if err == nil {
+		t.Errorf("Received an error: received %v, expected write error", err)
+	}
+}
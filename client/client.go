@@ -0,0 +1,160 @@
+// Package client - типізований Go-клієнт HTTP API трекера витрат (див. api/openapi.json).
+// Запити й відповіді використовують ті самі типи з пакета models, що й сервер
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+type Client struct {
+	BaseURL    string
+	Token      string // JWT, отриманий через Login; додається до кожного запиту
+	HTTPClient *http.Client
+}
+
+func New(baseURL string) *Client {
+	return &Client{
+		BaseURL:    strings.TrimRight(baseURL, "/"),
+		HTTPClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// FieldError описує проблему з окремим полем запиту
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Problem - помилка API у форматі RFC 7807; Code - машиночитний код на кшталт "ledger_not_found"
+type Problem struct {
+	Type     string       `json:"type"`
+	Title    string       `json:"title"`
+	Status   int          `json:"status"`
+	Detail   string       `json:"detail"`
+	Instance string       `json:"instance"`
+	Code     string       `json:"code"`
+	Errors   []FieldError `json:"errors"`
+}
+
+func (p *Problem) Error() string {
+	message := fmt.Sprintf("%d %s", p.Status, p.Title)
+	if p.Detail != "" {
+		message += ": " + p.Detail
+	}
+	for _, field := range p.Errors {
+		message += fmt.Sprintf("; %s: %s", field.Field, field.Message)
+	}
+	return message
+}
+
+// TwoFactorRequired повертає Login, коли пароль прийнято, але обліковий запис захищено 2FA:
+// вхід завершується викликом LoginTwoFactor з ChallengeToken і кодом з автентифікатора
+type TwoFactorRequired struct {
+	ChallengeToken string
+}
+
+func (e *TwoFactorRequired) Error() string {
+	return "two-factor authentication code required"
+}
+
+// do виконує запит: body кодується в JSON, успішна відповідь декодується в out (якщо він не nil),
+// а відповідь з помилкою повертається як *Problem
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, out interface{}) (*http.Response, error) {
+	var reader io.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(encoded)
+	}
+
+	target := c.BaseURL + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, target, reader)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return resp, decodeProblem(resp)
+	}
+
+	if out == nil {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return resp, nil
+	}
+
+	if raw, ok := out.(*[]byte); ok {
+		*raw, err = io.ReadAll(resp.Body)
+		return resp, err
+	}
+
+	return resp, json.NewDecoder(resp.Body).Decode(out)
+}
+
+func decodeProblem(resp *http.Response) error {
+	problem := &Problem{Status: resp.StatusCode, Title: http.StatusText(resp.StatusCode)}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil || len(data) == 0 {
+		return problem
+	}
+
+	// Відповідь не у форматі problem+json (наприклад, від проксі) передається як опис помилки
+	if json.Unmarshal(data, problem) != nil {
+		problem.Detail = strings.TrimSpace(string(data))
+	}
+
+	return problem
+}
+
+// dayQuery додає до запиту дату у форматі 2006-01-02, якщо вона вказана
+func dayQuery(query url.Values, name string, day time.Time) {
+	if !day.IsZero() {
+		query.Set(name, day.Format("2006-01-02"))
+	}
+}
+
+func monthQuery(query url.Values, month time.Time) {
+	if !month.IsZero() {
+		query.Set("month", month.Format("2006-01"))
+	}
+}
+
+func ledgerQuery(ledgerID int) url.Values {
+	query := url.Values{}
+	if ledgerID != 0 {
+		query.Set("ledger", fmt.Sprint(ledgerID))
+	}
+	return query
+}
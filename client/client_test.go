@@ -0,0 +1,108 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ChomuCake/uni-golang-labs/models"
+)
+
+func TestClient_LoginAndListExpenses(t *testing.T) {
+	// Arrange
+	mux := http.NewServeMux()
+	mux.HandleFunc("/login", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Authorization", "secret-token")
+	})
+	mux.HandleFunc("/expenses", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret-token" {
+			t.Errorf("Received incorrect authorization: %q", r.Header.Get("Authorization"))
+		}
+		if r.URL.RawQuery != "ledger=3&sort=month" {
+			t.Errorf("Received incorrect query: %q", r.URL.RawQuery)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[{"id":1,"category":"Food","amount":10,"date":"2024-03-01T10:00:00Z"}]`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	c := New(server.URL)
+
+	// Act
+	token, err := c.Login(context.Background(), "john", "secret123")
+	if err != nil {
+		t.Fatalf("Received an error: received %v, expected %v", err, nil)
+	}
+	expenses, err := c.ListExpenses(context.Background(), ExpenseFilter{LedgerID: 3, Sort: "month"})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Received an error: received %v, expected %v", err, nil)
+	}
+	if token != "secret-token" || c.Token != token {
+		t.Errorf("Received incorrect token: received %q", token)
+	}
+	if len(expenses) != 1 || expenses[0].Category != "Food" || !expenses[0].Date.Equal(time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("Received incorrect expenses: %+v", expenses)
+	}
+}
+
+func TestClient_Problem(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/problem+json")
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"type":"about:blank","title":"Bad Request","status":400,"code":"validation_failed",
+			"detail":"validation failed","errors":[{"field":"amount","code":"out_of_range","message":"must be between 1 and 100000000"}]}`))
+	}))
+	defer server.Close()
+
+	// Act
+	err := New(server.URL).CreateExpense(context.Background(), 0, models.Expense{Category: "Food"})
+
+	// Assert
+	var problem *Problem
+	if !errors.As(err, &problem) {
+		t.Fatalf("Received incorrect error: received %v, expected *Problem", err)
+	}
+	if problem.Code != "validation_failed" || len(problem.Errors) != 1 || problem.Errors[0].Field != "amount" {
+		t.Errorf("Received incorrect problem: %+v", problem)
+	}
+}
+
+func TestClient_StatementAndPathEscaping(t *testing.T) {
+	// Arrange
+	mux := http.NewServeMux()
+	mux.HandleFunc("/reports/statement.pdf", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("month") != "2024-03" {
+			t.Errorf("Received incorrect month: %q", r.URL.Query().Get("month"))
+		}
+		w.Write([]byte("%PDF-1.4"))
+	})
+	mux.HandleFunc("/budgets/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete || r.URL.EscapedPath() != "/budgets/Food%20&%20drinks" {
+			t.Errorf("Received incorrect request: %v %v", r.Method, r.URL.EscapedPath())
+		}
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	c := New(server.URL)
+
+	// Act
+	document, err := c.Statement(context.Background(), time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("Received an error: received %v, expected %v", err, nil)
+	}
+	err = c.DeleteBudget(context.Background(), "Food & drinks")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Received an error: received %v, expected %v", err, nil)
+	}
+	if string(document) != "%PDF-1.4" {
+		t.Errorf("Received incorrect document: %q", document)
+	}
+}
@@ -0,0 +1,387 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ChomuCake/uni-golang-labs/models"
+)
+
+// --------------------------- Користувачі ---------------------------
+
+func (c *Client) Register(ctx context.Context, user models.User) error {
+	_, err := c.do(ctx, http.MethodPost, "/register", nil, user, nil)
+	return err
+}
+
+// Login отримує JWT і зберігає його в клієнті для наступних запитів. Для облікового запису з 2FA
+// повертає *TwoFactorRequired, і вхід завершується через LoginTwoFactor
+func (c *Client) Login(ctx context.Context, username, password string) (string, error) {
+	var body []byte
+	resp, err := c.do(ctx, http.MethodPost, "/login", nil, models.User{Username: username, Password: password}, &body)
+	if err != nil {
+		return "", err
+	}
+
+	var challenge models.TwoFactorChallenge
+	if json.Unmarshal(body, &challenge) == nil && challenge.Required {
+		return "", &TwoFactorRequired{ChallengeToken: challenge.ChallengeToken}
+	}
+
+	return c.saveToken(resp)
+}
+
+// LoginTwoFactor - другий крок входу: токен з *TwoFactorRequired і код з автентифікатора або код відновлення
+func (c *Client) LoginTwoFactor(ctx context.Context, challengeToken, code string) (string, error) {
+	resp, err := c.do(ctx, http.MethodPost, "/login/2fa", nil, models.TwoFactorLogin{ChallengeToken: challengeToken, Code: code}, nil)
+	if err != nil {
+		return "", err
+	}
+
+	return c.saveToken(resp)
+}
+
+// saveToken бере JWT із заголовка Authorization відповіді входу
+func (c *Client) saveToken(resp *http.Response) (string, error) {
+	token := strings.TrimSpace(strings.TrimPrefix(resp.Header.Get("Authorization"), "Bearer "))
+	if token == "" {
+		return "", errors.New("login response has no token")
+	}
+
+	c.Token = token
+	return token, nil
+}
+
+// SetupTwoFactor створює секрет TOTP; 2FA запрацює після EnableTwoFactor
+func (c *Client) SetupTwoFactor(ctx context.Context) (models.TOTPSetup, error) {
+	var setup models.TOTPSetup
+	_, err := c.do(ctx, http.MethodPost, "/me/2fa/setup", nil, nil, &setup)
+	return setup, err
+}
+
+// EnableTwoFactor підтверджує секрет кодом і повертає коди відновлення
+func (c *Client) EnableTwoFactor(ctx context.Context, code string) ([]string, error) {
+	var codes models.RecoveryCodes
+	_, err := c.do(ctx, http.MethodPost, "/me/2fa/enable", nil, models.TwoFactorCode{Code: code}, &codes)
+	return codes.Codes, err
+}
+
+func (c *Client) DisableTwoFactor(ctx context.Context, code string) error {
+	_, err := c.do(ctx, http.MethodPost, "/me/2fa/disable", nil, models.TwoFactorCode{Code: code}, nil)
+	return err
+}
+
+// CreateAccessToken видає персональний токен; його секрет (Token) повертається лише цього разу
+func (c *Client) CreateAccessToken(ctx context.Context, request models.AccessTokenRequest) (models.CreatedAccessToken, error) {
+	var created models.CreatedAccessToken
+	_, err := c.do(ctx, http.MethodPost, "/me/tokens", nil, request, &created)
+	return created, err
+}
+
+func (c *Client) ListAccessTokens(ctx context.Context) ([]models.AccessToken, error) {
+	var tokens []models.AccessToken
+	_, err := c.do(ctx, http.MethodGet, "/me/tokens", nil, nil, &tokens)
+	return tokens, err
+}
+
+func (c *Client) RevokeAccessToken(ctx context.Context, tokenID int) error {
+	_, err := c.do(ctx, http.MethodDelete, fmt.Sprintf("/me/tokens/%d", tokenID), nil, nil, nil)
+	return err
+}
+
+func (c *Client) Profile(ctx context.Context) (models.User, error) {
+	var user models.User
+	_, err := c.do(ctx, http.MethodGet, "/me", nil, nil, &user)
+	return user, err
+}
+
+func (c *Client) UpdateProfile(ctx context.Context, update models.ProfileUpdate) (models.User, error) {
+	var user models.User
+	_, err := c.do(ctx, http.MethodPatch, "/me", nil, update, &user)
+	return user, err
+}
+
+// ChangePassword змінює пароль; сервер відкликає всі старі токени, тож клієнт зберігає новий
+func (c *Client) ChangePassword(ctx context.Context, currentPassword, newPassword string) error {
+	resp, err := c.do(ctx, http.MethodPost, "/me/password", nil, models.PasswordChange{CurrentPassword: currentPassword, NewPassword: newPassword}, nil)
+	if err != nil {
+		return err
+	}
+
+	if token := strings.TrimSpace(strings.TrimPrefix(resp.Header.Get("Authorization"), "Bearer ")); token != "" {
+		c.Token = token
+	}
+	return nil
+}
+
+// ForgotPassword просить сервер надіслати посилання для відновлення пароля на email
+func (c *Client) ForgotPassword(ctx context.Context, email string) error {
+	_, err := c.do(ctx, http.MethodPost, "/password/forgot", nil, map[string]string{"email": email}, nil)
+	return err
+}
+
+// ResetPassword встановлює новий пароль за токеном з листа; після цього потрібно увійти знову
+func (c *Client) ResetPassword(ctx context.Context, token, newPassword string) error {
+	_, err := c.do(ctx, http.MethodPost, "/password/reset", nil, models.PasswordReset{Token: token, NewPassword: newPassword}, nil)
+	return err
+}
+
+func (c *Client) DeleteAccount(ctx context.Context) error {
+	_, err := c.do(ctx, http.MethodDelete, "/me", nil, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	c.Token = ""
+	return nil
+}
+
+// --------------------------- Витрати ---------------------------
+
+// ExpenseFilter - параметри списку витрат: LedgerID 0 - особисті витрати,
+// Sort - day, week, month, year (фільтр за поточним періодом) або all
+type ExpenseFilter struct {
+	LedgerID int
+	Sort     string
+}
+
+func (c *Client) ListExpenses(ctx context.Context, filter ExpenseFilter) ([]models.Expense, error) {
+	query := ledgerQuery(filter.LedgerID)
+	if filter.Sort != "" {
+		query.Set("sort", filter.Sort)
+	}
+
+	var expenses []models.Expense
+	_, err := c.do(ctx, http.MethodGet, "/expenses", query, nil, &expenses)
+	return expenses, err
+}
+
+func (c *Client) CreateExpense(ctx context.Context, ledgerID int, expense models.Expense) error {
+	_, err := c.do(ctx, http.MethodPost, "/expenses", ledgerQuery(ledgerID), expense, nil)
+	return err
+}
+
+func (c *Client) UpdateExpense(ctx context.Context, ledgerID int, expense models.Expense) error {
+	_, err := c.do(ctx, http.MethodPut, fmt.Sprintf("/expenses/%d", expense.ID), ledgerQuery(ledgerID), expense, nil)
+	return err
+}
+
+func (c *Client) DeleteExpense(ctx context.Context, ledgerID, expenseID int) error {
+	_, err := c.do(ctx, http.MethodDelete, fmt.Sprintf("/expenses/%d", expenseID), ledgerQuery(ledgerID), nil, nil)
+	return err
+}
+
+// --------------------------- Спільні журнали та розрахунки ---------------------------
+
+func (c *Client) ListLedgers(ctx context.Context) ([]models.Ledger, error) {
+	var ledgers []models.Ledger
+	_, err := c.do(ctx, http.MethodGet, "/ledgers", nil, nil, &ledgers)
+	return ledgers, err
+}
+
+func (c *Client) CreateLedger(ctx context.Context, name string) (models.Ledger, error) {
+	var ledger models.Ledger
+	_, err := c.do(ctx, http.MethodPost, "/ledgers", nil, models.Ledger{Name: name}, &ledger)
+	return ledger, err
+}
+
+func (c *Client) ListLedgerMembers(ctx context.Context, ledgerID int) ([]models.LedgerMember, error) {
+	var members []models.LedgerMember
+	_, err := c.do(ctx, http.MethodGet, fmt.Sprintf("/ledgers/%d/members", ledgerID), nil, nil, &members)
+	return members, err
+}
+
+func (c *Client) InviteLedgerMember(ctx context.Context, ledgerID int, username, role string) (models.LedgerMember, error) {
+	invite := map[string]string{"username": username, "role": role}
+
+	var member models.LedgerMember
+	_, err := c.do(ctx, http.MethodPost, fmt.Sprintf("/ledgers/%d/members", ledgerID), nil, invite, &member)
+	return member, err
+}
+
+func (c *Client) RemoveLedgerMember(ctx context.Context, ledgerID, userID int) error {
+	_, err := c.do(ctx, http.MethodDelete, fmt.Sprintf("/ledgers/%d/members/%d", ledgerID, userID), nil, nil, nil)
+	return err
+}
+
+func (c *Client) LedgerBalances(ctx context.Context, ledgerID int) ([]models.Balance, error) {
+	var balances []models.Balance
+	_, err := c.do(ctx, http.MethodGet, fmt.Sprintf("/ledgers/%d/balances", ledgerID), nil, nil, &balances)
+	return balances, err
+}
+
+func (c *Client) SuggestSettlements(ctx context.Context, ledgerID int) ([]models.Balance, error) {
+	var payments []models.Balance
+	_, err := c.do(ctx, http.MethodGet, fmt.Sprintf("/ledgers/%d/settle-up", ledgerID), nil, nil, &payments)
+	return payments, err
+}
+
+func (c *Client) ListSettlements(ctx context.Context, ledgerID int) ([]models.Settlement, error) {
+	var settlements []models.Settlement
+	_, err := c.do(ctx, http.MethodGet, fmt.Sprintf("/ledgers/%d/settlements", ledgerID), nil, nil, &settlements)
+	return settlements, err
+}
+
+func (c *Client) RecordSettlement(ctx context.Context, ledgerID int, settlement models.Settlement) (models.Settlement, error) {
+	var recorded models.Settlement
+	_, err := c.do(ctx, http.MethodPost, fmt.Sprintf("/ledgers/%d/settlements", ledgerID), nil, settlement, &recorded)
+	return recorded, err
+}
+
+// --------------------------- Рахунки та перекази ---------------------------
+
+func (c *Client) ListAccounts(ctx context.Context) ([]models.Account, error) {
+	var accounts []models.Account
+	_, err := c.do(ctx, http.MethodGet, "/accounts", nil, nil, &accounts)
+	return accounts, err
+}
+
+func (c *Client) CreateAccount(ctx context.Context, account models.Account) (models.Account, error) {
+	var created models.Account
+	_, err := c.do(ctx, http.MethodPost, "/accounts", nil, account, &created)
+	return created, err
+}
+
+// AccountBalance повертає рахунок з балансом на кінець дня at (нульовий at - поточний баланс)
+func (c *Client) AccountBalance(ctx context.Context, accountID int, at time.Time) (models.Account, error) {
+	query := url.Values{}
+	dayQuery(query, "at", at)
+
+	var account models.Account
+	_, err := c.do(ctx, http.MethodGet, fmt.Sprintf("/accounts/%d/balance", accountID), query, nil, &account)
+	return account, err
+}
+
+// AccountHistory повертає рухи коштів між днями from і to включно (нульові межі не обмежують період)
+func (c *Client) AccountHistory(ctx context.Context, accountID int, from, to time.Time) ([]models.AccountEntry, error) {
+	query := url.Values{}
+	dayQuery(query, "from", from)
+	dayQuery(query, "to", to)
+
+	var entries []models.AccountEntry
+	_, err := c.do(ctx, http.MethodGet, fmt.Sprintf("/accounts/%d/history", accountID), query, nil, &entries)
+	return entries, err
+}
+
+func (c *Client) ListTransfers(ctx context.Context) ([]models.Transfer, error) {
+	var transfers []models.Transfer
+	_, err := c.do(ctx, http.MethodGet, "/transfers", nil, nil, &transfers)
+	return transfers, err
+}
+
+func (c *Client) CreateTransfer(ctx context.Context, transfer models.Transfer) (models.Transfer, error) {
+	var created models.Transfer
+	_, err := c.do(ctx, http.MethodPost, "/transfers", nil, transfer, &created)
+	return created, err
+}
+
+// --------------------------- Бюджети ---------------------------
+
+func (c *Client) ListBudgets(ctx context.Context) ([]models.Budget, error) {
+	var budgets []models.Budget
+	_, err := c.do(ctx, http.MethodGet, "/budgets", nil, nil, &budgets)
+	return budgets, err
+}
+
+func (c *Client) SetBudget(ctx context.Context, category string, amount int) (models.Budget, error) {
+	var budget models.Budget
+	_, err := c.do(ctx, http.MethodPut, "/budgets", nil, models.Budget{Category: category, Amount: amount}, &budget)
+	return budget, err
+}
+
+func (c *Client) DeleteBudget(ctx context.Context, category string) error {
+	_, err := c.do(ctx, http.MethodDelete, "/budgets/"+url.PathEscape(category), nil, nil, nil)
+	return err
+}
+
+// --------------------------- Звіти ---------------------------
+
+// TimeSeriesQuery - параметри динаміки витрат; нульові значення означають значення сервера за замовчуванням
+type TimeSeriesQuery struct {
+	Interval string // day, week або month
+	From     time.Time
+	To       time.Time // включно
+	Category string
+	Window   int
+}
+
+func (c *Client) TimeSeries(ctx context.Context, params TimeSeriesQuery) (models.TimeSeries, error) {
+	query := url.Values{}
+	if params.Interval != "" {
+		query.Set("interval", params.Interval)
+	}
+	dayQuery(query, "from", params.From)
+	dayQuery(query, "to", params.To)
+	if params.Category != "" {
+		query.Set("category", params.Category)
+	}
+	if params.Window != 0 {
+		query.Set("window", fmt.Sprint(params.Window))
+	}
+
+	var series models.TimeSeries
+	_, err := c.do(ctx, http.MethodGet, "/reports/timeseries", query, nil, &series)
+	return series, err
+}
+
+// CategoryTotals повертає витрати за категоріями між днями from і to включно (за замовчуванням - поточний місяць)
+func (c *Client) CategoryTotals(ctx context.Context, from, to time.Time) (models.CategoryReport, error) {
+	query := url.Values{}
+	dayQuery(query, "from", from)
+	dayQuery(query, "to", to)
+
+	var report models.CategoryReport
+	_, err := c.do(ctx, http.MethodGet, "/reports/categories", query, nil, &report)
+	return report, err
+}
+
+func (c *Client) BudgetProgress(ctx context.Context, month time.Time) ([]models.BudgetProgress, error) {
+	query := url.Values{}
+	monthQuery(query, month)
+
+	var progress []models.BudgetProgress
+	_, err := c.do(ctx, http.MethodGet, "/reports/budgets", query, nil, &progress)
+	return progress, err
+}
+
+// ChartQuery - параметри SVG-діаграми: Type - categories, monthly або budgets
+type ChartQuery struct {
+	Type     string
+	From     time.Time
+	To       time.Time
+	Month    time.Time
+	Category string
+}
+
+func (c *Client) Chart(ctx context.Context, params ChartQuery) ([]byte, error) {
+	query := url.Values{}
+	if params.Type != "" {
+		query.Set("type", params.Type)
+	}
+	dayQuery(query, "from", params.From)
+	dayQuery(query, "to", params.To)
+	monthQuery(query, params.Month)
+	if params.Category != "" {
+		query.Set("category", params.Category)
+	}
+
+	var svg []byte
+	_, err := c.do(ctx, http.MethodGet, "/reports/chart.svg", query, nil, &svg)
+	return svg, err
+}
+
+// Statement повертає місячну виписку у форматі PDF
+func (c *Client) Statement(ctx context.Context, month time.Time) ([]byte, error) {
+	query := url.Values{}
+	monthQuery(query, month)
+
+	var document []byte
+	_, err := c.do(ctx, http.MethodGet, "/reports/statement.pdf", query, nil, &document)
+	return document, err
+}
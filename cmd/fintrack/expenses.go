@@ -0,0 +1,388 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ChomuCake/uni-golang-labs/client"
+	"github.com/ChomuCake/uni-golang-labs/models"
+)
+
+var csvHeader = []string{"id", "date", "category", "amount", "kind", "account_id"}
+
+// parseDate розбирає дату з командного рядка так само, як її приймає API (RFC 3339 або YYYY-MM-DD)
+func parseDate(value string) (time.Time, bool, error) {
+	date, err := models.ParseDate(value)
+	return date, len(value) == len(models.DateLayout), err
+}
+
+func (a *app) add(ctx context.Context, args []string) error {
+	fs := a.flags("add")
+	category := fs.String("category", "", "expense category")
+	amount := fs.Int("amount", 0, "amount")
+	date := fs.String("date", "", "date (YYYY-MM-DD or RFC 3339), now by default")
+	kind := fs.String("kind", models.KindExpense, "expense or income")
+	account := fs.Int("account", 0, "account ID, the first account by default")
+	ledger := fs.Int("ledger", 0, "shared ledger ID")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	expense := models.Expense{Category: *category, Amount: *amount, Kind: *kind, AccountID: *account}
+	var err error
+	expense.Date, expense.DateOnly, err = parseDate(*date)
+	if err != nil {
+		return err
+	}
+
+	c, err := a.client()
+	if err != nil {
+		return err
+	}
+
+	err = c.CreateExpense(ctx, *ledger, expense)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(a.stdout, "Expense added")
+	return nil
+}
+
+// list показує витрати; -period фільтрує на сервері, -category, -from і -to - на боці клієнта
+func (a *app) list(ctx context.Context, args []string) error {
+	fs := a.flags("list")
+	period := fs.String("period", "all", "day, week, month, year or all")
+	category := fs.String("category", "", "only this category")
+	from := fs.String("from", "", "first day (YYYY-MM-DD)")
+	to := fs.String("to", "", "last day (YYYY-MM-DD), inclusive")
+	ledger := fs.Int("ledger", 0, "shared ledger ID")
+	output := fs.String("o", "table", "output format: table or json")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	p := printer{out: a.stdout, format: *output}
+	if err := p.validate(); err != nil {
+		return err
+	}
+
+	fromDate, _, err := parseDate(*from)
+	if err != nil {
+		return err
+	}
+	toDate, _, err := parseDate(*to)
+	if err != nil {
+		return err
+	}
+
+	c, err := a.client()
+	if err != nil {
+		return err
+	}
+
+	expenses, err := c.ListExpenses(ctx, client.ExpenseFilter{LedgerID: *ledger, Sort: *period})
+	if err != nil {
+		return err
+	}
+
+	filtered := []models.Expense{}
+	total := 0
+	for _, expense := range expenses {
+		if *category != "" && !strings.EqualFold(expense.Category, *category) {
+			continue
+		}
+		if !fromDate.IsZero() && expense.Date.Before(fromDate) {
+			continue
+		}
+		if !toDate.IsZero() && !expense.Date.Before(toDate.AddDate(0, 0, 1)) {
+			continue
+		}
+		filtered = append(filtered, expense)
+		if expense.Kind != models.KindIncome {
+			total += expense.Amount
+		}
+	}
+
+	rows := make([][]string, 0, len(filtered)+1)
+	for _, expense := range filtered {
+		rows = append(rows, []string{strconv.Itoa(expense.ID), expense.Date.Local().Format("2006-01-02 15:04"),
+			expense.Category, strconv.Itoa(expense.Amount), expense.Kind})
+	}
+	rows = append(rows, []string{"", "", "Total spent", strconv.Itoa(total), ""})
+
+	return p.print(filtered, []string{"ID", "DATE", "CATEGORY", "AMOUNT", "KIND"}, rows)
+}
+
+// edit змінює лише ті поля, прапорці яких вказано
+func (a *app) edit(ctx context.Context, args []string) error {
+	fs := a.flags("edit")
+	id := fs.Int("id", 0, "expense ID")
+	category := fs.String("category", "", "new category")
+	amount := fs.Int("amount", 0, "new amount")
+	date := fs.String("date", "", "new date (YYYY-MM-DD or RFC 3339)")
+	kind := fs.String("kind", "", "expense or income")
+	account := fs.Int("account", 0, "new account ID")
+	ledger := fs.Int("ledger", 0, "shared ledger ID")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *id == 0 {
+		return errors.New("expense ID is required (-id)")
+	}
+
+	c, err := a.client()
+	if err != nil {
+		return err
+	}
+
+	// API не має запиту однієї витрати, тому поточні значення беруться зі списку
+	expenses, err := c.ListExpenses(ctx, client.ExpenseFilter{LedgerID: *ledger, Sort: "all"})
+	if err != nil {
+		return err
+	}
+
+	var expense *models.Expense
+	for i := range expenses {
+		if expenses[i].ID == *id {
+			expense = &expenses[i]
+		}
+	}
+	if expense == nil {
+		return fmt.Errorf("expense %d not found", *id)
+	}
+
+	// Незмінена дата не передається, щоб сервер залишив її як є
+	expense.Date = time.Time{}
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "category":
+			expense.Category = *category
+		case "amount":
+			expense.Amount = *amount
+		case "kind":
+			expense.Kind = *kind
+		case "account":
+			expense.AccountID = *account
+		case "date":
+			expense.Date, expense.DateOnly, err = parseDate(*date)
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	err = c.UpdateExpense(ctx, *ledger, *expense)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(a.stdout, "Expense %d updated\n", *id)
+	return nil
+}
+
+func (a *app) remove(ctx context.Context, args []string) error {
+	fs := a.flags("rm")
+	ledger := fs.Int("ledger", 0, "shared ledger ID")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return errors.New("usage: fintrack rm [-ledger ID] EXPENSE_ID...")
+	}
+
+	c, err := a.client()
+	if err != nil {
+		return err
+	}
+
+	for _, rawID := range fs.Args() {
+		id, err := strconv.Atoi(rawID)
+		if err != nil {
+			return fmt.Errorf("invalid expense ID %q", rawID)
+		}
+
+		err = c.DeleteExpense(ctx, *ledger, id)
+		if err != nil {
+			return fmt.Errorf("delete %d: %w", id, err)
+		}
+		fmt.Fprintf(a.stdout, "Expense %d deleted\n", id)
+	}
+
+	return nil
+}
+
+// importExpenses додає витрати з CSV (колонки date, category, amount, а також необов'язкові kind
+// і account_id) або JSON-масиву у форматі API; формат визначається розширенням файлу
+func (a *app) importExpenses(ctx context.Context, args []string) error {
+	fs := a.flags("import")
+	file := fs.String("file", "", "CSV or JSON file, - for stdin")
+	format := fs.String("format", "", "csv or json, by file extension by default")
+	ledger := fs.Int("ledger", 0, "shared ledger ID")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *file == "" {
+		return errors.New("file is required (-file)")
+	}
+
+	var in io.Reader = a.stdin
+	if *file != "-" {
+		f, err := os.Open(*file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+
+	if *format == "" {
+		*format = strings.TrimPrefix(strings.ToLower(filepath.Ext(*file)), ".")
+	}
+
+	var expenses []models.Expense
+	var err error
+	switch *format {
+	case "csv":
+		expenses, err = readCSV(in)
+	case "json":
+		err = json.NewDecoder(in).Decode(&expenses)
+	default:
+		return fmt.Errorf("unknown import format %q (use csv or json)", *format)
+	}
+	if err != nil {
+		return err
+	}
+
+	c, err := a.client()
+	if err != nil {
+		return err
+	}
+
+	for i, expense := range expenses {
+		expense.ID = 0
+		err = c.CreateExpense(ctx, *ledger, expense)
+		if err != nil {
+			return fmt.Errorf("record %d (imported %d of %d): %w", i+1, i, len(expenses), err)
+		}
+	}
+
+	fmt.Fprintf(a.stdout, "Imported %d expenses\n", len(expenses))
+	return nil
+}
+
+func readCSV(in io.Reader) ([]models.Expense, error) {
+	reader := csv.NewReader(in)
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	columns := map[string]int{}
+	for i, name := range records[0] {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"date", "category", "amount"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("CSV header must contain %q", required)
+		}
+	}
+
+	field := func(record []string, name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	expenses := make([]models.Expense, 0, len(records)-1)
+	for line, record := range records[1:] {
+		expense := models.Expense{Category: field(record, "category"), Kind: field(record, "kind")}
+
+		expense.Date, expense.DateOnly, err = parseDate(field(record, "date"))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line+2, err)
+		}
+
+		expense.Amount, err = strconv.Atoi(field(record, "amount"))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid amount %q", line+2, field(record, "amount"))
+		}
+
+		if account := field(record, "account_id"); account != "" {
+			expense.AccountID, err = strconv.Atoi(account)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid account_id %q", line+2, account)
+			}
+		}
+
+		expenses = append(expenses, expense)
+	}
+
+	return expenses, nil
+}
+
+func (a *app) exportExpenses(ctx context.Context, args []string) error {
+	fs := a.flags("export")
+	format := fs.String("format", "csv", "csv or json")
+	file := fs.String("file", "", "output file, stdout by default")
+	ledger := fs.Int("ledger", 0, "shared ledger ID")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *format != "csv" && *format != "json" {
+		return fmt.Errorf("unknown export format %q (use csv or json)", *format)
+	}
+
+	c, err := a.client()
+	if err != nil {
+		return err
+	}
+
+	expenses, err := c.ListExpenses(ctx, client.ExpenseFilter{LedgerID: *ledger, Sort: "all"})
+	if err != nil {
+		return err
+	}
+	sort.SliceStable(expenses, func(i, j int) bool { return expenses[i].Date.Before(expenses[j].Date) })
+
+	out := a.stdout
+	if *file != "" {
+		f, err := os.OpenFile(*file, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+
+	if *format == "json" {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(expenses)
+	}
+
+	writer := csv.NewWriter(out)
+	_ = writer.Write(csvHeader)
+	for _, expense := range expenses {
+		_ = writer.Write([]string{strconv.Itoa(expense.ID), expense.Date.Format(models.DateTimeLayout), expense.Category,
+			strconv.Itoa(expense.Amount), expense.Kind, strconv.Itoa(expense.AccountID)})
+	}
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,223 @@
+// Команда fintrack - консольний клієнт трекера витрат, що працює через те саме HTTP API, що й веб-інтерфейс.
+//
+//	fintrack login -u john
+//	fintrack add -category Food -amount 120 -date 2024-03-01
+//	fintrack list -period month -o json
+//	fintrack report -type categories -from 2024-03-01 -to 2024-03-31
+//
+// Адреса сервера й токен зберігаються у <UserConfigDir>/fintrack/config.json з правами 0600
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+
+	"github.com/ChomuCake/uni-golang-labs/client"
+)
+
+const usage = `Usage: fintrack <command> [flags]
+
+Commands:
+  login    log in and cache the token
+  logout   forget the cached token
+  add      add an expense or income
+  list     list expenses (filters: -period, -category, -from, -to)
+  edit     change an expense
+  rm       delete expenses by ID
+  import   import expenses from CSV or JSON
+  export   export expenses as CSV or JSON
+  report   show time series, category totals or budget progress
+
+Run "fintrack <command> -h" for command flags.
+`
+
+// app містить усе, від чого залежать команди, щоб їх можна було запускати в тестах
+type app struct {
+	configPath string
+	stdin      io.Reader
+	stdout     io.Writer
+	stderr     io.Writer
+}
+
+type command func(a *app, ctx context.Context, args []string) error
+
+var commands = map[string]command{
+	"login":  (*app).login,
+	"logout": (*app).logout,
+	"add":    (*app).add,
+	"list":   (*app).list,
+	"edit":   (*app).edit,
+	"rm":     (*app).remove,
+	"import": (*app).importExpenses,
+	"export": (*app).exportExpenses,
+	"report": (*app).report,
+}
+
+func main() {
+	path, err := configPath()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "fintrack:", err)
+		os.Exit(1)
+	}
+
+	a := &app{configPath: path, stdin: os.Stdin, stdout: os.Stdout, stderr: os.Stderr}
+	os.Exit(a.run(context.Background(), os.Args[1:]))
+}
+
+func (a *app) run(ctx context.Context, args []string) int {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "help" {
+		fmt.Fprint(a.stderr, usage)
+		return 2
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(a.stderr, "fintrack: unknown command %q\n\n%s", args[0], usage)
+		return 2
+	}
+
+	err := cmd(a, ctx, args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		return 2
+	}
+	if err != nil {
+		fmt.Fprintln(a.stderr, "fintrack:", err)
+
+		var problem *client.Problem
+		if errors.As(err, &problem) && problem.Status == 401 {
+			fmt.Fprintln(a.stderr, `the session has expired, run "fintrack login"`)
+		}
+		return 1
+	}
+
+	return 0
+}
+
+func (a *app) flags(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet("fintrack "+name, flag.ContinueOnError)
+	fs.SetOutput(a.stderr)
+	return fs
+}
+
+// client повертає клієнт API з адресою й токеном із конфігурації
+func (a *app) client() (*client.Client, error) {
+	cfg, err := loadConfig(a.configPath)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Token == "" {
+		return nil, errors.New(`not logged in, run "fintrack login"`)
+	}
+
+	c := client.New(cfg.Server)
+	c.Token = cfg.Token
+	return c, nil
+}
+
+func (a *app) login(ctx context.Context, args []string) error {
+	fs := a.flags("login")
+	username := fs.String("u", "", "username")
+	password := fs.String("p", "", "password; insecure, it stays in shell history and is visible in ps (prefer FINTRACK_PASSWORD or the prompt)")
+	code := fs.String("code", "", "two-factor code or recovery code (read from stdin when required and omitted)")
+	server := fs.String("server", "", "API address, e.g. "+defaultServer)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	cfg, err := loadConfig(a.configPath)
+	if err != nil {
+		return err
+	}
+	if *server != "" {
+		cfg.Server = *server
+	}
+	if *username == "" {
+		*username = cfg.Username
+	}
+	if *username == "" {
+		return errors.New("username is required (-u)")
+	}
+
+	if *password == "" {
+		*password = os.Getenv("FINTRACK_PASSWORD")
+	}
+	// Пароль і код 2FA читаються з одного буфера, щоб не загубити другий рядок stdin
+	in := bufio.NewReader(a.stdin)
+	if *password == "" {
+		*password, err = a.readPassword(in)
+		if err != nil {
+			return err
+		}
+	}
+
+	c := client.New(cfg.Server)
+	token, err := c.Login(ctx, *username, *password)
+	var twoFactor *client.TwoFactorRequired
+	if errors.As(err, &twoFactor) {
+		if *code == "" {
+			fmt.Fprint(a.stderr, "Authentication code: ")
+			line, err := in.ReadString('\n')
+			if err != nil && line == "" {
+				return fmt.Errorf("read code: %w", err)
+			}
+			*code = strings.TrimSpace(line)
+		}
+		token, err = c.LoginTwoFactor(ctx, twoFactor.ChallengeToken, *code)
+	}
+	if err != nil {
+		return err
+	}
+
+	cfg.Username = *username
+	cfg.Token = token
+	err = saveConfig(a.configPath, cfg)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(a.stdout, "Logged in to %s as %s\n", cfg.Server, *username)
+	return nil
+}
+
+// readPassword питає пароль: у терміналі - без луни, щоб він не лишився на екрані й у scrollback,
+// з перенаправленого stdin - рядком
+func (a *app) readPassword(in *bufio.Reader) (string, error) {
+	fmt.Fprint(a.stderr, "Password: ")
+
+	if f, ok := a.stdin.(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+		password, err := term.ReadPassword(int(f.Fd()))
+		fmt.Fprintln(a.stderr)
+		if err != nil {
+			return "", fmt.Errorf("read password: %w", err)
+		}
+		return string(password), nil
+	}
+
+	line, err := in.ReadString('\n')
+	if err != nil && line == "" {
+		return "", fmt.Errorf("read password: %w", err)
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+func (a *app) logout(ctx context.Context, args []string) error {
+	if err := a.flags("logout").Parse(args); err != nil {
+		return err
+	}
+
+	cfg, err := loadConfig(a.configPath)
+	if err != nil {
+		return err
+	}
+
+	cfg.Token = ""
+	return saveConfig(a.configPath, cfg)
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ChomuCake/uni-golang-labs/models"
+)
+
+// newTestApp повертає застосунок з конфігурацією у тимчасовому каталозі
+func newTestApp(t *testing.T, stdin string) (*app, *bytes.Buffer, *bytes.Buffer) {
+	var stdout, stderr bytes.Buffer
+	a := &app{
+		configPath: filepath.Join(t.TempDir(), "fintrack", "config.json"),
+		stdin:      strings.NewReader(stdin),
+		stdout:     &stdout,
+		stderr:     &stderr,
+	}
+	return a, &stdout, &stderr
+}
+
+// fakeAPI імітує сервер: приймає вхід для john/secret123 (і для mary/secret123 з кодом 2FA 123456)
+// та зберігає додані витрати
+func fakeAPI(t *testing.T) (*httptest.Server, *[]models.Expense) {
+	var expenses []models.Expense
+	mux := http.NewServeMux()
+	mux.HandleFunc("/login", func(w http.ResponseWriter, r *http.Request) {
+		var user models.User
+		_ = json.NewDecoder(r.Body).Decode(&user)
+		if user.Username == "mary" && user.Password == "secret123" {
+			json.NewEncoder(w).Encode(models.TwoFactorChallenge{Required: true, ChallengeToken: "challenge-1"})
+			return
+		}
+		if user.Username != "john" || user.Password != "secret123" {
+			w.Header().Set("Content-Type", "application/problem+json")
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"status":401,"title":"Unauthorized","code":"invalid_credentials"}`))
+			return
+		}
+		w.Header().Set("Authorization", "token-1")
+	})
+	mux.HandleFunc("/login/2fa", func(w http.ResponseWriter, r *http.Request) {
+		var login models.TwoFactorLogin
+		_ = json.NewDecoder(r.Body).Decode(&login)
+		if login.ChallengeToken != "challenge-1" || login.Code != "123456" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Authorization", "token-1")
+	})
+	mux.HandleFunc("/expenses", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token-1" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.Method == http.MethodPost {
+			var expense models.Expense
+			err := json.NewDecoder(r.Body).Decode(&expense)
+			if err != nil {
+				t.Errorf("Received invalid expense body: %v", err)
+			}
+			expense.ID = len(expenses) + 1
+			expenses = append(expenses, expense)
+			w.WriteHeader(http.StatusCreated)
+			return
+		}
+		json.NewEncoder(w).Encode(expenses)
+	})
+	return httptest.NewServer(mux), &expenses
+}
+
+func TestLogin_CachesTokenPrivately(t *testing.T) {
+	// Arrange
+	server, _ := fakeAPI(t)
+	defer server.Close()
+	a, stdout, stderr := newTestApp(t, "secret123\n")
+
+	// Act
+	code := a.run(context.Background(), []string{"login", "-server", server.URL, "-u", "john"})
+
+	// Assert
+	if code != 0 {
+		t.Fatalf("Received exit code %d, expected 0: %s", code, stderr)
+	}
+	if !strings.Contains(stdout.String(), "Logged in") {
+		t.Errorf("Received incorrect output: %q", stdout)
+	}
+
+	info, err := os.Stat(a.configPath)
+	if err != nil {
+		t.Fatalf("Received an error: received %v, expected %v", err, nil)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Errorf("Received incorrect config permissions: received %v, expected %v", info.Mode().Perm(), os.FileMode(0o600))
+	}
+
+	cfg, _ := loadConfig(a.configPath)
+	if cfg.Token != "token-1" || cfg.Server != server.URL || cfg.Username != "john" {
+		t.Errorf("Received incorrect config: %+v", cfg)
+	}
+}
+
+func TestLogin_InvalidCredentials(t *testing.T) {
+	server, _ := fakeAPI(t)
+	defer server.Close()
+	a, _, stderr := newTestApp(t, "")
+
+	code := a.run(context.Background(), []string{"login", "-server", server.URL, "-u", "john", "-p", "wrong"})
+
+	if code != 1 || !strings.Contains(stderr.String(), "401") {
+		t.Errorf("Received exit code %d and output %q, expected an unauthorized error", code, stderr)
+	}
+}
+
+func TestLogin_TwoFactor(t *testing.T) {
+	// Arrange: пароль і код вводяться двома рядками stdin
+	server, _ := fakeAPI(t)
+	defer server.Close()
+	a, _, stderr := newTestApp(t, "secret123\n123456\n")
+
+	// Act
+	code := a.run(context.Background(), []string{"login", "-server", server.URL, "-u", "mary"})
+
+	// Assert
+	if code != 0 || !strings.Contains(stderr.String(), "Authentication code") {
+		t.Fatalf("Received exit code %d and output %q, expected a code prompt and success", code, stderr)
+	}
+	cfg, _ := loadConfig(a.configPath)
+	if cfg.Token != "token-1" {
+		t.Errorf("Received incorrect token: received %q, expected %q", cfg.Token, "token-1")
+	}
+}
+
+func TestImportAndList(t *testing.T) {
+	// Arrange
+	server, expenses := fakeAPI(t)
+	defer server.Close()
+	a, stdout, stderr := newTestApp(t, "date,category,amount,kind\n2024-03-01,Food,120,\n2024-03-02T10:00:00Z,Salary,1000,income\n")
+	err := saveConfig(a.configPath, config{Server: server.URL, Token: "token-1"})
+	if err != nil {
+		t.Fatalf("Received an error: received %v, expected %v", err, nil)
+	}
+
+	// Act
+	code := a.run(context.Background(), []string{"import", "-file", "-", "-format", "csv"})
+	if code != 0 {
+		t.Fatalf("Received exit code %d, expected 0: %s", code, stderr)
+	}
+	stdout.Reset()
+	code = a.run(context.Background(), []string{"list", "-category", "food", "-o", "json"})
+
+	// Assert
+	if code != 0 {
+		t.Fatalf("Received exit code %d, expected 0: %s", code, stderr)
+	}
+	if len(*expenses) != 2 || !(*expenses)[0].DateOnly || (*expenses)[1].Kind != models.KindIncome {
+		t.Errorf("Received incorrect imported expenses: %+v", *expenses)
+	}
+
+	var listed []models.Expense
+	err = json.Unmarshal(stdout.Bytes(), &listed)
+	if err != nil || len(listed) != 1 || listed[0].Category != "Food" {
+		t.Errorf("Received incorrect list output: %s", stdout)
+	}
+}
+
+func TestReadCSV_InvalidAmount(t *testing.T) {
+	_, err := readCSV(strings.NewReader("date,category,amount\n2024-03-01,Food,ten\n"))
+
+	if err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("Received incorrect error: received %v, expected error on line 2", err)
+	}
+}
+
+func TestRun_NotLoggedIn(t *testing.T) {
+	a, _, stderr := newTestApp(t, "")
+
+	code := a.run(context.Background(), []string{"list"})
+
+	if code != 1 || !strings.Contains(stderr.String(), "not logged in") {
+		t.Errorf("Received exit code %d and output %q, expected login hint", code, stderr)
+	}
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// printer виводить результат таблицею або JSON залежно від прапорця -o
+type printer struct {
+	out    io.Writer
+	format string
+}
+
+func (p printer) validate() error {
+	if p.format != "table" && p.format != "json" {
+		return fmt.Errorf("unknown output format %q (use table or json)", p.format)
+	}
+	return nil
+}
+
+// print виводить data як JSON або, для таблиці, рядки rows під заголовком header
+func (p printer) print(data interface{}, header []string, rows [][]string) error {
+	if p.format == "json" {
+		encoder := json.NewEncoder(p.out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(data)
+	}
+
+	w := tabwriter.NewWriter(p.out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, strings.Join(header, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	return w.Flush()
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/ChomuCake/uni-golang-labs/client"
+)
+
+// report показує один зі звітів API: timeseries, categories або budgets
+func (a *app) report(ctx context.Context, args []string) error {
+	fs := a.flags("report")
+	reportType := fs.String("type", "timeseries", "timeseries, categories or budgets")
+	interval := fs.String("interval", "month", "day, week or month (timeseries)")
+	from := fs.String("from", "", "first day (YYYY-MM-DD)")
+	to := fs.String("to", "", "last day (YYYY-MM-DD), inclusive")
+	category := fs.String("category", "", "only this category (timeseries)")
+	window := fs.Int("window", 0, "moving average window (timeseries)")
+	month := fs.String("month", "", "month YYYY-MM (budgets)")
+	output := fs.String("o", "table", "output format: table or json")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	p := printer{out: a.stdout, format: *output}
+	if err := p.validate(); err != nil {
+		return err
+	}
+
+	fromDate, _, err := parseDate(*from)
+	if err != nil {
+		return err
+	}
+	toDate, _, err := parseDate(*to)
+	if err != nil {
+		return err
+	}
+
+	var monthDate time.Time
+	if *month != "" {
+		monthDate, err = time.Parse("2006-01", *month)
+		if err != nil {
+			return fmt.Errorf("month must be in YYYY-MM format, got %s", *month)
+		}
+	}
+
+	c, err := a.client()
+	if err != nil {
+		return err
+	}
+
+	switch *reportType {
+	case "timeseries":
+		series, err := c.TimeSeries(ctx, client.TimeSeriesQuery{Interval: *interval, From: fromDate, To: toDate, Category: *category, Window: *window})
+		if err != nil {
+			return err
+		}
+
+		rows := make([][]string, 0, len(series.Buckets))
+		for _, bucket := range series.Buckets {
+			change := "-"
+			if bucket.ChangePct != nil {
+				change = fmt.Sprintf("%+.2f%%", *bucket.ChangePct)
+			}
+			rows = append(rows, []string{bucket.Start.Format("2006-01-02"), strconv.Itoa(bucket.Total),
+				strconv.Itoa(bucket.Change), change, fmt.Sprintf("%.2f", bucket.MovingAverage)})
+		}
+		return p.print(series, []string{"START", "TOTAL", "CHANGE", "CHANGE %", "MOVING AVG"}, rows)
+
+	case "categories":
+		report, err := c.CategoryTotals(ctx, fromDate, toDate)
+		if err != nil {
+			return err
+		}
+
+		rows := make([][]string, 0, len(report.Categories)+1)
+		for _, total := range report.Categories {
+			rows = append(rows, []string{total.Category, strconv.Itoa(total.Total), fmt.Sprintf("%.2f%%", total.Share)})
+		}
+		rows = append(rows, []string{"Total", strconv.Itoa(report.Total), ""})
+		return p.print(report, []string{"CATEGORY", "TOTAL", "SHARE"}, rows)
+
+	case "budgets":
+		progress, err := c.BudgetProgress(ctx, monthDate)
+		if err != nil {
+			return err
+		}
+
+		rows := make([][]string, 0, len(progress))
+		for _, budget := range progress {
+			rows = append(rows, []string{budget.Category, strconv.Itoa(budget.Budget), strconv.Itoa(budget.Spent),
+				strconv.Itoa(budget.Remaining), fmt.Sprintf("%.2f%%", budget.Percent)})
+		}
+		return p.print(progress, []string{"CATEGORY", "BUDGET", "SPENT", "REMAINING", "USED"}, rows)
+	}
+
+	return fmt.Errorf("unknown report type %q (use timeseries, categories or budgets)", *reportType)
+}
@@ -0,0 +1,277 @@
+// Package config читає налаштування сервера зі змінних середовища з префіксом FINTRACK_;
+// не вказані змінні отримують значення за замовчуванням, придатні для локального запуску
+package config
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Config struct {
+	HTTPAddr          string
+	ReadTimeout       time.Duration
+	ReadHeaderTimeout time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+	ShutdownTimeout   time.Duration // скільки чекати завершення запитів, що обробляються, при зупинці
+
+	// TLS вмикається, якщо вказано обидва файли
+	TLSCertFile string
+	TLSKeyFile  string
+
+	// Прапорець Secure для cookie браузерної сесії; вимикається лише для локальної розробки по HTTP,
+	// бо за проксі, що завершує TLS, сервер не бачить, що браузер звертався по HTTPS
+	CookieSecure bool
+
+	DatabaseDSN string
+
+	// Пул з'єднань з базою даних
+	DBMaxOpenConns    int
+	DBMaxIdleConns    int
+	DBConnMaxLifetime time.Duration
+	DBConnMaxIdleTime time.Duration
+
+	// Перевірка з'єднання під час запуску: кількість спроб і перша пауза між ними (далі подвоюється)
+	DBConnectAttempts int
+	DBConnectBackoff  time.Duration
+
+	// Дедлайн для запитів до бази в межах одного HTTP-запиту; 0 - без обмеження
+	DBRequestTimeout time.Duration
+
+	// Журналювання: мінімальний рівень (debug, info, warn, error) і формат (json або text)
+	LogLevel  slog.Level
+	LogFormat string
+
+	// Трасування: експорт спанів none, stdout або otlp (OTLP/HTTP на TraceOTLPEndpoint)
+	TraceExporter     string
+	TraceOTLPEndpoint string
+	TraceOTLPInsecure bool
+
+	// Обмеження частоти входу: сховище відер memory або redis (спільне для кількох екземплярів)
+	// і token bucket на IP-адресу та на ім'я користувача; нульовий burst вимикає правило
+	RateLimitBackend       string
+	RedisAddr              string
+	LoginRateIPBurst       int
+	LoginRateIPEvery       time.Duration
+	LoginRateUsernameBurst int
+	LoginRateUsernameEvery time.Duration
+
+	// Листи (відновлення пароля): log - лише в журнал, file - файли .eml у MailDir, smtp - через SMTPAddr
+	MailBackend  string
+	MailFrom     string
+	MailDir      string
+	SMTPAddr     string
+	SMTPUsername string
+	SMTPPassword string
+
+	// Сторінка, на яку веде посилання з листа відновлення пароля, і скільки це посилання діє
+	PasswordResetURL string
+	PasswordResetTTL time.Duration
+
+	// Вхід через OpenID Connect вмикається, якщо вказано постачальника (issuer); RedirectURL -
+	// адреса GET /oidc/callback цього сервера, зареєстрована в постачальника разом з ClientID
+	OIDCIssuer       string
+	OIDCClientID     string
+	OIDCClientSecret string
+	OIDCRedirectURL  string
+	OIDCScopes       []string
+}
+
+// OIDCEnabled повідомляє, чи доступний вхід через зовнішнього постачальника
+func (c Config) OIDCEnabled() bool {
+	return c.OIDCIssuer != ""
+}
+
+// TLSEnabled повідомляє, чи сервер має приймати HTTPS-з'єднання
+func (c Config) TLSEnabled() bool {
+	return c.TLSCertFile != ""
+}
+
+// Load читає конфігурацію із середовища процесу
+func Load() (Config, error) {
+	return load(os.LookupEnv)
+}
+
+// envReader накопичує помилки розбору, щоб повідомити про всі неправильні змінні разом
+type envReader struct {
+	lookup func(string) (string, bool)
+	errs   []string
+}
+
+func (r *envReader) string(name, fallback string) string {
+	if value, ok := r.lookup(name); ok && value != "" {
+		return value
+	}
+	return fallback
+}
+
+func (r *envReader) duration(name string, fallback time.Duration) time.Duration {
+	value, ok := r.lookup(name)
+	if !ok || value == "" {
+		return fallback
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		r.errs = append(r.errs, fmt.Sprintf("%s: invalid duration %q", name, value))
+		return fallback
+	}
+	return d
+}
+
+func (r *envReader) int(name string, fallback int) int {
+	value, ok := r.lookup(name)
+	if !ok || value == "" {
+		return fallback
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		r.errs = append(r.errs, fmt.Sprintf("%s: invalid number %q", name, value))
+		return fallback
+	}
+	return n
+}
+
+func (r *envReader) bool(name string, fallback bool) bool {
+	value, ok := r.lookup(name)
+	if !ok || value == "" {
+		return fallback
+	}
+
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		r.errs = append(r.errs, fmt.Sprintf("%s: invalid boolean %q", name, value))
+		return fallback
+	}
+	return b
+}
+
+// list розбирає значення, розділені комами, пропускаючи порожні
+func (r *envReader) list(name string, fallback []string) []string {
+	value, ok := r.lookup(name)
+	if !ok || value == "" {
+		return fallback
+	}
+
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func (r *envReader) level(name string, fallback slog.Level) slog.Level {
+	value, ok := r.lookup(name)
+	if !ok || value == "" {
+		return fallback
+	}
+
+	var level slog.Level
+	err := level.UnmarshalText([]byte(value))
+	if err != nil {
+		r.errs = append(r.errs, fmt.Sprintf("%s: invalid log level %q", name, value))
+		return fallback
+	}
+	return level
+}
+
+func load(lookup func(string) (string, bool)) (Config, error) {
+	r := &envReader{lookup: lookup}
+
+	cfg := Config{
+		HTTPAddr:          r.string("FINTRACK_HTTP_ADDR", ":8080"),
+		ReadTimeout:       r.duration("FINTRACK_HTTP_READ_TIMEOUT", 15*time.Second),
+		ReadHeaderTimeout: r.duration("FINTRACK_HTTP_READ_HEADER_TIMEOUT", 5*time.Second),
+		WriteTimeout:      r.duration("FINTRACK_HTTP_WRITE_TIMEOUT", 30*time.Second),
+		IdleTimeout:       r.duration("FINTRACK_HTTP_IDLE_TIMEOUT", 2*time.Minute),
+		ShutdownTimeout:   r.duration("FINTRACK_SHUTDOWN_TIMEOUT", 20*time.Second),
+		TLSCertFile:       r.string("FINTRACK_TLS_CERT_FILE", ""),
+		TLSKeyFile:        r.string("FINTRACK_TLS_KEY_FILE", ""),
+		CookieSecure:      r.bool("FINTRACK_COOKIE_SECURE", true),
+		DatabaseDSN:       r.string("FINTRACK_DB_DSN", "root:12345@tcp(localhost:3306)/test?parseTime=true"),
+		DBMaxOpenConns:    r.int("FINTRACK_DB_MAX_OPEN_CONNS", 25),
+		DBMaxIdleConns:    r.int("FINTRACK_DB_MAX_IDLE_CONNS", 25),
+		DBConnMaxLifetime: r.duration("FINTRACK_DB_CONN_MAX_LIFETIME", 5*time.Minute),
+		DBConnMaxIdleTime: r.duration("FINTRACK_DB_CONN_MAX_IDLE_TIME", time.Minute),
+		DBConnectAttempts: r.int("FINTRACK_DB_CONNECT_ATTEMPTS", 10),
+		DBConnectBackoff:  r.duration("FINTRACK_DB_CONNECT_BACKOFF", 500*time.Millisecond),
+		DBRequestTimeout:  r.duration("FINTRACK_DB_REQUEST_TIMEOUT", 10*time.Second),
+		LogLevel:          r.level("FINTRACK_LOG_LEVEL", slog.LevelInfo),
+		LogFormat:         r.string("FINTRACK_LOG_FORMAT", "json"),
+		TraceExporter:     r.string("FINTRACK_TRACE_EXPORTER", "none"),
+		TraceOTLPEndpoint: r.string("FINTRACK_TRACE_OTLP_ENDPOINT", "localhost:4318"),
+		TraceOTLPInsecure: r.bool("FINTRACK_TRACE_OTLP_INSECURE", true),
+
+		RateLimitBackend:       r.string("FINTRACK_RATE_LIMIT_BACKEND", "memory"),
+		RedisAddr:              r.string("FINTRACK_REDIS_ADDR", "localhost:6379"),
+		LoginRateIPBurst:       r.int("FINTRACK_LOGIN_RATE_IP_BURST", 20),
+		LoginRateIPEvery:       r.duration("FINTRACK_LOGIN_RATE_IP_EVERY", 3*time.Second),
+		LoginRateUsernameBurst: r.int("FINTRACK_LOGIN_RATE_USERNAME_BURST", 5),
+		LoginRateUsernameEvery: r.duration("FINTRACK_LOGIN_RATE_USERNAME_EVERY", 30*time.Second),
+
+		MailBackend:      r.string("FINTRACK_MAIL_BACKEND", "log"),
+		MailFrom:         r.string("FINTRACK_MAIL_FROM", "Finance Tracker <no-reply@localhost>"),
+		MailDir:          r.string("FINTRACK_MAIL_DIR", "mail"),
+		SMTPAddr:         r.string("FINTRACK_SMTP_ADDR", "localhost:25"),
+		SMTPUsername:     r.string("FINTRACK_SMTP_USERNAME", ""),
+		SMTPPassword:     r.string("FINTRACK_SMTP_PASSWORD", ""),
+		PasswordResetURL: r.string("FINTRACK_PASSWORD_RESET_URL", "http://localhost:8080/reset.html"),
+		PasswordResetTTL: r.duration("FINTRACK_PASSWORD_RESET_TTL", time.Hour),
+
+		OIDCIssuer:       r.string("FINTRACK_OIDC_ISSUER", ""),
+		OIDCClientID:     r.string("FINTRACK_OIDC_CLIENT_ID", ""),
+		OIDCClientSecret: r.string("FINTRACK_OIDC_CLIENT_SECRET", ""),
+		OIDCRedirectURL:  r.string("FINTRACK_OIDC_REDIRECT_URL", "http://localhost:8080/oidc/callback"),
+		OIDCScopes:       r.list("FINTRACK_OIDC_SCOPES", []string{"profile", "email"}),
+	}
+
+	if cfg.OIDCEnabled() && cfg.OIDCClientID == "" {
+		r.errs = append(r.errs, "FINTRACK_OIDC_CLIENT_ID is required when FINTRACK_OIDC_ISSUER is set")
+	}
+
+	switch cfg.MailBackend {
+	case "log", "file", "smtp":
+	default:
+		r.errs = append(r.errs, fmt.Sprintf("FINTRACK_MAIL_BACKEND: must be log, file or smtp, got %q", cfg.MailBackend))
+	}
+
+	if cfg.PasswordResetTTL == 0 {
+		r.errs = append(r.errs, "FINTRACK_PASSWORD_RESET_TTL must be positive")
+	}
+
+	if cfg.RateLimitBackend != "memory" && cfg.RateLimitBackend != "redis" {
+		r.errs = append(r.errs, fmt.Sprintf("FINTRACK_RATE_LIMIT_BACKEND: must be memory or redis, got %q", cfg.RateLimitBackend))
+	}
+
+	switch cfg.TraceExporter {
+	case "none", "stdout", "otlp":
+	default:
+		r.errs = append(r.errs, fmt.Sprintf("FINTRACK_TRACE_EXPORTER: must be none, stdout or otlp, got %q", cfg.TraceExporter))
+	}
+
+	if cfg.LogFormat != "json" && cfg.LogFormat != "text" {
+		r.errs = append(r.errs, fmt.Sprintf("FINTRACK_LOG_FORMAT: must be json or text, got %q", cfg.LogFormat))
+	}
+
+	if cfg.DBConnectAttempts == 0 {
+		r.errs = append(r.errs, "FINTRACK_DB_CONNECT_ATTEMPTS must be at least 1")
+	}
+
+	if (cfg.TLSCertFile == "") != (cfg.TLSKeyFile == "") {
+		r.errs = append(r.errs, "FINTRACK_TLS_CERT_FILE and FINTRACK_TLS_KEY_FILE must be set together")
+	}
+
+	if len(r.errs) > 0 {
+		return Config{}, errors.New("config: " + strings.Join(r.errs, "; "))
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,82 @@
+package config
+
+import (
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func env(values map[string]string) func(string) (string, bool) {
+	return func(name string) (string, bool) {
+		value, ok := values[name]
+		return value, ok
+	}
+}
+
+func TestLoad_Defaults(t *testing.T) {
+	// Act
+	cfg, err := load(env(nil))
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Received an error: received %v, expected %v", err, nil)
+	}
+	if cfg.HTTPAddr != ":8080" || cfg.ReadHeaderTimeout != 5*time.Second || cfg.TLSEnabled() || !cfg.CookieSecure {
+		t.Errorf("Received incorrect defaults: %+v", cfg)
+	}
+}
+
+func TestLoad_FromEnv(t *testing.T) {
+	// Act
+	cfg, err := load(env(map[string]string{
+		"FINTRACK_HTTP_ADDR":           ":9443",
+		"FINTRACK_HTTP_WRITE_TIMEOUT":  "1m",
+		"FINTRACK_TLS_CERT_FILE":       "cert.pem",
+		"FINTRACK_TLS_KEY_FILE":        "key.pem",
+		"FINTRACK_COOKIE_SECURE":       "false",
+		"FINTRACK_DB_MAX_OPEN_CONNS":   "50",
+		"FINTRACK_LOG_LEVEL":           "debug",
+		"FINTRACK_LOG_FORMAT":          "text",
+		"FINTRACK_TRACE_EXPORTER":      "otlp",
+		"FINTRACK_TRACE_OTLP_INSECURE": "false",
+		"FINTRACK_DB_REQUEST_TIMEOUT":  "0s",
+		"FINTRACK_RATE_LIMIT_BACKEND":  "redis",
+		"FINTRACK_LOGIN_RATE_IP_BURST": "0",
+		"FINTRACK_MAIL_BACKEND":        "smtp",
+		"FINTRACK_PASSWORD_RESET_TTL":  "15m",
+		"FINTRACK_OIDC_ISSUER":         "https://idp.example.com",
+		"FINTRACK_OIDC_CLIENT_ID":      "fintrack",
+		"FINTRACK_OIDC_SCOPES":         "profile, email,groups",
+	}))
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Received an error: received %v, expected %v", err, nil)
+	}
+	if cfg.HTTPAddr != ":9443" || cfg.WriteTimeout != time.Minute || !cfg.TLSEnabled() || cfg.DBMaxOpenConns != 50 ||
+		cfg.LogLevel != slog.LevelDebug || cfg.LogFormat != "text" || cfg.TraceExporter != "otlp" || cfg.TraceOTLPInsecure ||
+		cfg.DBRequestTimeout != 0 || cfg.RateLimitBackend != "redis" || cfg.LoginRateIPBurst != 0 || cfg.LoginRateUsernameBurst != 5 ||
+		cfg.MailBackend != "smtp" || cfg.PasswordResetTTL != 15*time.Minute || !cfg.OIDCEnabled() || len(cfg.OIDCScopes) != 3 ||
+		cfg.CookieSecure {
+		t.Errorf("Received incorrect config: %+v", cfg)
+	}
+}
+
+func TestLoad_ReportsAllErrors(t *testing.T) {
+	// Act
+	_, err := load(env(map[string]string{
+		"FINTRACK_HTTP_READ_TIMEOUT": "soon",
+		"FINTRACK_TLS_CERT_FILE":     "cert.pem",
+		"FINTRACK_DB_MAX_IDLE_CONNS": "-1",
+		"FINTRACK_LOG_LEVEL":         "verbose",
+		"FINTRACK_OIDC_ISSUER":       "https://idp.example.com",
+	}))
+
+	// Assert
+	if err == nil || !strings.Contains(err.Error(), "FINTRACK_HTTP_READ_TIMEOUT") || !strings.Contains(err.Error(), "FINTRACK_TLS_KEY_FILE") ||
+		!strings.Contains(err.Error(), "FINTRACK_DB_MAX_IDLE_CONNS") || !strings.Contains(err.Error(), "FINTRACK_LOG_LEVEL") ||
+		!strings.Contains(err.Error(), "FINTRACK_OIDC_CLIENT_ID") {
+		t.Errorf("Received incorrect error: received %v, expected both problems", err)
+	}
+}
@@ -1,32 +1,75 @@
 package database
 
 import (
+	"context"
 	"database/sql"
+	"time"
 
 	_ "github.com/go-sql-driver/mysql"
 )
 
+// PoolConfig - налаштування пулу з'єднань; нульові значення залишають значення database/sql за замовчуванням
+type PoolConfig struct {
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+}
+
 type RealDatabase struct {
 	// реалізація основної бази даних
 	RealDBName string
+	DSN        string // рядок підключення з конфігурації; порожній - локальна база "test"
+	Pool       PoolConfig
 	db_real    *sql.DB
 }
 
 func (db *RealDatabase) InitDB() error {
-	db.RealDBName = "test"
+	dsn := db.DSN
+	if dsn == "" {
+		db.RealDBName = "test"
+		dsn = "root:12345@tcp(localhost:3306)/" + db.RealDBName + "?parseTime=true"
+	}
+
 	var err error
-	dsn := "root:12345@tcp(localhost:3306)/" + db.RealDBName + "?parseTime=true"
 	db.db_real, err = sql.Open("mysql", dsn)
 	if err != nil {
 		return err
 	}
+
+	if db.Pool.MaxOpenConns > 0 {
+		db.db_real.SetMaxOpenConns(db.Pool.MaxOpenConns)
+	}
+	if db.Pool.MaxIdleConns > 0 {
+		db.db_real.SetMaxIdleConns(db.Pool.MaxIdleConns)
+	}
+	if db.Pool.ConnMaxLifetime > 0 {
+		db.db_real.SetConnMaxLifetime(db.Pool.ConnMaxLifetime)
+	}
+	if db.Pool.ConnMaxIdleTime > 0 {
+		db.db_real.SetConnMaxIdleTime(db.Pool.ConnMaxIdleTime)
+	}
+
 	return nil
 }
 
+// WaitReady перевіряє з'єднання з базою (sql.Open цього не робить), повторюючи спробу
+// до attempts разів з паузою backoff, що подвоюється після кожної невдачі
+func (db *RealDatabase) WaitReady(ctx context.Context, attempts int, backoff time.Duration, onRetry func(attempt int, err error)) error {
+	return retry(ctx, attempts, backoff, func() error {
+		pingCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+		return db.db_real.PingContext(pingCtx)
+	}, onRetry)
+}
+
 func (db *RealDatabase) GetDB() *sql.DB {
 	return db.db_real
 }
 
-func (db *RealDatabase) CloseDB() {
-	db.db_real.Close()
+func (db *RealDatabase) CloseDB() error {
+	if db.db_real == nil {
+		return nil
+	}
+	return db.db_real.Close()
 }
@@ -0,0 +1,38 @@
+package database
+
+import (
+	"context"
+	"time"
+)
+
+// maxBackoff обмежує паузу між спробами, щоб подвоєння не розтягувало запуск на хвилини
+const maxBackoff = 30 * time.Second
+
+// retry викликає fn, доки вона не завершиться успішно, не вичерпаються спроби або не скасується ctx;
+// повертає останню помилку fn
+func retry(ctx context.Context, attempts int, backoff time.Duration, fn func() error, onRetry func(attempt int, err error)) error {
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		err = fn()
+		if err == nil || attempt == attempts {
+			return err
+		}
+
+		if onRetry != nil {
+			onRetry(attempt, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,56 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRetry_SucceedsAfterFailures(t *testing.T) {
+	// Arrange
+	calls := 0
+	var retried []int
+
+	// Act
+	err := retry(context.Background(), 5, time.Millisecond, func() error {
+		calls++
+		if calls < 3 {
+			return errors.New("connection refused")
+		}
+		return nil
+	}, func(attempt int, err error) { retried = append(retried, attempt) })
+
+	// Assert
+	if err != nil || calls != 3 || len(retried) != 2 {
+		t.Errorf("Received err %v after %d calls and retries %v, expected success on the third call", err, calls, retried)
+	}
+}
+
+func TestRetry_GivesUp(t *testing.T) {
+	calls := 0
+
+	err := retry(context.Background(), 3, time.Millisecond, func() error {
+		calls++
+		return errors.New("connection refused")
+	}, nil)
+
+	if err == nil || calls != 3 {
+		t.Errorf("Received err %v after %d calls, expected the last error after 3 calls", err, calls)
+	}
+}
+
+func TestRetry_StopsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	calls := 0
+
+	err := retry(ctx, 10, time.Hour, func() error {
+		calls++
+		return errors.New("connection refused")
+	}, nil)
+
+	if err == nil || calls != 1 {
+		t.Errorf("Received err %v after %d calls, expected to stop after the first call", err, calls)
+	}
+}
@@ -0,0 +1,129 @@
+package drepo
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/ChomuCake/uni-golang-labs/models"
+	_ "github.com/go-sql-driver/mysql"
+)
+
+// --------------------------- Логіка роботи з персональними токенами доступу (MySQL) ---------------------------
+
+// інтерфейс DatabaseK описується в тому ж файлі що і використовується
+type DatabaseK interface {
+	GetDB() *sql.DB
+}
+
+type AccessTokenDBMySQL struct {
+	Observer QueryObserver // необов'язковий, nil - без вимірювань
+	DB       DatabaseK
+}
+
+func NewAccessTokenDBMySQL(DB DatabaseK) *AccessTokenDBMySQL {
+	return &AccessTokenDBMySQL{DB: DB}
+}
+
+const accessTokenColumns = "t.id, t.user_id, t.name, t.scopes, t.created_at, t.expires_at, t.last_used_at"
+
+func scanAccessToken(row interface{ Scan(...any) error }) (models.AccessToken, error) {
+	var (
+		token      models.AccessToken
+		scopes     string
+		expiresAt  sql.NullTime
+		lastUsedAt sql.NullTime
+	)
+	err := row.Scan(&token.ID, &token.UserID, &token.Name, &scopes, &token.CreatedAt, &expiresAt, &lastUsedAt)
+	if err != nil {
+		return models.AccessToken{}, err
+	}
+
+	token.Scopes = strings.Split(scopes, ",")
+	if expiresAt.Valid {
+		token.ExpiresAt = &expiresAt.Time
+	}
+	if lastUsedAt.Valid {
+		token.LastUsedAt = &lastUsedAt.Time
+	}
+	return token, nil
+}
+
+// AddAccessToken зберігає токен (лише хеш) і повертає його айді
+func (db *AccessTokenDBMySQL) AddAccessToken(ctx context.Context, token models.AccessToken, tokenHash string) (id int, err error) {
+	defer observe(ctx, db.Observer, "access_tokens", "AddAccessToken")(&err)
+
+	result, err := db.DB.GetDB().ExecContext(ctx, "INSERT INTO access_tokens (user_id, name, token_hash, scopes, created_at, expires_at) VALUES (?, ?, ?, ?, ?, ?)",
+		token.UserID, token.Name, tokenHash, strings.Join(token.Scopes, ","), token.CreatedAt, token.ExpiresAt)
+	if err != nil {
+		return 0, err
+	}
+
+	lastID, err := result.LastInsertId()
+	return int(lastID), err
+}
+
+func (db *AccessTokenDBMySQL) GetUserAccessTokens(ctx context.Context, userID int) (tokens []models.AccessToken, err error) {
+	defer observe(ctx, db.Observer, "access_tokens", "GetUserAccessTokens")(&err)
+
+	rows, err := db.DB.GetDB().QueryContext(ctx, "SELECT "+accessTokenColumns+" FROM access_tokens t WHERE t.user_id = ? ORDER BY t.id", userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		token, err := scanAccessToken(rows)
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, token)
+	}
+
+	return tokens, rows.Err()
+}
+
+// GetAccessTokenByHash шукає токен за хешем; sql.ErrNoRows - такого токена немає або власника видалено
+func (db *AccessTokenDBMySQL) GetAccessTokenByHash(ctx context.Context, tokenHash string) (token models.AccessToken, err error) {
+	defer observe(ctx, db.Observer, "access_tokens", "GetAccessTokenByHash")(&err)
+
+	row := db.DB.GetDB().QueryRowContext(ctx, `
+		SELECT `+accessTokenColumns+`
+		FROM access_tokens t
+		JOIN users u ON u.id = t.user_id
+		WHERE t.token_hash = ? AND u.deleted_at IS NULL`, tokenHash)
+	return scanAccessToken(row)
+}
+
+// TouchAccessToken запам'ятовує час останнього використання
+func (db *AccessTokenDBMySQL) TouchAccessToken(ctx context.Context, tokenID int, usedAt time.Time) (err error) {
+	defer observe(ctx, db.Observer, "access_tokens", "TouchAccessToken")(&err)
+
+	_, err = db.DB.GetDB().ExecContext(ctx, "UPDATE access_tokens SET last_used_at = ? WHERE id = ?", usedAt, tokenID)
+	return err
+}
+
+// DeleteAccessToken відкликає токен користувача; false - у користувача немає такого токена
+func (db *AccessTokenDBMySQL) DeleteAccessToken(ctx context.Context, userID, tokenID int) (deleted bool, err error) {
+	defer observe(ctx, db.Observer, "access_tokens", "DeleteAccessToken")(&err)
+
+	result, err := db.DB.GetDB().ExecContext(ctx, "DELETE FROM access_tokens WHERE id = ? AND user_id = ?", tokenID, userID)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected == 1, nil
+}
+
+// DeleteUserAccessTokens відкликає всі токени користувача
+func (db *AccessTokenDBMySQL) DeleteUserAccessTokens(ctx context.Context, userID int) (err error) {
+	defer observe(ctx, db.Observer, "access_tokens", "DeleteUserAccessTokens")(&err)
+
+	_, err = db.DB.GetDB().ExecContext(ctx, "DELETE FROM access_tokens WHERE user_id = ?", userID)
+	return err
+}
@@ -0,0 +1,204 @@
+package drepo
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/ChomuCake/uni-golang-labs/models"
+	_ "github.com/go-sql-driver/mysql"
+)
+
+// --------------------------- Логіка роботи з даними для рахунків та переказів (MySQL) ---------------------------
+
+// інтерфейс DatabaseA описується в тому ж файлі що і використовується
+type DatabaseA interface {
+	GetDB() *sql.DB
+}
+
+type AccountDBMySQL struct {
+	Observer QueryObserver // необов'язковий, nil - без вимірювань
+	DB       DatabaseA
+}
+
+func NewAccountDBMySQL(DB DatabaseA) *AccountDBMySQL {
+	return &AccountDBMySQL{DB: DB}
+}
+
+func (db *AccountDBMySQL) AddAccount(ctx context.Context, account models.Account) (id int, err error) {
+	defer observe(ctx, db.Observer, "accounts", "AddAccount")(&err)
+
+	query := "INSERT INTO accounts (user_id, name, type, initial_balance) VALUES (?, ?, ?, ?)"
+	res, err := db.DB.GetDB().ExecContext(ctx, query, account.UserID, account.Name, account.Type, account.InitialBalance)
+	if err != nil {
+		return 0, err
+	}
+
+	accountID, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(accountID), nil
+}
+
+func (db *AccountDBMySQL) GetUserAccounts(ctx context.Context, userID int) (accounts []models.Account, err error) {
+	defer observe(ctx, db.Observer, "accounts", "GetUserAccounts")(&err)
+
+	query := "SELECT id, user_id, name, type, initial_balance FROM accounts WHERE user_id = ? ORDER BY id"
+	rows, err := db.DB.GetDB().QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var account models.Account
+		err := rows.Scan(&account.ID, &account.UserID, &account.Name, &account.Type, &account.InitialBalance)
+		if err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, account)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return accounts, nil
+}
+
+// GetUserAccountBalances повертає рахунки користувача з поточними балансами. Суми витрат, доходів
+// і переказів рахуються в базі одним запитом з групуванням за рахунком, без завантаження історії
+func (db *AccountDBMySQL) GetUserAccountBalances(ctx context.Context, userID int) (accounts []models.Account, err error) {
+	defer observe(ctx, db.Observer, "accounts", "GetUserAccountBalances")(&err)
+
+	query := `SELECT a.id, a.user_id, a.name, a.type, a.initial_balance,
+			a.initial_balance + COALESCE(e_sum.total, 0) - COALESCE(t_out.total, 0) + COALESCE(t_in.total, 0)
+		FROM accounts a
+		LEFT JOIN (
+			SELECT e.account_id, SUM(CASE WHEN e.kind = 'income' THEN e.amount ELSE -e.amount END) AS total
+			FROM expenses e JOIN accounts ea ON ea.id = e.account_id
+			WHERE ea.user_id = ? GROUP BY e.account_id
+		) e_sum ON e_sum.account_id = a.id
+		LEFT JOIN (
+			SELECT t.from_account_id AS account_id, SUM(t.amount) AS total
+			FROM transfers t JOIN accounts ta ON ta.id = t.from_account_id
+			WHERE ta.user_id = ? GROUP BY t.from_account_id
+		) t_out ON t_out.account_id = a.id
+		LEFT JOIN (
+			SELECT t.to_account_id AS account_id, SUM(t.amount) AS total
+			FROM transfers t JOIN accounts ta ON ta.id = t.to_account_id
+			WHERE ta.user_id = ? GROUP BY t.to_account_id
+		) t_in ON t_in.account_id = a.id
+		WHERE a.user_id = ?
+		ORDER BY a.id`
+	rows, err := db.DB.GetDB().QueryContext(ctx, query, userID, userID, userID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var account models.Account
+		err := rows.Scan(&account.ID, &account.UserID, &account.Name, &account.Type, &account.InitialBalance, &account.Balance)
+		if err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, account)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return accounts, nil
+}
+
+func (db *AccountDBMySQL) GetAccountByID(ctx context.Context, accountID int) (account models.Account, err error) {
+	defer observe(ctx, db.Observer, "accounts", "GetAccountByID")(&err)
+
+	query := "SELECT id, user_id, name, type, initial_balance FROM accounts WHERE id = ?"
+
+	err = db.DB.GetDB().QueryRowContext(ctx, query, accountID).Scan(&account.ID, &account.UserID, &account.Name, &account.Type, &account.InitialBalance)
+	if err != nil {
+		return models.Account{}, err
+	}
+
+	return account, nil
+}
+
+func (db *AccountDBMySQL) GetAccountEntries(ctx context.Context, accountID int) (entries []models.AccountEntry, err error) {
+	defer observe(ctx, db.Observer, "accounts", "GetAccountEntries")(&err)
+
+	// Усі рухи коштів по рахунку: витрати й доходи та перекази в обидва боки
+	query := `SELECT date, kind, id, category, CASE WHEN kind = 'income' THEN amount ELSE -amount END
+			FROM expenses WHERE account_id = ?
+		UNION ALL
+		SELECT date, 'transfer_out', id, '', -amount FROM transfers WHERE from_account_id = ?
+		UNION ALL
+		SELECT date, 'transfer_in', id, '', amount FROM transfers WHERE to_account_id = ?
+		ORDER BY 1, 3`
+	rows, err := db.DB.GetDB().QueryContext(ctx, query, accountID, accountID, accountID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var entry models.AccountEntry
+		err := rows.Scan(&entry.Date, &entry.Kind, &entry.ReferenceID, &entry.Category, &entry.Amount)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+func (db *AccountDBMySQL) AddTransfer(ctx context.Context, transfer models.Transfer) (id int, err error) {
+	defer observe(ctx, db.Observer, "transfers", "AddTransfer")(&err)
+
+	query := "INSERT INTO transfers (user_id, from_account_id, to_account_id, amount, date) VALUES (?, ?, ?, ?, ?)"
+	res, err := db.DB.GetDB().ExecContext(ctx, query, transfer.UserID, transfer.FromAccountID, transfer.ToAccountID, transfer.Amount, transfer.Date)
+	if err != nil {
+		return 0, err
+	}
+
+	transferID, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(transferID), nil
+}
+
+func (db *AccountDBMySQL) GetUserTransfers(ctx context.Context, userID int) (transfers []models.Transfer, err error) {
+	defer observe(ctx, db.Observer, "transfers", "GetUserTransfers")(&err)
+
+	query := "SELECT id, user_id, from_account_id, to_account_id, amount, date FROM transfers WHERE user_id = ? ORDER BY date"
+	rows, err := db.DB.GetDB().QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var transfer models.Transfer
+		err := rows.Scan(&transfer.ID, &transfer.UserID, &transfer.FromAccountID, &transfer.ToAccountID, &transfer.Amount, &transfer.Date)
+		if err != nil {
+			return nil, err
+		}
+		transfers = append(transfers, transfer)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return transfers, nil
+}
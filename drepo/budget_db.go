@@ -0,0 +1,81 @@
+package drepo
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/ChomuCake/uni-golang-labs/models"
+	_ "github.com/go-sql-driver/mysql"
+)
+
+// --------------------------- Логіка роботи з даними для бюджетів (MySQL) ---------------------------
+
+// інтерфейс DatabaseB описується в тому ж файлі що і використовується
+type DatabaseB interface {
+	GetDB() *sql.DB
+}
+
+type BudgetDBMySQL struct {
+	Observer QueryObserver // необов'язковий, nil - без вимірювань
+	DB       DatabaseB
+}
+
+func NewBudgetDBMySQL(DB DatabaseB) *BudgetDBMySQL {
+	return &BudgetDBMySQL{DB: DB}
+}
+
+// SetBudget створює бюджет категорії або змінює суму наявного
+func (db *BudgetDBMySQL) SetBudget(ctx context.Context, budget models.Budget) (err error) {
+	defer observe(ctx, db.Observer, "budgets", "SetBudget")(&err)
+
+	query := `INSERT INTO budgets (user_id, category, amount) VALUES (?, ?, ?)
+		ON DUPLICATE KEY UPDATE amount = VALUES(amount)`
+	_, err = db.DB.GetDB().ExecContext(ctx, query, budget.UserID, budget.Category, budget.Amount)
+	return err
+}
+
+func (db *BudgetDBMySQL) GetUserBudgets(ctx context.Context, userID int) (budgets []models.Budget, err error) {
+	defer observe(ctx, db.Observer, "budgets", "GetUserBudgets")(&err)
+
+	query := "SELECT id, user_id, category, amount FROM budgets WHERE user_id = ? ORDER BY category"
+	rows, err := db.DB.GetDB().QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var budget models.Budget
+		err := rows.Scan(&budget.ID, &budget.UserID, &budget.Category, &budget.Amount)
+		if err != nil {
+			return nil, err
+		}
+		budgets = append(budgets, budget)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return budgets, nil
+}
+
+func (db *BudgetDBMySQL) DeleteBudget(ctx context.Context, userID int, category string) (err error) {
+	defer observe(ctx, db.Observer, "budgets", "DeleteBudget")(&err)
+
+	query := "DELETE FROM budgets WHERE user_id = ? AND category = ?"
+	res, err := db.DB.GetDB().ExecContext(ctx, query, userID, category)
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
@@ -3,6 +3,7 @@
 package drepo
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"log"
@@ -70,13 +71,60 @@ func (db *TestDatabase) СlearTestDB() error {
 		CREATE TABLE users (
 			id INT AUTO_INCREMENT PRIMARY KEY,
 			username VARCHAR(255) NOT NULL,
-			password VARCHAR(255) NOT NULL
+			password VARCHAR(255) NOT NULL,
+			time_zone VARCHAR(64) NOT NULL DEFAULT 'UTC',
+			token_version INT NOT NULL DEFAULT 0,
+			deleted_at DATETIME NULL,
+			email VARCHAR(255) NULL UNIQUE,
+			totp_secret VARCHAR(64) NULL,
+			totp_enabled BOOLEAN NOT NULL DEFAULT FALSE,
+			totp_last_step BIGINT NOT NULL DEFAULT 0
 		)
 	`)
 	if err != nil {
 		return fmt.Errorf("failed to create users table: %v", err)
 	}
 
+	// Створення таблиці `login_failures`
+	_, err = db.db_test.Exec(`
+		CREATE TABLE login_failures (
+			username VARCHAR(255) NOT NULL PRIMARY KEY,
+			failed_logins INT NOT NULL DEFAULT 0,
+			locked_until DATETIME NULL
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create login_failures table: %v", err)
+	}
+
+	// Створення таблиць `ledgers` та `ledger_members`
+	_, err = db.db_test.Exec(`
+		CREATE TABLE ledgers (
+			id INT AUTO_INCREMENT PRIMARY KEY,
+			name VARCHAR(255) NOT NULL,
+			owner_id INT NOT NULL,
+			FOREIGN KEY (owner_id) REFERENCES users(id)
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create ledgers table: %v", err)
+	}
+
+	_, err = db.db_test.Exec(`
+		CREATE TABLE ledger_members (
+			ledger_id INT NOT NULL,
+			user_id INT NOT NULL,
+			role VARCHAR(16) NOT NULL,
+			joined_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+			PRIMARY KEY (ledger_id, user_id),
+			FOREIGN KEY (ledger_id) REFERENCES ledgers(id),
+			FOREIGN KEY (user_id) REFERENCES users(id)
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create ledger_members table: %v", err)
+	}
+
 	// Створення таблиці `expenses`
 	_, err = db.db_test.Exec(`
 		CREATE TABLE expenses (
@@ -85,13 +133,123 @@ func (db *TestDatabase) СlearTestDB() error {
 			category VARCHAR(255) NOT NULL,
 			amount INT NOT NULL,
 			user_id INT NOT NULL,
-			FOREIGN KEY (user_id) REFERENCES users(id)
+			ledger_id INT NULL,
+			paid_by INT NULL,
+			split_method VARCHAR(16) NULL,
+			kind VARCHAR(16) NOT NULL DEFAULT 'expense',
+			account_id INT NULL,
+			FOREIGN KEY (user_id) REFERENCES users(id),
+			FOREIGN KEY (ledger_id) REFERENCES ledgers(id),
+			FOREIGN KEY (paid_by) REFERENCES users(id)
 		)
 	`)
 	if err != nil {
 		return fmt.Errorf("failed to create expenses table: %v", err)
 	}
 
+	// Створення таблиці `expense_splits`
+	_, err = db.db_test.Exec(`
+		CREATE TABLE expense_splits (
+			expense_id INT NOT NULL,
+			user_id INT NOT NULL,
+			amount INT NOT NULL,
+			PRIMARY KEY (expense_id, user_id),
+			FOREIGN KEY (expense_id) REFERENCES expenses(id) ON DELETE CASCADE,
+			FOREIGN KEY (user_id) REFERENCES users(id)
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create expense_splits table: %v", err)
+	}
+
+	// Таблиці з особистими даними, які очищує видалення користувача
+	for name, query := range map[string]string{
+		"settlements": `
+			CREATE TABLE settlements (
+				id INT AUTO_INCREMENT PRIMARY KEY,
+				ledger_id INT NOT NULL,
+				from_user_id INT NOT NULL,
+				to_user_id INT NOT NULL,
+				amount INT NOT NULL,
+				date TIMESTAMP NOT NULL,
+				FOREIGN KEY (ledger_id) REFERENCES ledgers(id),
+				FOREIGN KEY (from_user_id) REFERENCES users(id),
+				FOREIGN KEY (to_user_id) REFERENCES users(id)
+			)`,
+		"accounts": `
+			CREATE TABLE accounts (
+				id INT AUTO_INCREMENT PRIMARY KEY,
+				user_id INT NOT NULL,
+				name VARCHAR(255) NOT NULL,
+				type VARCHAR(16) NOT NULL,
+				initial_balance INT NOT NULL DEFAULT 0,
+				FOREIGN KEY (user_id) REFERENCES users(id)
+			)`,
+		"transfers": `
+			CREATE TABLE transfers (
+				id INT AUTO_INCREMENT PRIMARY KEY,
+				user_id INT NOT NULL,
+				from_account_id INT NOT NULL,
+				to_account_id INT NOT NULL,
+				amount INT NOT NULL,
+				date TIMESTAMP NOT NULL,
+				FOREIGN KEY (user_id) REFERENCES users(id)
+			)`,
+		"password_reset_tokens": `
+			CREATE TABLE password_reset_tokens (
+				id INT AUTO_INCREMENT PRIMARY KEY,
+				user_id INT NOT NULL,
+				token_hash CHAR(64) NOT NULL UNIQUE,
+				expires_at DATETIME NOT NULL,
+				used_at DATETIME NULL,
+				FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+			)`,
+		"recovery_codes": `
+			CREATE TABLE recovery_codes (
+				id INT AUTO_INCREMENT PRIMARY KEY,
+				user_id INT NOT NULL,
+				code_hash CHAR(64) NOT NULL,
+				used_at DATETIME NULL,
+				UNIQUE KEY recovery_code_user_hash (user_id, code_hash),
+				FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+			)`,
+		"user_identities": `
+			CREATE TABLE user_identities (
+				id INT AUTO_INCREMENT PRIMARY KEY,
+				user_id INT NOT NULL,
+				issuer VARCHAR(255) NOT NULL,
+				subject VARCHAR(255) NOT NULL,
+				created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+				UNIQUE KEY user_identity_issuer_subject (issuer, subject),
+				FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+			)`,
+		"access_tokens": `
+			CREATE TABLE access_tokens (
+				id INT AUTO_INCREMENT PRIMARY KEY,
+				user_id INT NOT NULL,
+				name VARCHAR(64) NOT NULL,
+				token_hash CHAR(64) NOT NULL UNIQUE,
+				scopes VARCHAR(255) NOT NULL,
+				created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+				expires_at DATETIME NULL,
+				last_used_at DATETIME NULL,
+				FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+			)`,
+		"budgets": `
+			CREATE TABLE budgets (
+				id INT AUTO_INCREMENT PRIMARY KEY,
+				user_id INT NOT NULL,
+				category VARCHAR(64) NOT NULL,
+				amount INT NOT NULL,
+				FOREIGN KEY (user_id) REFERENCES users(id)
+			)`,
+	} {
+		_, err = db.db_test.Exec(query)
+		if err != nil {
+			return fmt.Errorf("failed to create %s table: %v", name, err)
+		}
+	}
+
 	return nil
 }
 
@@ -106,6 +264,8 @@ func TestGetUserExpensesIntegration(t *testing.T) {
 	}
 	defer db.CloseDB()
 
+	ctx := context.Background()
+
 	// Створення репо витрат
 	ExpenseDB := NewExpenseDBMySQL(db)
 
@@ -115,12 +275,14 @@ func TestGetUserExpensesIntegration(t *testing.T) {
 	newUser := models.User{
 		Username: "TestName",
 		Password: "12345",
+		TimeZone: "Europe/Kyiv",
 	}
 
-	// GetUserByID повинен повертати тільки ім'я та айді користувача
+	// GetUserByID повинен повертати ім'я, айді та часовий пояс користувача (без пароля)
 	expectedUser := models.User{
 		Username: newUser.Username,
 		ID:       1,
+		TimeZone: newUser.TimeZone,
 	}
 
 	// Створення об'єкту моделі витрат
@@ -150,14 +312,14 @@ func TestGetUserExpensesIntegration(t *testing.T) {
 	// Тестування створення і отримання користувача
 	// Результат після створення користувача він має отримуватись з бд
 	t.Run("create and get User", func(t *testing.T) {
-		err = userDB.AddUser(newUser)
+		err = userDB.AddUser(ctx, newUser)
 
 		if err != nil {
 			t.Errorf("failed to add user with error: %v", err)
 		}
 
 		fmt.Println(newUser)
-		user, err := userDB.GetUserByID(expectedUser.ID)
+		user, err := userDB.GetUserByID(ctx, expectedUser.ID)
 		if err != nil {
 			t.Errorf("failed to get user with error: %v", err)
 		}
@@ -170,14 +332,14 @@ func TestGetUserExpensesIntegration(t *testing.T) {
 	// Тестування створення і отримання витрат користувача
 	// Результат користувач повинен отримувати нову витрату після створення її у бд
 	t.Run("create and get UserExpneses", func(t *testing.T) {
-		err = ExpenseDB.AddExpense(newExpense)
+		err = ExpenseDB.AddExpense(ctx, newExpense)
 
 		if err != nil {
 			t.Errorf("failed to add expense with error: %v", err)
 		}
 
 		fmt.Println(newExpense)
-		expense, err := ExpenseDB.GetUserExpenses(expectedUser.ID)
+		expense, err := ExpenseDB.GetUserExpenses(ctx, expectedUser.ID)
 		if err != nil {
 			t.Errorf("failed to get user expneses with error: %v", err)
 		}
@@ -190,14 +352,14 @@ func TestGetUserExpensesIntegration(t *testing.T) {
 	// Тестування оновлення і отримання витрат користувача
 	// Результат користувач повинен отримувати оновлені витрати після оновлення їх у бд
 	t.Run("update and get UserExpnese", func(t *testing.T) {
-		err = ExpenseDB.UpdateUserExpenses(ExpensesUpdate)
+		err = ExpenseDB.UpdateUserExpenses(ctx, ExpensesUpdate)
 
 		if err != nil {
 			t.Errorf("failed update expense with error: %v", err)
 		}
 
 		fmt.Println(ExpensesUpdate)
-		expense, err := ExpenseDB.GetUserExpenses(expectedUser.ID)
+		expense, err := ExpenseDB.GetUserExpenses(ctx, expectedUser.ID)
 		if err != nil {
 			t.Errorf("failed to get user expneses with error: %v", err)
 		}
@@ -210,13 +372,13 @@ func TestGetUserExpensesIntegration(t *testing.T) {
 	// Тестування видалення і отримання витрат користувача
 	// Результат користувач повинен отримувати 0 витрат після видалення їх з бд
 	t.Run("delete and get UserExpnese", func(t *testing.T) {
-		err = ExpenseDB.DeleteExpense(strconv.Itoa(ExpensesUpdate.ID))
+		err = ExpenseDB.DeleteExpense(ctx, strconv.Itoa(ExpensesUpdate.ID))
 
 		if err != nil {
 			t.Errorf("failed to delete expense with error: %v", err)
 		}
 
-		expense, err := ExpenseDB.GetUserExpenses(expectedUser.ID)
+		expense, err := ExpenseDB.GetUserExpenses(ctx, expectedUser.ID)
 		if err != nil {
 			t.Errorf("failed to get user expneses with error: %v", err)
 		}
@@ -229,12 +391,12 @@ func TestGetUserExpensesIntegration(t *testing.T) {
 	// Тестування отримання користувача за ім'ям, та за ім'ям і паролем
 	// Результат користувач повинен бути однаковим при кожному отримані з бд
 	t.Run("get user by username and get user by username and password", func(t *testing.T) {
-		userGet1, err := userDB.GetUserByUsername(newUser.Username)
+		userGet1, err := userDB.GetUserByUsername(ctx, newUser.Username)
 		if err != nil {
 			t.Errorf("failed to get user with error: %v", err)
 		}
 
-		userGet2, err := userDB.GetUserByUsernameAndPassword(newUser.Username, newUser.Password)
+		userGet2, err := userDB.GetUserByUsernameAndPassword(ctx, newUser.Username, newUser.Password)
 		if err != nil {
 			t.Errorf("failed to get user with error: %v", err)
 		}
@@ -244,6 +406,228 @@ func TestGetUserExpensesIntegration(t *testing.T) {
 		}
 	})
 
+	// Тестування лічильника невдалих входів: інкремент, блокування і скидання після успішного входу
+	t.Run("record login failures, lock and reset", func(t *testing.T) {
+		for i := 1; i <= 2; i++ {
+			failures, err := userDB.RecordLoginFailure(ctx, newUser.Username)
+			if err != nil || failures != i {
+				t.Errorf("failed to record login failure: received %v, %v, expected %v", failures, err, i)
+			}
+		}
+
+		until := time.Now().Add(time.Minute).Truncate(time.Second)
+		err := userDB.LockUser(ctx, newUser.Username, until)
+		if err != nil {
+			t.Errorf("failed to lock user with error: %v", err)
+		}
+
+		state, err := userDB.GetLoginState(ctx, newUser.Username)
+		if err != nil || state.FailedLogins != 2 || !state.LockedUntil.Equal(until) {
+			t.Errorf("login state is corrupted; actual: %+v, %v, expected: 2 failures locked until %v", state, err, until)
+		}
+
+		err = userDB.ResetLoginFailures(ctx, newUser.Username)
+		if err != nil {
+			t.Errorf("failed to reset login failures with error: %v", err)
+		}
+
+		state, err = userDB.GetLoginState(ctx, newUser.Username)
+		if err != nil || state != (models.LoginState{}) {
+			t.Errorf("login state is corrupted; actual: %+v, %v, expected: %+v", state, err, models.LoginState{})
+		}
+
+		// Спроби з незареєстрованим ім'ям рахуються так само
+		failures, err := userDB.RecordLoginFailure(ctx, "nobody")
+		if err != nil || failures != 1 {
+			t.Errorf("failed to record login failure for unknown username: received %v, %v, expected %v", failures, err, 1)
+		}
+	})
+
+	// Токен відновлення пароля спрацьовує один раз і змінює пароль користувача
+	t.Run("use password reset token once", func(t *testing.T) {
+		resetDB := NewPasswordResetDBMySQL(db)
+		err := resetDB.AddResetToken(ctx, expectedUser.ID, "hash-1", time.Now().Add(time.Hour))
+		if err != nil {
+			t.Fatalf("failed to add reset token with error: %v", err)
+		}
+
+		token, err := resetDB.GetResetToken(ctx, "hash-1")
+		if err != nil || token.UserID != expectedUser.ID || token.Used {
+			t.Fatalf("reset token is corrupted; actual: %+v, %v", token, err)
+		}
+
+		err = resetDB.UseResetToken(ctx, token, "reset-password-1")
+		if err != nil {
+			t.Errorf("failed to use reset token with error: %v", err)
+		}
+
+		err = resetDB.UseResetToken(ctx, token, "reset-password-2")
+		if err != sql.ErrNoRows {
+			t.Errorf("reset token used twice: received %v, expected %v", err, sql.ErrNoRows)
+		}
+
+		_, err = userDB.GetUserByUsernameAndPassword(ctx, newUser.Username, "reset-password-1")
+		if err != nil {
+			t.Errorf("failed to login with reset password: %v", err)
+		}
+	})
+
+	// Зміна пароля збільшує версію токенів, а видалення користувача без спільних даних прибирає запис повністю
+	t.Run("change password and delete user", func(t *testing.T) {
+		// Версія вже збільшилася один раз після відновлення пароля
+		version, err := userDB.ChangePassword(ctx, expectedUser.ID, "new-password-1")
+		if err != nil || version != 2 {
+			t.Errorf("failed to change password: received %v, %v, expected version %v", version, err, 2)
+		}
+
+		_, err = userDB.GetUserByUsernameAndPassword(ctx, newUser.Username, "new-password-1")
+		if err != nil {
+			t.Errorf("failed to login with new password: %v", err)
+		}
+
+		anonymized, err := userDB.DeleteUser(ctx, expectedUser.ID)
+		if err != nil || anonymized {
+			t.Errorf("failed to delete user: received %v, %v, expected removed row", anonymized, err)
+		}
+
+		_, err = userDB.GetTokenVersion(ctx, expectedUser.ID)
+		if err != sql.ErrNoRows {
+			t.Errorf("deleted user is still present: received %v, expected %v", err, sql.ErrNoRows)
+		}
+	})
+
+	// Журнал власника, що видаляє акаунт, переходить до учасника, який приєднався найраніше
+	t.Run("delete ledger owner", func(t *testing.T) {
+		ledgerDB := NewLedgerDBMySQL(db)
+		for _, username := range []string{"LedgerOwner", "FirstMember", "SecondMember"} {
+			err := userDB.AddUser(ctx, models.User{Username: username, Password: "12345", TimeZone: "UTC"})
+			if err != nil {
+				t.Fatalf("failed to add user with error: %v", err)
+			}
+		}
+		owner, _ := userDB.GetUserByUsername(ctx, "LedgerOwner")
+		first, _ := userDB.GetUserByUsername(ctx, "FirstMember")
+		second, _ := userDB.GetUserByUsername(ctx, "SecondMember")
+
+		ledgerID, err := ledgerDB.AddLedger(ctx, models.Ledger{Name: "Flat", OwnerID: owner.ID})
+		if err != nil {
+			t.Fatalf("failed to add ledger with error: %v", err)
+		}
+		// Глядач приєднався раніше за редактора, тож саме він стає власником
+		for _, member := range []models.LedgerMember{
+			{LedgerID: ledgerID, UserID: first.ID, Role: models.RoleViewer},
+			{LedgerID: ledgerID, UserID: second.ID, Role: models.RoleEditor},
+		} {
+			err = ledgerDB.AddMember(ctx, member)
+			if err != nil {
+				t.Fatalf("failed to add member with error: %v", err)
+			}
+		}
+
+		_, err = userDB.DeleteUser(ctx, owner.ID)
+		if err != nil {
+			t.Fatalf("failed to delete user: %v", err)
+		}
+
+		ledgers, err := ledgerDB.GetUserLedgers(ctx, first.ID)
+		if err != nil || len(ledgers) != 1 || ledgers[0].OwnerID != first.ID || ledgers[0].Role != models.RoleOwner {
+			t.Errorf("ledger isn't handed over: received %v, %v, expected owner %v", ledgers, err, first.ID)
+		}
+		role, err := ledgerDB.GetMemberRole(ctx, ledgerID, second.ID)
+		if err != nil || role != models.RoleEditor {
+			t.Errorf("received incorrect role of the other member: %v, %v, expected %v", role, err, models.RoleEditor)
+		}
+	})
+
+	// Звіти враховують і частку користувача у спільних витратах журналів, хто б їх не оплатив
+	t.Run("reports include ledger share", func(t *testing.T) {
+		ledgerDB := NewLedgerDBMySQL(db)
+		reportDB := NewReportDBMySQL(db)
+		for _, username := range []string{"ReportUser", "ReportFriend"} {
+			err := userDB.AddUser(ctx, models.User{Username: username, Password: "12345", TimeZone: "UTC"})
+			if err != nil {
+				t.Fatalf("failed to add user with error: %v", err)
+			}
+		}
+		user, _ := userDB.GetUserByUsername(ctx, "ReportUser")
+		friend, _ := userDB.GetUserByUsername(ctx, "ReportFriend")
+
+		ledgerID, err := ledgerDB.AddLedger(ctx, models.Ledger{Name: "Trip", OwnerID: user.ID})
+		if err != nil {
+			t.Fatalf("failed to add ledger with error: %v", err)
+		}
+		err = ledgerDB.AddMember(ctx, models.LedgerMember{LedgerID: ledgerID, UserID: friend.ID, Role: models.RoleEditor})
+		if err != nil {
+			t.Fatalf("failed to add member with error: %v", err)
+		}
+
+		date := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+		for _, expense := range []models.Expense{
+			{Amount: 30, Category: "Food", Date: date, Kind: models.KindExpense, UserID: user.ID},
+			{Amount: 100, Category: "Food", Date: date, Kind: models.KindExpense, UserID: friend.ID, LedgerID: ledgerID,
+				PaidBy: friend.ID, SplitMethod: models.SplitExact,
+				Splits: []models.ExpenseSplit{{UserID: user.ID, Amount: 40}, {UserID: friend.ID, Amount: 60}}},
+		} {
+			err = ExpenseDB.AddExpense(ctx, expense)
+			if err != nil {
+				t.Fatalf("failed to add expense with error: %v", err)
+			}
+		}
+		march := models.DateRange{Start: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), End: time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)}
+
+		spending, err := reportDB.GetSpendingByRanges(ctx, user.ID, "Food", []models.DateRange{march})
+		if err != nil || !reflect.DeepEqual(spending, []int{70}) {
+			t.Errorf("received incorrect spending: %v, %v, expected %v", spending, err, []int{70})
+		}
+		totals, err := reportDB.GetCategoryTotals(ctx, user.ID, march)
+		if err != nil || !reflect.DeepEqual(totals, []models.CategoryTotal{{Category: "Food", Total: 70}}) {
+			t.Errorf("received incorrect category totals: %v, %v, expected %v", totals, err, 70)
+		}
+		expenses, err := reportDB.GetExpensesInRange(ctx, user.ID, march)
+		if err != nil || len(expenses) != 2 || expenses[1].Amount != 40 || expenses[1].LedgerID != ledgerID {
+			t.Errorf("received incorrect expenses: %+v, %v, expected the personal expense and a share of %v", expenses, err, 40)
+		}
+	})
+
+	// Баланси всіх рахунків рахуються одним запитом: початковий баланс, витрати, доходи і перекази
+	t.Run("get user account balances", func(t *testing.T) {
+		accountDB := NewAccountDBMySQL(db)
+		err := userDB.AddUser(ctx, models.User{Username: "AccountUser", Password: "12345", TimeZone: "UTC"})
+		if err != nil {
+			t.Fatalf("failed to add user with error: %v", err)
+		}
+		user, _ := userDB.GetUserByUsername(ctx, "AccountUser")
+
+		cashID, err := accountDB.AddAccount(ctx, models.Account{UserID: user.ID, Name: "Cash", Type: models.AccountCash, InitialBalance: 100})
+		if err != nil {
+			t.Fatalf("failed to add account with error: %v", err)
+		}
+		savingsID, err := accountDB.AddAccount(ctx, models.Account{UserID: user.ID, Name: "Savings", Type: models.AccountSavings})
+		if err != nil {
+			t.Fatalf("failed to add account with error: %v", err)
+		}
+
+		date := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+		for _, expense := range []models.Expense{
+			{Amount: 30, Category: "Food", Date: date, Kind: models.KindExpense, UserID: user.ID, AccountID: cashID},
+			{Amount: 50, Category: "Salary", Date: date, Kind: models.KindIncome, UserID: user.ID, AccountID: cashID},
+		} {
+			err = ExpenseDB.AddExpense(ctx, expense)
+			if err != nil {
+				t.Fatalf("failed to add expense with error: %v", err)
+			}
+		}
+		_, err = accountDB.AddTransfer(ctx, models.Transfer{UserID: user.ID, FromAccountID: cashID, ToAccountID: savingsID, Amount: 20, Date: date})
+		if err != nil {
+			t.Fatalf("failed to add transfer with error: %v", err)
+		}
+
+		accounts, err := accountDB.GetUserAccountBalances(ctx, user.ID)
+		if err != nil || len(accounts) != 2 || accounts[0].Balance != 100 || accounts[1].Balance != 20 {
+			t.Errorf("received incorrect balances: %+v, %v, expected 100 and 20", accounts, err)
+		}
+	})
+
 	// Закінчення тестування
 	log.Println("Integration test completed.")
 }
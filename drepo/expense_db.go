@@ -1,6 +1,7 @@
 package drepo
 
 import (
+	"context"
 	"database/sql"
 
 	"github.com/ChomuCake/uni-golang-labs/models"
@@ -15,17 +16,82 @@ type Database interface {
 }
 
 type ExpenseDBMySQL struct {
-	DB Database
+	Observer QueryObserver // необов'язковий, nil - без вимірювань
+	DB       Database
 }
 
 func NewExpenseDBMySQL(DB Database) *ExpenseDBMySQL {
-	return &ExpenseDBMySQL{DB}
+	return &ExpenseDBMySQL{DB: DB}
 }
 
-func (db *ExpenseDBMySQL) GetUserExpenses(userID int) ([]models.Expense, error) {
-	// Виконання запиту до бази даних для отримання витрат користувача за його ідентифікатором
-	query := "SELECT id, amount, category, date FROM expenses WHERE user_id = ?"
-	rows, err := db.DB.GetDB().Query(query, userID)
+func (db *ExpenseDBMySQL) GetUserExpenses(ctx context.Context, userID int) (expenses []models.Expense, err error) {
+	defer observe(ctx, db.Observer, "expenses", "GetUserExpenses")(&err)
+
+	// Виконання запиту до бази даних для отримання особистих витрат користувача за його ідентифікатором
+	query := "SELECT id, amount, category, date, kind, account_id FROM expenses WHERE user_id = ? AND ledger_id IS NULL"
+	return db.queryExpenses(ctx, query, userID)
+}
+
+func (db *ExpenseDBMySQL) GetLedgerExpenses(ctx context.Context, ledgerID int) (expenses []models.Expense, err error) {
+	defer observe(ctx, db.Observer, "expenses", "GetLedgerExpenses")(&err)
+
+	// Виконання запиту до бази даних для отримання витрат спільного журналу разом з розподілом
+	query := "SELECT id, amount, category, date, kind, account_id, user_id, paid_by, split_method FROM expenses WHERE ledger_id = ?"
+	rows, err := db.DB.GetDB().QueryContext(ctx, query, ledgerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	index := make(map[int]int)
+	for rows.Next() {
+		var expense models.Expense
+		var accountID, paidBy sql.NullInt64
+		var splitMethod sql.NullString
+		err := rows.Scan(&expense.ID, &expense.Amount, &expense.Category, &expense.Date, &expense.Kind, &accountID, &expense.UserID, &paidBy, &splitMethod)
+		if err != nil {
+			return nil, err
+		}
+		expense.LedgerID = ledgerID
+		expense.AccountID = int(accountID.Int64)
+		expense.PaidBy = int(paidBy.Int64)
+		expense.SplitMethod = splitMethod.String
+		index[expense.ID] = len(expenses)
+		expenses = append(expenses, expense)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	splitRows, err := db.DB.GetDB().QueryContext(ctx, `SELECT s.expense_id, s.user_id, s.amount FROM expense_splits s
+		JOIN expenses e ON e.id = s.expense_id WHERE e.ledger_id = ?`, ledgerID)
+	if err != nil {
+		return nil, err
+	}
+	defer splitRows.Close()
+
+	for splitRows.Next() {
+		var expenseID int
+		var split models.ExpenseSplit
+		err := splitRows.Scan(&expenseID, &split.UserID, &split.Amount)
+		if err != nil {
+			return nil, err
+		}
+		if i, ok := index[expenseID]; ok {
+			expenses[i].Splits = append(expenses[i].Splits, split)
+		}
+	}
+
+	if err = splitRows.Err(); err != nil {
+		return nil, err
+	}
+
+	return expenses, nil
+}
+
+func (db *ExpenseDBMySQL) queryExpenses(ctx context.Context, query string, args ...interface{}) ([]models.Expense, error) {
+	rows, err := db.DB.GetDB().QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -34,10 +100,12 @@ func (db *ExpenseDBMySQL) GetUserExpenses(userID int) ([]models.Expense, error)
 	var expenses []models.Expense
 	for rows.Next() {
 		var expense models.Expense
-		err := rows.Scan(&expense.ID, &expense.Amount, &expense.Category, &expense.Date)
+		var accountID sql.NullInt64
+		err := rows.Scan(&expense.ID, &expense.Amount, &expense.Category, &expense.Date, &expense.Kind, &accountID)
 		if err != nil {
 			return nil, err
 		}
+		expense.AccountID = int(accountID.Int64)
 		expenses = append(expenses, expense)
 	}
 
@@ -48,21 +116,63 @@ func (db *ExpenseDBMySQL) GetUserExpenses(userID int) ([]models.Expense, error)
 	return expenses, nil
 }
 
-func (db *ExpenseDBMySQL) AddExpense(expense models.Expense) error {
-	// Виконання запиту до бази даних для збереження витрати
-	query := "INSERT INTO expenses (amount, category, date, user_id) VALUES (?, ?, ?, ?)"
-	_, err := db.DB.GetDB().Exec(query, expense.Amount, expense.Category, expense.Date, expense.UserID)
+func (db *ExpenseDBMySQL) GetExpenseByID(ctx context.Context, expenseID string) (expense models.Expense, err error) {
+	defer observe(ctx, db.Observer, "expenses", "GetExpenseByID")(&err)
+
+	// Виконання запиту до бази даних для отримання витрати за її ідентифікатором
+	query := "SELECT id, amount, category, date, kind, account_id, user_id, ledger_id, paid_by, split_method FROM expenses WHERE id = ?"
+
+	var accountID, ledgerID, paidBy sql.NullInt64
+	var splitMethod sql.NullString
+	err = db.DB.GetDB().QueryRowContext(ctx, query, expenseID).Scan(&expense.ID, &expense.Amount, &expense.Category, &expense.Date,
+		&expense.Kind, &accountID, &expense.UserID, &ledgerID, &paidBy, &splitMethod)
+	if err != nil {
+		return models.Expense{}, err
+	}
+	expense.AccountID = int(accountID.Int64)
+	expense.LedgerID = int(ledgerID.Int64)
+	expense.PaidBy = int(paidBy.Int64)
+	expense.SplitMethod = splitMethod.String
+
+	return expense, nil
+}
+
+func (db *ExpenseDBMySQL) AddExpense(ctx context.Context, expense models.Expense) (err error) {
+	defer observe(ctx, db.Observer, "expenses", "AddExpense")(&err)
+
+	// Витрата і її розподіл між учасниками зберігаються в одній транзакції
+	tx, err := db.DB.GetDB().BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
+	defer tx.Rollback()
 
-	return nil
+	query := "INSERT INTO expenses (amount, category, date, kind, account_id, user_id, ledger_id, paid_by, split_method) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)"
+	res, err := tx.ExecContext(ctx, query, expense.Amount, expense.Category, expense.Date, expense.Kind, nullableID(expense.AccountID),
+		expense.UserID, nullableID(expense.LedgerID), nullableID(expense.PaidBy), nullableString(expense.SplitMethod))
+	if err != nil {
+		return err
+	}
+
+	expenseID, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	err = insertSplits(ctx, tx, int(expenseID), expense.Splits)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
-func (db *ExpenseDBMySQL) DeleteExpense(expenseID string) error {
+func (db *ExpenseDBMySQL) DeleteExpense(ctx context.Context, expenseID string) (err error) {
+	defer observe(ctx, db.Observer, "expenses", "DeleteExpense")(&err)
+
 	// Виконання запиту до бази даних для видалення витрати за її ідентифікатором
 	query := "DELETE FROM expenses WHERE id = ?"
-	_, err := db.DB.GetDB().Exec(query, expenseID)
+	_, err = db.DB.GetDB().ExecContext(ctx, query, expenseID)
 	if err != nil {
 		return err
 	}
@@ -70,13 +180,53 @@ func (db *ExpenseDBMySQL) DeleteExpense(expenseID string) error {
 	return nil
 }
 
-func (db *ExpenseDBMySQL) UpdateUserExpenses(expense models.Expense) error {
-	// Виконання запиту до бази даних для оновлення витрати
-	query := "UPDATE expenses SET amount = ?, category = ?, date = ? WHERE id = ?"
-	_, err := db.DB.GetDB().Exec(query, expense.Amount, expense.Category, expense.Date, expense.ID)
+func (db *ExpenseDBMySQL) UpdateUserExpenses(ctx context.Context, expense models.Expense) (err error) {
+	defer observe(ctx, db.Observer, "expenses", "UpdateUserExpenses")(&err)
+
+	// Оновлення витрати і заміна її розподілу в одній транзакції
+	tx, err := db.DB.GetDB().BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
+	defer tx.Rollback()
+
+	query := "UPDATE expenses SET amount = ?, category = ?, date = ?, kind = ?, account_id = ?, paid_by = ?, split_method = ? WHERE id = ?"
+	_, err = tx.ExecContext(ctx, query, expense.Amount, expense.Category, expense.Date, expense.Kind, nullableID(expense.AccountID),
+		nullableID(expense.PaidBy), nullableString(expense.SplitMethod), expense.ID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, "DELETE FROM expense_splits WHERE expense_id = ?", expense.ID)
+	if err != nil {
+		return err
+	}
+
+	err = insertSplits(ctx, tx, expense.ID, expense.Splits)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func insertSplits(ctx context.Context, tx *sql.Tx, expenseID int, splits []models.ExpenseSplit) error {
+	for _, split := range splits {
+		_, err := tx.ExecContext(ctx, "INSERT INTO expense_splits (expense_id, user_id, amount) VALUES (?, ?, ?)", expenseID, split.UserID, split.Amount)
+		if err != nil {
+			return err
+		}
+	}
 
 	return nil
 }
+
+// nullableString перетворює порожній рядок на NULL
+func nullableString(value string) sql.NullString {
+	return sql.NullString{String: value, Valid: value != ""}
+}
+
+// nullableID перетворює нульовий ідентифікатор на NULL для необов'язкових зовнішніх ключів
+func nullableID(id int) sql.NullInt64 {
+	return sql.NullInt64{Int64: int64(id), Valid: id != 0}
+}
@@ -0,0 +1,40 @@
+package drepo
+
+import (
+	"context"
+	"database/sql"
+
+	_ "github.com/go-sql-driver/mysql"
+)
+
+// --------------------------- Перевірки стану бази даних (MySQL) ---------------------------
+
+// інтерфейс DatabaseH описується в тому ж файлі що і використовується
+type DatabaseH interface {
+	GetDB() *sql.DB
+}
+
+type HealthDBMySQL struct {
+	DB DatabaseH
+}
+
+func NewHealthDBMySQL(DB DatabaseH) *HealthDBMySQL {
+	return &HealthDBMySQL{DB}
+}
+
+func (db *HealthDBMySQL) Ping(ctx context.Context) error {
+	return db.DB.GetDB().PingContext(ctx)
+}
+
+// SchemaVersion повертає версію схеми з таблиці schema_migrations, яку веде golang-migrate;
+// dirty означає, що остання міграція завершилася з помилкою
+func (db *HealthDBMySQL) SchemaVersion(ctx context.Context) (int, bool, error) {
+	var version int
+	var dirty bool
+	err := db.DB.GetDB().QueryRowContext(ctx, "SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&version, &dirty)
+	if err != nil {
+		return 0, false, err
+	}
+
+	return version, dirty, nil
+}
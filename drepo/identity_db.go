@@ -0,0 +1,66 @@
+package drepo
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/ChomuCake/uni-golang-labs/models"
+	_ "github.com/go-sql-driver/mysql"
+)
+
+// --------------------------- Логіка роботи із зовнішніми обліковими записами OIDC (MySQL) ---------------------------
+
+// інтерфейс DatabaseI описується в тому ж файлі що і використовується
+type DatabaseI interface {
+	GetDB() *sql.DB
+}
+
+type IdentityDBMySQL struct {
+	Observer QueryObserver // необов'язковий, nil - без вимірювань
+	DB       DatabaseI
+}
+
+func NewIdentityDBMySQL(DB DatabaseI) *IdentityDBMySQL {
+	return &IdentityDBMySQL{DB: DB}
+}
+
+// GetUserByIdentity шукає користувача, прив'язаного до зовнішнього облікового запису; sql.ErrNoRows - не прив'язано
+func (db *IdentityDBMySQL) GetUserByIdentity(ctx context.Context, issuer, subject string) (user models.User, err error) {
+	defer observe(ctx, db.Observer, "user_identities", "GetUserByIdentity")(&err)
+
+	err = db.DB.GetDB().QueryRowContext(ctx, `
+		SELECT u.id, u.username, COALESCE(u.email, ''), u.time_zone, u.token_version, u.totp_enabled
+		FROM user_identities i
+		JOIN users u ON u.id = i.user_id
+		WHERE i.issuer = ? AND i.subject = ? AND u.deleted_at IS NULL`, issuer, subject).
+		Scan(&user.ID, &user.Username, &user.Email, &user.TimeZone, &user.TokenVersion, &user.TwoFactorEnabled)
+	return user, err
+}
+
+// AddUserWithIdentity в одній транзакції створює користувача без пароля і прив'язує до нього
+// зовнішній обліковий запис; повертає айді нового користувача
+func (db *IdentityDBMySQL) AddUserWithIdentity(ctx context.Context, user models.User, issuer, subject string) (userID int, err error) {
+	defer observe(ctx, db.Observer, "user_identities", "AddUserWithIdentity")(&err)
+
+	tx, err := db.DB.GetDB().BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, "INSERT INTO users(username, password, email, time_zone) VALUES(?, '', NULLIF(?, ''), ?)", user.Username, user.Email, user.TimeZone)
+	if err != nil {
+		return 0, err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	_, err = tx.ExecContext(ctx, "INSERT INTO user_identities (user_id, issuer, subject) VALUES (?, ?, ?)", id, issuer, subject)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(id), tx.Commit()
+}
@@ -0,0 +1,148 @@
+package drepo
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/ChomuCake/uni-golang-labs/models"
+	_ "github.com/go-sql-driver/mysql"
+)
+
+// --------------------------- Логіка роботи з даними для спільних журналів (MySQL) ---------------------------
+
+// інтерфейс DatabaseL описується в тому ж файлі що і використовується
+type DatabaseL interface {
+	GetDB() *sql.DB
+}
+
+type LedgerDBMySQL struct {
+	Observer QueryObserver // необов'язковий, nil - без вимірювань
+	DB       DatabaseL
+}
+
+func NewLedgerDBMySQL(DB DatabaseL) *LedgerDBMySQL {
+	return &LedgerDBMySQL{DB: DB}
+}
+
+func (db *LedgerDBMySQL) AddLedger(ctx context.Context, ledger models.Ledger) (id int, err error) {
+	defer observe(ctx, db.Observer, "ledgers", "AddLedger")(&err)
+
+	// Журнал і членство власника створюються в одній транзакції
+	tx, err := db.DB.GetDB().BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, "INSERT INTO ledgers (name, owner_id) VALUES (?, ?)", ledger.Name, ledger.OwnerID)
+	if err != nil {
+		return 0, err
+	}
+
+	ledgerID, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	_, err = tx.ExecContext(ctx, "INSERT INTO ledger_members (ledger_id, user_id, role) VALUES (?, ?, ?)", ledgerID, ledger.OwnerID, models.RoleOwner)
+	if err != nil {
+		return 0, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	return int(ledgerID), nil
+}
+
+func (db *LedgerDBMySQL) GetUserLedgers(ctx context.Context, userID int) (ledgers []models.Ledger, err error) {
+	defer observe(ctx, db.Observer, "ledgers", "GetUserLedgers")(&err)
+
+	// Виконання запиту до бази даних для отримання журналів, учасником яких є користувач
+	query := `SELECT l.id, l.name, l.owner_id, m.role FROM ledgers l
+		JOIN ledger_members m ON m.ledger_id = l.id
+		WHERE m.user_id = ?`
+	rows, err := db.DB.GetDB().QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var ledger models.Ledger
+		err := rows.Scan(&ledger.ID, &ledger.Name, &ledger.OwnerID, &ledger.Role)
+		if err != nil {
+			return nil, err
+		}
+		ledgers = append(ledgers, ledger)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return ledgers, nil
+}
+
+func (db *LedgerDBMySQL) GetMemberRole(ctx context.Context, ledgerID, userID int) (role string, err error) {
+	defer observe(ctx, db.Observer, "ledger_members", "GetMemberRole")(&err)
+
+	err = db.DB.GetDB().QueryRowContext(ctx, "SELECT role FROM ledger_members WHERE ledger_id = ? AND user_id = ?", ledgerID, userID).Scan(&role)
+	if err != nil {
+		return "", err
+	}
+	return role, nil
+}
+
+func (db *LedgerDBMySQL) GetLedgerMembers(ctx context.Context, ledgerID int) (members []models.LedgerMember, err error) {
+	defer observe(ctx, db.Observer, "ledger_members", "GetLedgerMembers")(&err)
+
+	query := `SELECT m.ledger_id, m.user_id, u.username, m.role FROM ledger_members m
+		JOIN users u ON u.id = m.user_id
+		WHERE m.ledger_id = ?`
+	rows, err := db.DB.GetDB().QueryContext(ctx, query, ledgerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var member models.LedgerMember
+		err := rows.Scan(&member.LedgerID, &member.UserID, &member.Username, &member.Role)
+		if err != nil {
+			return nil, err
+		}
+		members = append(members, member)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return members, nil
+}
+
+func (db *LedgerDBMySQL) AddMember(ctx context.Context, member models.LedgerMember) (err error) {
+	defer observe(ctx, db.Observer, "ledger_members", "AddMember")(&err)
+
+	// Повторне запрошення оновлює роль наявного учасника
+	query := "INSERT INTO ledger_members (ledger_id, user_id, role) VALUES (?, ?, ?) ON DUPLICATE KEY UPDATE role = VALUES(role)"
+	_, err = db.DB.GetDB().ExecContext(ctx, query, member.LedgerID, member.UserID, member.Role)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *LedgerDBMySQL) RemoveMember(ctx context.Context, ledgerID, userID int) (err error) {
+	defer observe(ctx, db.Observer, "ledger_members", "RemoveMember")(&err)
+
+	_, err = db.DB.GetDB().ExecContext(ctx, "DELETE FROM ledger_members WHERE ledger_id = ? AND user_id = ?", ledgerID, userID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,42 @@
+package drepo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("github.com/ChomuCake/uni-golang-labs/drepo")
+
+// QueryObserver отримує тривалість кожного запиту репозиторію (реалізується пакетом metrics)
+type QueryObserver interface {
+	ObserveQuery(repository, query string, duration time.Duration, err error)
+}
+
+// observe починає спан і вимірювання запиту; повернену функцію слід викликати через defer
+// з адресою іменованого результату err, щоб врахувати помилку запиту
+func observe(ctx context.Context, o QueryObserver, repository, query string) func(err *error) {
+	_, span := tracer.Start(ctx, repository+"."+query,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.DBSystemMySQL, semconv.DBSQLTable(repository), semconv.DBOperation(query)))
+
+	start := time.Now()
+	return func(err *error) {
+		// Відсутність запису - звичайна відповідь бази, а не збій
+		if *err != nil && !errors.Is(*err, sql.ErrNoRows) {
+			span.RecordError(*err)
+			span.SetStatus(codes.Error, (*err).Error())
+		}
+		span.End()
+
+		if o != nil {
+			o.ObserveQuery(repository, query, time.Since(start), *err)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package drepo
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/ChomuCake/uni-golang-labs/models"
+	_ "github.com/go-sql-driver/mysql"
+)
+
+// --------------------------- Логіка роботи з токенами відновлення пароля (MySQL) ---------------------------
+
+// інтерфейс DatabaseP описується в тому ж файлі що і використовується
+type DatabaseP interface {
+	GetDB() *sql.DB
+}
+
+type PasswordResetDBMySQL struct {
+	Observer QueryObserver // необов'язковий, nil - без вимірювань
+	DB       DatabaseP
+}
+
+func NewPasswordResetDBMySQL(DB DatabaseP) *PasswordResetDBMySQL {
+	return &PasswordResetDBMySQL{DB: DB}
+}
+
+func (db *PasswordResetDBMySQL) AddResetToken(ctx context.Context, userID int, tokenHash string, expiresAt time.Time) (err error) {
+	defer observe(ctx, db.Observer, "password_reset_tokens", "AddResetToken")(&err)
+
+	_, err = db.DB.GetDB().ExecContext(ctx, "INSERT INTO password_reset_tokens (user_id, token_hash, expires_at) VALUES (?, ?, ?)", userID, tokenHash, expiresAt)
+	return err
+}
+
+// GetResetToken шукає токен за хешем; sql.ErrNoRows - такого токена не видавали
+func (db *PasswordResetDBMySQL) GetResetToken(ctx context.Context, tokenHash string) (token models.PasswordResetToken, err error) {
+	defer observe(ctx, db.Observer, "password_reset_tokens", "GetResetToken")(&err)
+
+	err = db.DB.GetDB().QueryRowContext(ctx, "SELECT id, user_id, expires_at, used_at IS NOT NULL FROM password_reset_tokens WHERE token_hash = ?", tokenHash).
+		Scan(&token.ID, &token.UserID, &token.ExpiresAt, &token.Used)
+	return token, err
+}
+
+// UseResetToken в одній транзакції позначає токен використаним, встановлює новий пароль,
+// відкликає видані JWT (token_version) і знімає блокування входу. Інші невикористані токени
+// користувача теж анулюються. Якщо токен уже використано паралельним запитом, повертає sql.ErrNoRows
+func (db *PasswordResetDBMySQL) UseResetToken(ctx context.Context, token models.PasswordResetToken, password string) (err error) {
+	defer observe(ctx, db.Observer, "password_reset_tokens", "UseResetToken")(&err)
+
+	tx, err := db.DB.GetDB().BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	result, err := tx.ExecContext(ctx, "UPDATE password_reset_tokens SET used_at = ? WHERE id = ? AND used_at IS NULL", now, token.ID)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected != 1 {
+		return sql.ErrNoRows
+	}
+
+	_, err = tx.ExecContext(ctx, "UPDATE password_reset_tokens SET used_at = ? WHERE user_id = ? AND used_at IS NULL", now, token.UserID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE users
+		SET password = ?, token_version = token_version + 1
+		WHERE id = ? AND deleted_at IS NULL`, password, token.UserID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, "DELETE FROM login_failures WHERE username = (SELECT username FROM users WHERE id = ?)", token.UserID)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,151 @@
+package drepo
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/ChomuCake/uni-golang-labs/models"
+	_ "github.com/go-sql-driver/mysql"
+)
+
+// --------------------------- Агрегація витрат для звітів (MySQL) ---------------------------
+
+// інтерфейс DatabaseR описується в тому ж файлі що і використовується
+type DatabaseR interface {
+	GetDB() *sql.DB
+}
+
+type ReportDBMySQL struct {
+	Observer QueryObserver // необов'язковий, nil - без вимірювань
+	DB       DatabaseR
+}
+
+func NewReportDBMySQL(DB DatabaseR) *ReportDBMySQL {
+	return &ReportDBMySQL{DB: DB}
+}
+
+// userSpending - витрати користувача для звітів: особисті витрати повністю, а спільні витрати
+// журналів - його часткою з expense_splits (незалежно від того, хто платив). Запит має два
+// параметри, обидва - id користувача; рахунок частки невідомий (NULL), бо платив, можливо, інший
+const userSpending = `SELECT id, amount, category, date, account_id, NULL AS ledger_id FROM expenses
+		WHERE user_id = ? AND ledger_id IS NULL AND kind = 'expense'
+	UNION ALL
+	SELECT e.id, s.amount, e.category, e.date, NULL, e.ledger_id FROM expense_splits s
+		JOIN expenses e ON e.id = s.expense_id
+		WHERE s.user_id = ? AND e.ledger_id IS NOT NULL AND e.kind = 'expense'`
+
+// GetSpendingByRanges повертає суму витрат користувача (див. userSpending) для кожного проміжку (у тому ж порядку).
+// Проміжки передаються як похідна таблиця, тож інтервали без витрат повертаються з нулем, а межі
+// днів і місяців (разом з переходами на літній час) уже пораховані у часовому поясі користувача
+func (db *ReportDBMySQL) GetSpendingByRanges(ctx context.Context, userID int, category string, ranges []models.DateRange) (totals []int, err error) {
+	defer observe(ctx, db.Observer, "expenses", "GetSpendingByRanges")(&err)
+
+	if len(ranges) == 0 {
+		return []int{}, nil
+	}
+
+	var buckets strings.Builder
+	args := make([]interface{}, 0, len(ranges)*3+2)
+	for i, r := range ranges {
+		if i > 0 {
+			buckets.WriteString(" UNION ALL ")
+		}
+		buckets.WriteString("SELECT ? AS idx, ? AS start_at, ? AS end_at")
+		args = append(args, i, r.Start.UTC(), r.End.UTC())
+	}
+
+	query := `SELECT b.idx, COALESCE(SUM(e.amount), 0) FROM (` + buckets.String() + `) b
+		LEFT JOIN (` + userSpending + `) e ON e.date >= b.start_at AND e.date < b.end_at`
+	args = append(args, userID, userID)
+	if category != "" {
+		query += " AND e.category = ?"
+		args = append(args, category)
+	}
+	query += " GROUP BY b.idx ORDER BY b.idx"
+
+	rows, err := db.DB.GetDB().QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	totals = make([]int, len(ranges))
+	for rows.Next() {
+		var idx, total int
+		err := rows.Scan(&idx, &total)
+		if err != nil {
+			return nil, err
+		}
+		totals[idx] = total
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return totals, nil
+}
+
+// GetCategoryTotals повертає суми витрат користувача (див. userSpending) за категоріями у проміжку [start, end),
+// від найбільшої до найменшої
+func (db *ReportDBMySQL) GetCategoryTotals(ctx context.Context, userID int, period models.DateRange) (totals []models.CategoryTotal, err error) {
+	defer observe(ctx, db.Observer, "expenses", "GetCategoryTotals")(&err)
+
+	query := `SELECT category, SUM(amount) AS total FROM (` + userSpending + `) e
+		WHERE date >= ? AND date < ?
+		GROUP BY category ORDER BY total DESC, category`
+	rows, err := db.DB.GetDB().QueryContext(ctx, query, userID, userID, period.Start.UTC(), period.End.UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var total models.CategoryTotal
+		err := rows.Scan(&total.Category, &total.Total)
+		if err != nil {
+			return nil, err
+		}
+		totals = append(totals, total)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return totals, nil
+}
+
+// GetExpensesInRange повертає витрати користувача (див. userSpending) у проміжку [start, end) за датою;
+// для спільних витрат Amount - частка користувача, а LedgerID вказує журнал
+func (db *ReportDBMySQL) GetExpensesInRange(ctx context.Context, userID int, period models.DateRange) (expenses []models.Expense, err error) {
+	defer observe(ctx, db.Observer, "expenses", "GetExpensesInRange")(&err)
+
+	query := `SELECT id, amount, category, date, account_id, ledger_id FROM (` + userSpending + `) e
+		WHERE date >= ? AND date < ?
+		ORDER BY date, id`
+	rows, err := db.DB.GetDB().QueryContext(ctx, query, userID, userID, period.Start.UTC(), period.End.UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		expense := models.Expense{UserID: userID, Kind: models.KindExpense}
+		var accountID, ledgerID sql.NullInt64
+		err := rows.Scan(&expense.ID, &expense.Amount, &expense.Category, &expense.Date, &accountID, &ledgerID)
+		if err != nil {
+			return nil, err
+		}
+		expense.AccountID = int(accountID.Int64)
+		expense.LedgerID = int(ledgerID.Int64)
+		expenses = append(expenses, expense)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return expenses, nil
+}
@@ -0,0 +1,99 @@
+package drepo
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/ChomuCake/uni-golang-labs/models"
+	_ "github.com/go-sql-driver/mysql"
+)
+
+// --------------------------- Логіка роботи з даними для розрахунків між учасниками (MySQL) ---------------------------
+
+// інтерфейс DatabaseS описується в тому ж файлі що і використовується
+type DatabaseS interface {
+	GetDB() *sql.DB
+}
+
+type SettlementDBMySQL struct {
+	Observer QueryObserver // необов'язковий, nil - без вимірювань
+	DB       DatabaseS
+}
+
+func NewSettlementDBMySQL(DB DatabaseS) *SettlementDBMySQL {
+	return &SettlementDBMySQL{DB: DB}
+}
+
+func (db *SettlementDBMySQL) GetLedgerDebts(ctx context.Context, ledgerID int) (debts []models.Balance, err error) {
+	defer observe(ctx, db.Observer, "expense_splits", "GetLedgerDebts")(&err)
+
+	// Кожен учасник винен платнику свою частку витрати (частка самого платника не враховується);
+	// доходи в журналі на борги не впливають
+	query := `SELECT s.user_id, e.paid_by, SUM(s.amount) FROM expense_splits s
+		JOIN expenses e ON e.id = s.expense_id
+		WHERE e.ledger_id = ? AND e.kind = 'expense' AND s.user_id <> e.paid_by
+		GROUP BY s.user_id, e.paid_by`
+	rows, err := db.DB.GetDB().QueryContext(ctx, query, ledgerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var debt models.Balance
+		err := rows.Scan(&debt.FromUserID, &debt.ToUserID, &debt.Amount)
+		if err != nil {
+			return nil, err
+		}
+		debts = append(debts, debt)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return debts, nil
+}
+
+func (db *SettlementDBMySQL) GetLedgerSettlements(ctx context.Context, ledgerID int) (settlements []models.Settlement, err error) {
+	defer observe(ctx, db.Observer, "settlements", "GetLedgerSettlements")(&err)
+
+	query := "SELECT id, ledger_id, from_user_id, to_user_id, amount, date FROM settlements WHERE ledger_id = ? ORDER BY date"
+	rows, err := db.DB.GetDB().QueryContext(ctx, query, ledgerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var settlement models.Settlement
+		err := rows.Scan(&settlement.ID, &settlement.LedgerID, &settlement.FromUserID, &settlement.ToUserID, &settlement.Amount, &settlement.Date)
+		if err != nil {
+			return nil, err
+		}
+		settlements = append(settlements, settlement)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return settlements, nil
+}
+
+func (db *SettlementDBMySQL) AddSettlement(ctx context.Context, settlement models.Settlement) (id int, err error) {
+	defer observe(ctx, db.Observer, "settlements", "AddSettlement")(&err)
+
+	query := "INSERT INTO settlements (ledger_id, from_user_id, to_user_id, amount, date) VALUES (?, ?, ?, ?, ?)"
+	res, err := db.DB.GetDB().ExecContext(ctx, query, settlement.LedgerID, settlement.FromUserID, settlement.ToUserID, settlement.Amount, settlement.Date)
+	if err != nil {
+		return 0, err
+	}
+
+	settlementID, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(settlementID), nil
+}
@@ -0,0 +1,136 @@
+package drepo
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/ChomuCake/uni-golang-labs/models"
+	_ "github.com/go-sql-driver/mysql"
+)
+
+// --------------------------- Логіка роботи з двофакторною автентифікацією (MySQL) ---------------------------
+
+// інтерфейс DatabaseT описується в тому ж файлі що і використовується
+type DatabaseT interface {
+	GetDB() *sql.DB
+}
+
+type TwoFactorDBMySQL struct {
+	Observer QueryObserver // необов'язковий, nil - без вимірювань
+	DB       DatabaseT
+}
+
+func NewTwoFactorDBMySQL(DB DatabaseT) *TwoFactorDBMySQL {
+	return &TwoFactorDBMySQL{DB: DB}
+}
+
+// GetTOTP повертає налаштування 2FA; sql.ErrNoRows - користувача немає
+func (db *TwoFactorDBMySQL) GetTOTP(ctx context.Context, userID int) (state models.TOTPState, err error) {
+	defer observe(ctx, db.Observer, "users", "GetTOTP")(&err)
+
+	var secret sql.NullString
+	err = db.DB.GetDB().QueryRowContext(ctx, "SELECT totp_secret, totp_enabled, totp_last_step FROM users WHERE id = ? AND deleted_at IS NULL", userID).
+		Scan(&secret, &state.Enabled, &state.LastStep)
+	if err != nil {
+		return models.TOTPState{}, err
+	}
+	state.Secret = secret.String
+
+	return state, nil
+}
+
+// SetTOTPSecret зберігає новий секрет, який ще треба підтвердити кодом (2FA залишається вимкненою)
+func (db *TwoFactorDBMySQL) SetTOTPSecret(ctx context.Context, userID int, secret string) (err error) {
+	defer observe(ctx, db.Observer, "users", "SetTOTPSecret")(&err)
+
+	_, err = db.DB.GetDB().ExecContext(ctx, "UPDATE users SET totp_secret = ?, totp_enabled = FALSE, totp_last_step = 0 WHERE id = ? AND deleted_at IS NULL", secret, userID)
+	return err
+}
+
+// EnableTOTP вмикає 2FA і замінює коди відновлення новими (зберігаються хеші);
+// step - інтервал коду, яким підтверджено налаштування, щоб його не можна було використати для входу
+func (db *TwoFactorDBMySQL) EnableTOTP(ctx context.Context, userID int, step int64, recoveryHashes []string) (err error) {
+	defer observe(ctx, db.Observer, "users", "EnableTOTP")(&err)
+
+	tx, err := db.DB.GetDB().BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, "UPDATE users SET totp_enabled = TRUE, totp_last_step = ? WHERE id = ? AND deleted_at IS NULL", step, userID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, "DELETE FROM recovery_codes WHERE user_id = ?", userID)
+	if err != nil {
+		return err
+	}
+
+	for _, hash := range recoveryHashes {
+		_, err = tx.ExecContext(ctx, "INSERT INTO recovery_codes (user_id, code_hash) VALUES (?, ?)", userID, hash)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// DisableTOTP вимикає 2FA, стирає секрет і коди відновлення
+func (db *TwoFactorDBMySQL) DisableTOTP(ctx context.Context, userID int) (err error) {
+	defer observe(ctx, db.Observer, "users", "DisableTOTP")(&err)
+
+	tx, err := db.DB.GetDB().BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, "UPDATE users SET totp_secret = NULL, totp_enabled = FALSE, totp_last_step = 0 WHERE id = ?", userID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, "DELETE FROM recovery_codes WHERE user_id = ?", userID)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// UseTOTPStep запам'ятовує використаний інтервал коду. Повертає false, якщо цей або пізніший
+// інтервал уже використано (повторне введення того самого коду чи паралельний запит)
+func (db *TwoFactorDBMySQL) UseTOTPStep(ctx context.Context, userID int, step int64) (ok bool, err error) {
+	defer observe(ctx, db.Observer, "users", "UseTOTPStep")(&err)
+
+	result, err := db.DB.GetDB().ExecContext(ctx, "UPDATE users SET totp_last_step = ? WHERE id = ? AND totp_last_step < ?", step, userID, step)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected == 1, nil
+}
+
+// UseRecoveryCode позначає код відновлення використаним; false - такого невикористаного коду немає
+func (db *TwoFactorDBMySQL) UseRecoveryCode(ctx context.Context, userID int, codeHash string) (ok bool, err error) {
+	defer observe(ctx, db.Observer, "recovery_codes", "UseRecoveryCode")(&err)
+
+	result, err := db.DB.GetDB().ExecContext(ctx, "UPDATE recovery_codes SET used_at = ? WHERE user_id = ? AND code_hash = ? AND used_at IS NULL", time.Now(), userID, codeHash)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected == 1, nil
+}
@@ -1,8 +1,10 @@
 package drepo
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"time"
 
 	"github.com/ChomuCake/uni-golang-labs/models"
 	_ "github.com/go-sql-driver/mysql"
@@ -16,21 +18,25 @@ type DatabaseU interface {
 }
 
 type UserDBMySQL struct {
-	DB DatabaseU
+	Observer QueryObserver // необов'язковий, nil - без вимірювань
+	DB       DatabaseU
 }
 
 func NewUserDBMySQL(DB DatabaseU) *UserDBMySQL {
-	return &UserDBMySQL{DB}
+	return &UserDBMySQL{DB: DB}
 }
 
-func (db *UserDBMySQL) AddUser(user models.User) error {
-	stmt, err := db.DB.GetDB().Prepare("INSERT INTO users(username, password) VALUES(?, ?)")
+func (db *UserDBMySQL) AddUser(ctx context.Context, user models.User) (err error) {
+	defer observe(ctx, db.Observer, "users", "AddUser")(&err)
+
+	// Порожня адреса зберігається як NULL, щоб не порушувати унікальність email
+	stmt, err := db.DB.GetDB().PrepareContext(ctx, "INSERT INTO users(username, password, email, time_zone) VALUES(?, ?, NULLIF(?, ''), ?)")
 	if err != nil {
 		return err
 	}
 	defer stmt.Close()
 
-	_, err = stmt.Exec(user.Username, user.Password)
+	_, err = stmt.ExecContext(ctx, user.Username, user.Password, user.Email, user.TimeZone)
 	if err != nil {
 		return err
 	}
@@ -38,31 +44,45 @@ func (db *UserDBMySQL) AddUser(user models.User) error {
 	return nil
 }
 
-func (db *UserDBMySQL) GetUserByUsernameAndPassword(username, password string) (models.User, error) {
-	var user models.User
-	err := db.DB.GetDB().QueryRow("SELECT id, username FROM users WHERE username = ? AND password = ?", username, password).Scan(&user.ID, &user.Username)
+func (db *UserDBMySQL) GetUserByUsernameAndPassword(ctx context.Context, username, password string) (user models.User, err error) {
+	defer observe(ctx, db.Observer, "users", "GetUserByUsernameAndPassword")(&err)
+
+	err = db.DB.GetDB().QueryRowContext(ctx, "SELECT id, username, COALESCE(email, ''), time_zone, token_version, totp_enabled FROM users WHERE username = ? AND password = ? AND deleted_at IS NULL", username, password).
+		Scan(&user.ID, &user.Username, &user.Email, &user.TimeZone, &user.TokenVersion, &user.TwoFactorEnabled)
+	if err != nil {
+		return user, err
+	}
+	return user, nil
+}
+
+func (db *UserDBMySQL) GetUserByUsername(ctx context.Context, username string) (user models.User, err error) {
+	defer observe(ctx, db.Observer, "users", "GetUserByUsername")(&err)
+
+	err = db.DB.GetDB().QueryRowContext(ctx, "SELECT id, username, COALESCE(email, ''), time_zone FROM users WHERE username = ? AND deleted_at IS NULL", username).Scan(&user.ID, &user.Username, &user.Email, &user.TimeZone)
 	if err != nil {
 		return user, err
 	}
 	return user, nil
 }
 
-func (db *UserDBMySQL) GetUserByUsername(username string) (models.User, error) {
-	var user models.User
-	err := db.DB.GetDB().QueryRow("SELECT id, username FROM users WHERE username = ?", username).Scan(&user.ID, &user.Username)
+func (db *UserDBMySQL) GetUserByEmail(ctx context.Context, email string) (user models.User, err error) {
+	defer observe(ctx, db.Observer, "users", "GetUserByEmail")(&err)
+
+	err = db.DB.GetDB().QueryRowContext(ctx, "SELECT id, username, email, time_zone FROM users WHERE email = ? AND deleted_at IS NULL", email).Scan(&user.ID, &user.Username, &user.Email, &user.TimeZone)
 	if err != nil {
 		return user, err
 	}
 	return user, nil
 }
 
-func (db *UserDBMySQL) GetUserByID(userID int) (models.User, error) {
+func (db *UserDBMySQL) GetUserByID(ctx context.Context, userID int) (user models.User, err error) {
+	defer observe(ctx, db.Observer, "users", "GetUserByID")(&err)
+
 	// Виконання запиту до бази даних для отримання користувача за його ідентифікатором
-	query := "SELECT id, username FROM users WHERE id = ?"
-	row := db.DB.GetDB().QueryRow(query, userID)
+	query := "SELECT id, username, COALESCE(email, ''), time_zone, token_version, totp_enabled FROM users WHERE id = ? AND deleted_at IS NULL"
+	row := db.DB.GetDB().QueryRowContext(ctx, query, userID)
 
-	var user models.User
-	err := row.Scan(&user.ID, &user.Username)
+	err = row.Scan(&user.ID, &user.Username, &user.Email, &user.TimeZone, &user.TokenVersion, &user.TwoFactorEnabled)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return models.User{}, fmt.Errorf("user not found")
@@ -72,3 +92,222 @@ func (db *UserDBMySQL) GetUserByID(userID int) (models.User, error) {
 
 	return user, nil
 }
+
+// GetLoginState повертає лічильник невдалих входів і блокування для імені; стан ведеться
+// і для імен, яких немає в users, тож для них так само повертається нульовий або накопичений стан
+func (db *UserDBMySQL) GetLoginState(ctx context.Context, username string) (state models.LoginState, err error) {
+	defer observe(ctx, db.Observer, "login_failures", "GetLoginState")(&err)
+
+	var lockedUntil sql.NullTime
+	err = db.DB.GetDB().QueryRowContext(ctx, "SELECT failed_logins, locked_until FROM login_failures WHERE username = ?", username).Scan(&state.FailedLogins, &lockedUntil)
+	if err == sql.ErrNoRows {
+		return models.LoginState{}, nil
+	}
+	if err != nil {
+		return models.LoginState{}, err
+	}
+	state.LockedUntil = lockedUntil.Time
+
+	return state, nil
+}
+
+// RecordLoginFailure атомарно збільшує лічильник невдалих входів і повертає нове значення
+func (db *UserDBMySQL) RecordLoginFailure(ctx context.Context, username string) (failures int, err error) {
+	defer observe(ctx, db.Observer, "login_failures", "RecordLoginFailure")(&err)
+
+	// Одночасні невдалі спроби не повинні загубити інкремент, тому читання відбувається в тій самій транзакції
+	tx, err := db.DB.GetDB().BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO login_failures (username, failed_logins) VALUES (?, 1)
+		ON DUPLICATE KEY UPDATE failed_logins = failed_logins + 1`, username)
+	if err != nil {
+		return 0, err
+	}
+
+	err = tx.QueryRowContext(ctx, "SELECT failed_logins FROM login_failures WHERE username = ?", username).Scan(&failures)
+	if err != nil {
+		return 0, err
+	}
+
+	return failures, tx.Commit()
+}
+
+// LockUser блокує вхід для імені; викликається після RecordLoginFailure, тож запис уже існує
+func (db *UserDBMySQL) LockUser(ctx context.Context, username string, until time.Time) (err error) {
+	defer observe(ctx, db.Observer, "login_failures", "LockUser")(&err)
+
+	_, err = db.DB.GetDB().ExecContext(ctx, "UPDATE login_failures SET locked_until = ? WHERE username = ?", until, username)
+	return err
+}
+
+// ResetLoginFailures знімає блокування після успішного входу
+func (db *UserDBMySQL) ResetLoginFailures(ctx context.Context, username string) (err error) {
+	defer observe(ctx, db.Observer, "login_failures", "ResetLoginFailures")(&err)
+
+	_, err = db.DB.GetDB().ExecContext(ctx, "DELETE FROM login_failures WHERE username = ?", username)
+	return err
+}
+
+// UpdateUser зберігає ім'я, адресу та часовий пояс користувача
+func (db *UserDBMySQL) UpdateUser(ctx context.Context, user models.User) (err error) {
+	defer observe(ctx, db.Observer, "users", "UpdateUser")(&err)
+
+	_, err = db.DB.GetDB().ExecContext(ctx, "UPDATE users SET username = ?, email = NULLIF(?, ''), time_zone = ? WHERE id = ? AND deleted_at IS NULL", user.Username, user.Email, user.TimeZone, user.ID)
+	return err
+}
+
+// ChangePassword змінює пароль і збільшує версію токенів; повертає нову версію
+func (db *UserDBMySQL) ChangePassword(ctx context.Context, userID int, password string) (version int, err error) {
+	defer observe(ctx, db.Observer, "users", "ChangePassword")(&err)
+
+	tx, err := db.DB.GetDB().BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, "UPDATE users SET password = ?, token_version = token_version + 1 WHERE id = ? AND deleted_at IS NULL", password, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	err = tx.QueryRowContext(ctx, "SELECT token_version FROM users WHERE id = ?", userID).Scan(&version)
+	if err != nil {
+		return 0, err
+	}
+
+	return version, tx.Commit()
+}
+
+// GetTokenVersion повертає поточну версію токенів; для видаленого користувача - sql.ErrNoRows
+func (db *UserDBMySQL) GetTokenVersion(ctx context.Context, userID int) (version int, err error) {
+	defer observe(ctx, db.Observer, "users", "GetTokenVersion")(&err)
+
+	err = db.DB.GetDB().QueryRowContext(ctx, "SELECT token_version FROM users WHERE id = ? AND deleted_at IS NULL", userID).Scan(&version)
+	return version, err
+}
+
+// DeleteUser видаляє особисті дані користувача (витрати без журналу, перекази, бюджети,
+// рахунки без спільних витрат, токени відновлення пароля, коди 2FA, прив'язки OIDC, токени
+// доступу) і членство в журналах; власні журнали з іншими учасниками передаються
+// найдавнішому учаснику (див. transferOwnedLedgers). Якщо на користувача ще посилаються спільні витрати, частки,
+// розрахунки чи журнали, запис users не видаляється (цього не дозволяють зовнішні ключі),
+// а знеособлюється: ім'я замінюється на "deleted#<id>", пароль і адреса стираються.
+// Повертає true, якщо запис було знеособлено, а не видалено
+func (db *UserDBMySQL) DeleteUser(ctx context.Context, userID int) (anonymized bool, err error) {
+	defer observe(ctx, db.Observer, "users", "DeleteUser")(&err)
+
+	tx, err := db.DB.GetDB().BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	err = transferOwnedLedgers(ctx, tx, userID)
+	if err != nil {
+		return false, err
+	}
+
+	// Частки особистих витрат видаляються каскадно (ON DELETE CASCADE)
+	for _, query := range []string{
+		"DELETE FROM expenses WHERE user_id = ? AND ledger_id IS NULL",
+		"DELETE FROM transfers WHERE user_id = ?",
+		"DELETE FROM budgets WHERE user_id = ?",
+		"DELETE FROM accounts WHERE user_id = ? AND id NOT IN (SELECT account_id FROM expenses)",
+		"DELETE FROM ledger_members WHERE user_id = ?",
+		"DELETE FROM password_reset_tokens WHERE user_id = ?",
+		"DELETE FROM recovery_codes WHERE user_id = ?",
+		"DELETE FROM user_identities WHERE user_id = ?",
+		"DELETE FROM access_tokens WHERE user_id = ?",
+	} {
+		_, err = tx.ExecContext(ctx, query, userID)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	var references int
+	err = tx.QueryRowContext(ctx, `
+		SELECT
+			(SELECT COUNT(*) FROM expenses WHERE user_id = ? OR paid_by = ?) +
+			(SELECT COUNT(*) FROM expense_splits WHERE user_id = ?) +
+			(SELECT COUNT(*) FROM settlements WHERE from_user_id = ? OR to_user_id = ?) +
+			(SELECT COUNT(*) FROM ledgers WHERE owner_id = ?) +
+			(SELECT COUNT(*) FROM accounts WHERE user_id = ?)`,
+		userID, userID, userID, userID, userID, userID, userID).Scan(&references)
+	if err != nil {
+		return false, err
+	}
+
+	if references == 0 {
+		_, err = tx.ExecContext(ctx, "DELETE FROM users WHERE id = ?", userID)
+	} else {
+		// Ім'я з # не проходить валідацію при реєстрації, тож не може збігтися з реальним користувачем
+		_, err = tx.ExecContext(ctx, `
+			UPDATE users
+			SET username = CONCAT('deleted#', id), password = '', email = NULL, time_zone = 'UTC',
+				totp_secret = NULL, totp_enabled = FALSE,
+				token_version = token_version + 1, deleted_at = ?
+			WHERE id = ?`, time.Now(), userID)
+		anonymized = true
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return anonymized, tx.Commit()
+}
+
+// transferOwnedLedgers передає кожен журнал користувача учаснику, який приєднався найраніше
+// (за однакового часу - редактору раніше за глядача), щоб журнал не лишився без власника.
+// Журнали без інших учасників лишаються за знеособленим записом
+func transferOwnedLedgers(ctx context.Context, tx *sql.Tx, userID int) error {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT l.id, (
+			SELECT m.user_id FROM ledger_members m
+			WHERE m.ledger_id = l.id AND m.user_id <> l.owner_id
+			ORDER BY m.joined_at, m.role = ?, m.user_id
+			LIMIT 1)
+		FROM ledgers l
+		WHERE l.owner_id = ?`, models.RoleViewer, userID)
+	if err != nil {
+		return err
+	}
+
+	// Результат читається повністю до оновлень: транзакція має одне з'єднання
+	successors := map[int]int{}
+	for rows.Next() {
+		var ledgerID int
+		var successor sql.NullInt64
+		err = rows.Scan(&ledgerID, &successor)
+		if err != nil {
+			rows.Close()
+			return err
+		}
+		if successor.Valid {
+			successors[ledgerID] = int(successor.Int64)
+		}
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	for ledgerID, successor := range successors {
+		_, err = tx.ExecContext(ctx, "UPDATE ledgers SET owner_id = ? WHERE id = ?", successor, ledgerID)
+		if err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx, "UPDATE ledger_members SET role = ? WHERE ledger_id = ? AND user_id = ?", models.RoleOwner, ledgerID, successor)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,6 @@
+// Сесія зберігається в HttpOnly cookie; зміни стану сервер приймає лише з CSRF-токеном
+// з cookie fintrack_csrf, повтореним у заголовку X-CSRF-Token
+function csrfToken() {
+  const match = document.cookie.match(/(?:^|; )fintrack_csrf=([^;]*)/);
+  return match ? match[1] : "";
+}
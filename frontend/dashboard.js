@@ -0,0 +1,105 @@
+function authOptions(extra) {
+  return Object.assign({ headers: { "X-CSRF-Token": csrfToken() } }, extra);
+}
+
+// Selected month as {month: "2006-01", from: "2006-01-01", to: "2006-01-31"}
+function selectedMonth() {
+  const month = document.getElementById("month").value;
+  const [year, mon] = month.split("-").map(Number);
+  const lastDay = new Date(Date.UTC(year, mon, 0)).getUTCDate();
+  return { month, from: `${month}-01`, to: `${month}-${String(lastDay).padStart(2, "0")}` };
+}
+
+// Charts are rendered by /reports/chart.svg; the request needs the session, so the SVG is inlined
+function loadChart(elementID, query) {
+  fetch("/reports/chart.svg?" + query, authOptions())
+    .then((response) => {
+      if (!response.ok) {
+        throw new Error("Failed to load chart");
+      }
+      return response.text();
+    })
+    .then((svg) => {
+      document.getElementById(elementID).innerHTML = svg;
+    })
+    .catch((error) => {
+      console.error("Error:", error);
+    });
+}
+
+function loadBudgets(month) {
+  fetch("/reports/budgets?month=" + month, authOptions())
+    .then((response) => response.json())
+    .then((progress) => {
+      const list = document.getElementById("budgets-list");
+      list.innerHTML = "";
+      progress.forEach((budget) => {
+        const row = document.createElement("tr");
+        [budget.category, budget.budget, budget.spent, budget.remaining, budget.percent + "%"].forEach((value) => {
+          const cell = document.createElement("td");
+          cell.innerText = value;
+          row.appendChild(cell);
+        });
+        list.appendChild(row);
+      });
+    })
+    .catch((error) => {
+      console.error("Error:", error);
+    });
+}
+
+function refresh() {
+  const { month, from, to } = selectedMonth();
+  loadChart("categories-chart", `type=categories&from=${from}&to=${to}`);
+  loadChart("monthly-chart", `type=monthly&to=${to}`);
+  loadChart("budgets-chart", `type=budgets&month=${month}`);
+  loadBudgets(month);
+}
+
+document.getElementById("budget-form").addEventListener("submit", (event) => {
+  event.preventDefault();
+  const budget = {
+    category: document.getElementById("budget-category").value,
+    amount: Number(document.getElementById("budget-amount").value),
+  };
+  fetch("/budgets", authOptions({ method: "PUT", body: JSON.stringify(budget) }))
+    .then((response) => {
+      if (response.ok) {
+        refresh();
+      } else {
+        alert("Failed to save budget");
+      }
+    })
+    .catch((error) => {
+      console.error("Error:", error);
+    });
+});
+
+// The statement endpoint needs the token, so the PDF is downloaded via fetch and saved from a blob
+function downloadStatement() {
+  const { month } = selectedMonth();
+  fetch("/reports/statement.pdf?month=" + month, authOptions())
+    .then((response) => {
+      if (!response.ok) {
+        throw new Error("Failed to download statement");
+      }
+      return response.blob();
+    })
+    .then((blob) => {
+      const link = document.createElement("a");
+      link.href = URL.createObjectURL(blob);
+      link.download = `statement-${month}.pdf`;
+      link.click();
+      URL.revokeObjectURL(link.href);
+    })
+    .catch((error) => {
+      console.error("Error:", error);
+    });
+}
+
+document.getElementById("refresh").addEventListener("click", refresh);
+document.getElementById("statement").addEventListener("click", downloadStatement);
+
+const now = new Date();
+document.getElementById("month").value = `${now.getFullYear()}-${String(now.getMonth() + 1).padStart(2, "0")}`;
+refresh();
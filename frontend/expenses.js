@@ -1,21 +1,40 @@
-// Function to save token in local storage
-function saveToken(token) {
-  localStorage.setItem("token", token);
+// Returns "?ledger=ID" (or "&ledger=ID") for the selected shared ledger
+function ledgerQuery(prefix) {
+  const ledgerID = document.getElementById("ledger").value;
+  return ledgerID ? `${prefix}ledger=${ledgerID}` : "";
 }
 
-// Function to retrieve token from local storage
-function getToken() {
-  return localStorage.getItem("token");
+// Fill the ledger selector with ledgers the user is a member of
+function fetchLedgers() {
+  const options = {
+    headers: {
+      "X-CSRF-Token": csrfToken(),
+    },
+  };
+  fetch("/ledgers", options)
+    .then((response) => response.json())
+    .then((ledgers) => {
+      const select = document.getElementById("ledger");
+      ledgers.forEach((ledger) => {
+        const option = document.createElement("option");
+        option.value = ledger.id;
+        option.innerText = `${ledger.name} (${ledger.role})`;
+        select.appendChild(option);
+      });
+    })
+    .catch((error) => {
+      console.error("Error:", error);
+    });
 }
 
 function deleteExpense(expenseID) {
   const options = {
     method: "DELETE",
     headers: {
-      Authorization: getToken(),
+      "X-CSRF-Token": csrfToken(),
     },
   };
-  fetch("/expenses/" + expenseID, options)
+  fetch("/expenses/" + expenseID + ledgerQuery("?"), options)
     .then((response) => {
       if (response.ok) {
         alert("Expense deleted successfully");
@@ -33,12 +52,14 @@ function deleteExpense(expenseID) {
 function fetchExpenses(sortBy) {
   let url = "/expenses";
   if (sortBy) {
-    url += `?sort=${sortBy}`;
+    url += `?sort=${sortBy}` + ledgerQuery("&");
+  } else {
+    url += ledgerQuery("?");
   }
 
   const options = {
     headers: {
-      Authorization: getToken(),
+      "X-CSRF-Token": csrfToken(),
     },
   };
 
@@ -99,16 +120,20 @@ document
       category: formData.get("category"),
       amount: parseInt(formData.get("amount")),
     };
+    // Date is optional, the server uses the current time when it's omitted
+    if (formData.get("date")) {
+      data.date = formData.get("date");
+    }
     const options = {
       method: "POST",
       headers: {
         "Content-Type": "application/json",
-        Authorization: getToken(),
+        "X-CSRF-Token": csrfToken(),
       },
       body: JSON.stringify(data),
     };
 
-    fetch(form.action, options)
+    fetch(form.action + ledgerQuery("?"), options)
       .then((response) => {
         if (response.ok) {
           alert("Expenses add successful");
@@ -128,6 +153,26 @@ document.getElementById("get-expenses").addEventListener("click", function () {
 });
 
 function openUpdateExpensePage(expenseID) {
-    window.location.href = "expensesupdate.html?expenseID=" + expenseID;
+    window.location.href = "expensesupdate.html?expenseID=" + expenseID + ledgerQuery("&");
   }
+
+// Вихід: HttpOnly cookie сесії може видалити лише сервер
+function logout() {
+  fetch("/logout", {
+    method: "POST",
+    headers: {
+      "X-CSRF-Token": csrfToken(),
+    },
+  })
+    .then(() => {
+      window.location.href = "login.html";
+    })
+    .catch((error) => {
+      console.error("Error:", error);
+    });
+}
+
+document.getElementById("logout").addEventListener("click", logout);
+
+fetchLedgers();
   
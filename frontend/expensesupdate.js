@@ -1,5 +1,6 @@
 const urlParams = new URLSearchParams(window.location.search);
 const expenseID = urlParams.get("expenseID");
+const ledgerID = urlParams.get("ledger");
 const categoryInput = document.getElementById("update-category");
 const amountInput = document.getElementById("update-amount");
 const dateInput = document.getElementById("update-date");
@@ -10,7 +11,7 @@ document.getElementById("update-expense-form").addEventListener("submit", functi
   const formData = new FormData(form);
   const data = {
     id: parseInt(expenseID),
-    rawdate: formData.get("rawdate"),
+    date: formData.get("date"),
     category: formData.get("category"),
     amount: parseInt(formData.get("amount")),
   };
@@ -18,12 +19,12 @@ document.getElementById("update-expense-form").addEventListener("submit", functi
     method: "PUT",
     headers: {
       "Content-Type": "application/json",
-      Authorization: getToken(),
+      "X-CSRF-Token": csrfToken(),
     },
     body: JSON.stringify(data),
   };
 
-  fetch("/expenses/" + expenseID, options)
+  fetch("/expenses/" + expenseID + (ledgerID ? "?ledger=" + ledgerID : ""), options)
     .then((response) => {
       if (response.ok) {
         alert("Expense updated successfully");
@@ -36,8 +37,3 @@ document.getElementById("update-expense-form").addEventListener("submit", functi
       console.error("Error:", error);
     });
 });
-
-// Function to retrieve token from local storage
-function getToken() {
-  return localStorage.getItem("token");
-}
\ No newline at end of file
@@ -1,12 +1,6 @@
-// Function to save token in local storage
-function saveToken(token) {
-  localStorage.setItem("token", token);
-}
-
-// Function to retrieve token from local storage
-function getToken() {
-  return localStorage.getItem("token");
-}
+// Інтерфейс працює в cookie-сесії: JWT лежить у HttpOnly cookie і недоступний скриптам (а отже й XSS).
+// Токен, збережений попередніми версіями в localStorage, більше не потрібен
+localStorage.removeItem("token");
 
 // JSON for log form
 document
@@ -24,18 +18,56 @@ document
       body: JSON.stringify(data),
     };
 
-    fetch(form.action, options)
+    fetch(form.action + "?session=cookie", options)
       .then((response) => {
-        if (response.ok) {
-          alert("Login successful");
-          const token = response.headers.get("Authorization");
-          saveToken(token);
-          window.location.href = "expenses.html"; // Перехід на expenses.html
-        } else {
+        if (!response.ok) {
           alert("Login failed");
+          return;
         }
+
+        // Без 2FA тіло порожнє, а сесію вже встановлено в cookie
+        return response.text().then((body) => {
+          const challenge = body ? JSON.parse(body) : {};
+          if (challenge.two_factor_required) {
+            // Увімкнена 2FA: пароль прийнято, потрібен код з автентифікатора
+            loginTwoFactor(challenge.challenge_token);
+          } else {
+            finishLogin();
+          }
+        });
       })
       .catch((error) => {
         console.error("Error:", error);
       });
   });
+
+function finishLogin() {
+  alert("Login successful");
+  window.location.href = "expenses.html"; // Перехід на expenses.html
+}
+
+// Другий крок входу: код з автентифікатора або код відновлення
+function loginTwoFactor(challengeToken) {
+  const code = prompt("Authentication code (or a recovery code):");
+  if (!code) {
+    return;
+  }
+
+  fetch("/login/2fa?session=cookie", {
+    method: "POST",
+    headers: {
+      "Content-Type": "application/json",
+    },
+    body: JSON.stringify({ challenge_token: challengeToken, code: code.trim() }),
+  })
+    .then((response) => {
+      if (response.ok) {
+        finishLogin();
+      } else {
+        alert("Invalid authentication code");
+      }
+    })
+    .catch((error) => {
+      console.error("Error:", error);
+    });
+}
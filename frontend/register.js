@@ -6,6 +6,8 @@ document
     const form = e.target;
     const formData = new FormData(form);
     const data = Object.fromEntries(formData.entries());
+    // Day and month boundaries are computed in the browser's time zone
+    data.time_zone = Intl.DateTimeFormat().resolvedOptions().timeZone;
     const options = {
       method: "POST",
       headers: {
@@ -0,0 +1,57 @@
+// The email link opens this page with ?token=..., otherwise the page asks for the email
+const token = new URLSearchParams(window.location.search).get("token");
+const forgotForm = document.querySelector('form[action="/password/forgot"]');
+const resetForm = document.querySelector('form[action="/password/reset"]');
+
+if (token) {
+  forgotForm.hidden = true;
+  document.getElementById("forgot-title").hidden = true;
+  resetForm.hidden = false;
+  document.getElementById("reset-title").hidden = false;
+}
+
+function postJSON(url, data) {
+  return fetch(url, {
+    method: "POST",
+    headers: {
+      "Content-Type": "application/json",
+    },
+    body: JSON.stringify(data),
+  });
+}
+
+forgotForm.addEventListener("submit", function (e) {
+  e.preventDefault();
+  const data = Object.fromEntries(new FormData(e.target).entries());
+
+  postJSON(e.target.action, data)
+    .then((response) => {
+      if (response.ok) {
+        alert("If this email is registered, a reset link has been sent");
+      } else {
+        alert("Request failed");
+      }
+    })
+    .catch((error) => {
+      console.error("Error:", error);
+    });
+});
+
+resetForm.addEventListener("submit", function (e) {
+  e.preventDefault();
+  const data = Object.fromEntries(new FormData(e.target).entries());
+  data.token = token;
+
+  postJSON(e.target.action, data)
+    .then((response) => {
+      if (response.ok) {
+        alert("Password changed");
+        window.location.href = "login.html"; // Перехід на login.html
+      } else {
+        alert("Reset link is invalid or expired");
+      }
+    })
+    .catch((error) => {
+      console.error("Error:", error);
+    });
+});
@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/julienschmidt/httprouter"
+
+	"github.com/ChomuCake/uni-golang-labs/models"
+)
+
+// інтерфейс accessTokenService описується в тому ж файлі що і використовується
+type accessTokenService interface {
+	CreateToken(ctx context.Context, userID int, request models.AccessTokenRequest) (models.CreatedAccessToken, error)
+	ListTokens(ctx context.Context, userID int) ([]models.AccessToken, error)
+	RevokeToken(ctx context.Context, userID, tokenID int) error
+}
+
+type AccessTokenHandler struct {
+	patService accessTokenService
+	tokenMng   tokenManager
+}
+
+func NewAccessTokenHandler(patService accessTokenService, tokenMng tokenManager) *AccessTokenHandler {
+	return &AccessTokenHandler{patService: patService, tokenMng: tokenMng}
+}
+
+// Маршрути без withScope: персональним токеном не можна видати чи відкликати інший токен
+func (h *AccessTokenHandler) RegisterRoutesAccessTokens(router routeRegistrar) {
+	router.POST("/me/tokens", h.CreateToken)
+	router.GET("/me/tokens", h.ListTokens)
+	router.DELETE("/me/tokens/:id", h.RevokeToken)
+}
+
+// CreateToken видає персональний токен; сам токен є лише у цій відповіді
+func (h *AccessTokenHandler) CreateToken(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	var request models.AccessTokenRequest
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		writeMalformedBody(w, r)
+		return
+	}
+
+	// Отримання айді користувача з заголовка авторизації
+	userID, err := h.tokenMng.ExtractUserIDFromRequest(r)
+	if err != nil {
+		writeUnauthorized(w, r, err)
+		return
+	}
+
+	created, err := h.patService.CreateToken(r.Context(), userID, request)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, created)
+}
+
+func (h *AccessTokenHandler) ListTokens(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	// Отримання айді користувача з заголовка авторизації
+	userID, err := h.tokenMng.ExtractUserIDFromRequest(r)
+	if err != nil {
+		writeUnauthorized(w, r, err)
+		return
+	}
+
+	tokens, err := h.patService.ListTokens(r.Context(), userID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, tokens)
+}
+
+func (h *AccessTokenHandler) RevokeToken(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	// Отримання айді користувача з заголовка авторизації
+	userID, err := h.tokenMng.ExtractUserIDFromRequest(r)
+	if err != nil {
+		writeUnauthorized(w, r, err)
+		return
+	}
+
+	tokenID, err := strconv.Atoi(params.ByName("id"))
+	if err != nil {
+		writeInvalidParam(w, r, "id")
+		return
+	}
+
+	err = h.patService.RevokeToken(r.Context(), userID, tokenID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
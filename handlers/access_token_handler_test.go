@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/julienschmidt/httprouter"
+
+	"github.com/ChomuCake/uni-golang-labs/models"
+	"github.com/ChomuCake/uni-golang-labs/services"
+	"github.com/ChomuCake/uni-golang-labs/util"
+)
+
+// accessTokenStub знає один персональний токен зі scope expenses:read
+type accessTokenStub struct{}
+
+func (accessTokenStub) AuthenticateAccessToken(ctx context.Context, token, scope string) (int, error) {
+	if token != models.AccessTokenPrefix+"secret" {
+		return 0, &services.Error{Kind: services.ErrUnauthorized, Code: "invalid_token", Message: "invalid access token"}
+	}
+	if scope != models.ScopeExpensesRead {
+		return 0, &services.Error{Kind: services.ErrForbidden, Code: "insufficient_scope", Message: "access token lacks the scope"}
+	}
+	return 7, nil
+}
+
+// expenseStub повертає порожній список і приймає будь-які зміни
+type expenseStub struct{}
+
+func (expenseStub) CreateExpense(ctx context.Context, userID, ledgerID int, expense models.Expense) error {
+	return nil
+}
+
+func (expenseStub) GetExpenses(ctx context.Context, userID, ledgerID int, sortExpensesBy string) ([]models.Expense, error) {
+	return []models.Expense{}, nil
+}
+
+func (expenseStub) UpdateExpense(ctx context.Context, userID, ledgerID int, updatedExpense models.Expense) error {
+	return nil
+}
+
+func (expenseStub) DeleteExpense(ctx context.Context, userID, ledgerID int, expenseID string) error {
+	return nil
+}
+
+func TestAccessToken_ScopeEnforcement(t *testing.T) {
+	// Arrange
+	tokenMng := util.Authenticator{
+		JWTTokenManager: util.JWTTokenManager{Versions: tokenVersionStub{}},
+		AccessTokens:    accessTokenStub{},
+	}
+	router := httprouter.New()
+	NewExpenseHandler(expenseStub{}, tokenMng).RegisterRoutes(router)
+	NewUserHandler(&passwordUserService{versions: tokenVersionStub{}}, tokenMng).RegisterRoutesUser(router)
+	NewAccessTokenHandler(nil, tokenMng).RegisterRoutesAccessTokens(router)
+
+	request := func(method, path, token, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer "+token)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr
+	}
+	token := models.AccessTokenPrefix + "secret"
+
+	// Act
+	read := request(http.MethodGet, "/expenses", token, "")
+	write := request(http.MethodPost, "/expenses", token, `{"amount":10,"category":"food","date":"2024-01-02T00:00:00Z"}`)
+	profile := request(http.MethodGet, "/me", token, "")
+	newToken := request(http.MethodPost, "/me/tokens", token, `{"name":"more","scopes":["expenses:write"]}`)
+	unknown := request(http.MethodGet, "/expenses", models.AccessTokenPrefix+"other", "")
+
+	// Assert
+	if read.Code != http.StatusOK {
+		t.Errorf("Received incorrect status for a granted scope: received %v, expected %v", read.Code, http.StatusOK)
+	}
+	for name, rr := range map[string]*httptest.ResponseRecorder{"write": write, "profile": profile, "new token": newToken} {
+		if rr.Code != http.StatusForbidden || !strings.Contains(rr.Body.String(), "insufficient_scope") {
+			t.Errorf("Received incorrect response for %s: received %v %s, expected %v", name, rr.Code, rr.Body, http.StatusForbidden)
+		}
+	}
+	if unknown.Code != http.StatusUnauthorized {
+		t.Errorf("Received incorrect status for an unknown token: received %v, expected %v", unknown.Code, http.StatusUnauthorized)
+	}
+}
@@ -0,0 +1,212 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+
+	"github.com/ChomuCake/uni-golang-labs/models"
+)
+
+// інтерфейс accountService описується в тому ж файлі що і використовується
+type accountService interface {
+	CreateAccount(ctx context.Context, userID int, account models.Account) (models.Account, error)
+	GetAccounts(ctx context.Context, userID int) ([]models.Account, error)
+	GetBalance(ctx context.Context, userID, accountID int, at time.Time) (models.Account, error)
+	GetHistory(ctx context.Context, userID, accountID int, from, to time.Time) ([]models.AccountEntry, error)
+	CreateTransfer(ctx context.Context, userID int, transfer models.Transfer) (models.Transfer, error)
+	GetTransfers(ctx context.Context, userID int) ([]models.Transfer, error)
+}
+
+type AccountHandler struct {
+	accService accountService
+	tokenMng   tokenManager
+}
+
+func NewAccountHandler(accService accountService, tokenMng tokenManager) *AccountHandler {
+	return &AccountHandler{
+		accService: accService,
+		tokenMng:   tokenMng,
+	}
+}
+
+func (h *AccountHandler) RegisterRoutesAccount(router routeRegistrar) {
+	router.POST("/accounts", withScope(models.ScopeAccountsWrite, h.CreateAccount))
+	router.GET("/accounts", withScope(models.ScopeAccountsRead, h.GetAccounts))
+	router.GET("/accounts/:id/balance", withScope(models.ScopeAccountsRead, h.GetBalance))
+	router.GET("/accounts/:id/history", withScope(models.ScopeAccountsRead, h.GetHistory))
+	router.POST("/transfers", withScope(models.ScopeAccountsWrite, h.CreateTransfer))
+	router.GET("/transfers", withScope(models.ScopeAccountsRead, h.GetTransfers))
+}
+
+func (h *AccountHandler) CreateAccount(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	var account models.Account
+	err := json.NewDecoder(r.Body).Decode(&account)
+	if err != nil {
+		writeMalformedBody(w, r)
+		return
+	}
+
+	// Отримання айді користувача з заголовка авторизації
+	userID, err := h.tokenMng.ExtractUserIDFromRequest(r)
+	if err != nil {
+		writeUnauthorized(w, r, err)
+		return
+	}
+
+	createdAccount, err := h.accService.CreateAccount(r.Context(), userID, account)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, createdAccount)
+}
+
+func (h *AccountHandler) GetAccounts(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	// Отримання айді користувача з заголовка авторизації
+	userID, err := h.tokenMng.ExtractUserIDFromRequest(r)
+	if err != nil {
+		writeUnauthorized(w, r, err)
+		return
+	}
+
+	accounts, err := h.accService.GetAccounts(r.Context(), userID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, accounts)
+}
+
+// GetBalance повертає баланс рахунку, за параметром ?at=2006-01-02 - на кінець вказаного дня
+func (h *AccountHandler) GetBalance(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	// Отримання айді користувача з заголовка авторизації
+	userID, err := h.tokenMng.ExtractUserIDFromRequest(r)
+	if err != nil {
+		writeUnauthorized(w, r, err)
+		return
+	}
+
+	accountID, err := strconv.Atoi(params.ByName("id"))
+	if err != nil {
+		writeInvalidParam(w, r, "id")
+		return
+	}
+
+	at, err := parseDayParam(r, "at", true)
+	if err != nil {
+		writeInvalidParam(w, r, "at")
+		return
+	}
+
+	account, err := h.accService.GetBalance(r.Context(), userID, accountID, at)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, account)
+}
+
+// GetHistory повертає рухи коштів по рахунку з поточним балансом; ?from= і ?to= обмежують період (включно)
+func (h *AccountHandler) GetHistory(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	// Отримання айді користувача з заголовка авторизації
+	userID, err := h.tokenMng.ExtractUserIDFromRequest(r)
+	if err != nil {
+		writeUnauthorized(w, r, err)
+		return
+	}
+
+	accountID, err := strconv.Atoi(params.ByName("id"))
+	if err != nil {
+		writeInvalidParam(w, r, "id")
+		return
+	}
+
+	from, err := parseDayParam(r, "from", false)
+	if err != nil {
+		writeInvalidParam(w, r, "from")
+		return
+	}
+
+	to, err := parseDayParam(r, "to", true)
+	if err != nil {
+		writeInvalidParam(w, r, "to")
+		return
+	}
+
+	entries, err := h.accService.GetHistory(r.Context(), userID, accountID, from, to)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, entries)
+}
+
+func (h *AccountHandler) CreateTransfer(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	var transfer models.Transfer
+	err := json.NewDecoder(r.Body).Decode(&transfer)
+	if err != nil {
+		writeMalformedBody(w, r)
+		return
+	}
+
+	// Отримання айді користувача з заголовка авторизації
+	userID, err := h.tokenMng.ExtractUserIDFromRequest(r)
+	if err != nil {
+		writeUnauthorized(w, r, err)
+		return
+	}
+
+	createdTransfer, err := h.accService.CreateTransfer(r.Context(), userID, transfer)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, createdTransfer)
+}
+
+func (h *AccountHandler) GetTransfers(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	// Отримання айді користувача з заголовка авторизації
+	userID, err := h.tokenMng.ExtractUserIDFromRequest(r)
+	if err != nil {
+		writeUnauthorized(w, r, err)
+		return
+	}
+
+	transfers, err := h.accService.GetTransfers(r.Context(), userID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, transfers)
+}
+
+// parseDayParam розбирає дату у форматі 2006-01-02 з параметра запиту; endOfDay зсуває
+// результат на початок наступного дня, щоб межа включала весь вказаний день
+func parseDayParam(r *http.Request, name string, endOfDay bool) (time.Time, error) {
+	rawDate := r.URL.Query().Get(name)
+	if rawDate == "" {
+		return time.Time{}, nil
+	}
+
+	day, err := time.Parse("2006-01-02", rawDate)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if endOfDay {
+		day = day.AddDate(0, 0, 1)
+	}
+
+	return day, nil
+}
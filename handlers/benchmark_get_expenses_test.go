@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"net/http"
@@ -71,13 +72,48 @@ func (db *TestDatabase) СlearTestDB() error {
 		CREATE TABLE users (
 			id INT AUTO_INCREMENT PRIMARY KEY,
 			username VARCHAR(255) NOT NULL,
-			password VARCHAR(255) NOT NULL
+			password VARCHAR(255) NOT NULL,
+			time_zone VARCHAR(64) NOT NULL DEFAULT 'UTC',
+			token_version INT NOT NULL DEFAULT 0,
+			deleted_at DATETIME NULL,
+			email VARCHAR(255) NULL UNIQUE,
+			totp_secret VARCHAR(64) NULL,
+			totp_enabled BOOLEAN NOT NULL DEFAULT FALSE,
+			totp_last_step BIGINT NOT NULL DEFAULT 0
 		)
 	`)
 	if err != nil {
 		return fmt.Errorf("failed to create users table: %v", err)
 	}
 
+	// Створення таблиць `ledgers` та `ledger_members`
+	_, err = db.db_test.Exec(`
+		CREATE TABLE ledgers (
+			id INT AUTO_INCREMENT PRIMARY KEY,
+			name VARCHAR(255) NOT NULL,
+			owner_id INT NOT NULL,
+			FOREIGN KEY (owner_id) REFERENCES users(id)
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create ledgers table: %v", err)
+	}
+
+	_, err = db.db_test.Exec(`
+		CREATE TABLE ledger_members (
+			ledger_id INT NOT NULL,
+			user_id INT NOT NULL,
+			role VARCHAR(16) NOT NULL,
+			joined_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+			PRIMARY KEY (ledger_id, user_id),
+			FOREIGN KEY (ledger_id) REFERENCES ledgers(id),
+			FOREIGN KEY (user_id) REFERENCES users(id)
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create ledger_members table: %v", err)
+	}
+
 	// Створення таблиці `expenses`
 	_, err = db.db_test.Exec(`
 		CREATE TABLE expenses (
@@ -86,13 +122,35 @@ func (db *TestDatabase) СlearTestDB() error {
 			category VARCHAR(255) NOT NULL,
 			amount INT NOT NULL,
 			user_id INT NOT NULL,
-			FOREIGN KEY (user_id) REFERENCES users(id)
+			ledger_id INT NULL,
+			paid_by INT NULL,
+			split_method VARCHAR(16) NULL,
+			kind VARCHAR(16) NOT NULL DEFAULT 'expense',
+			account_id INT NULL,
+			FOREIGN KEY (user_id) REFERENCES users(id),
+			FOREIGN KEY (ledger_id) REFERENCES ledgers(id),
+			FOREIGN KEY (paid_by) REFERENCES users(id)
 		)
 	`)
 	if err != nil {
 		return fmt.Errorf("failed to create expenses table: %v", err)
 	}
 
+	// Створення таблиці `expense_splits`
+	_, err = db.db_test.Exec(`
+		CREATE TABLE expense_splits (
+			expense_id INT NOT NULL,
+			user_id INT NOT NULL,
+			amount INT NOT NULL,
+			PRIMARY KEY (expense_id, user_id),
+			FOREIGN KEY (expense_id) REFERENCES expenses(id) ON DELETE CASCADE,
+			FOREIGN KEY (user_id) REFERENCES users(id)
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create expense_splits table: %v", err)
+	}
+
 	return nil
 }
 
@@ -128,12 +186,12 @@ func BenchmarkGetUserExpenses(b *testing.B) {
 		UserID:   1,
 	}
 
-	err = userDB.AddUser(benchmarkUser)
+	err = userDB.AddUser(context.Background(), benchmarkUser)
 	if err != nil {
 		b.Errorf("failed to add user with error: %v", err)
 	}
 
-	err = expenseDB.AddExpense(benmarkExpense)
+	err = expenseDB.AddExpense(context.Background(), benmarkExpense)
 	if err != nil {
 		b.Errorf("failed to add expense with error: %v", err)
 	}
@@ -143,7 +201,7 @@ func BenchmarkGetUserExpenses(b *testing.B) {
 		b.Errorf("failed to generate token with error: %v", err)
 	}
 
-	s := services.NewExpenseService(expenseDB, userDB)
+	s := services.NewExpenseService(expenseDB, userDB, drepo.NewLedgerDBMySQL(db), drepo.NewAccountDBMySQL(db))
 
 	h := NewExpenseHandler(s, jwtToken)
 
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+
+	"github.com/ChomuCake/uni-golang-labs/models"
+)
+
+// інтерфейс budgetService описується в тому ж файлі що і використовується
+type budgetService interface {
+	SetBudget(ctx context.Context, userID int, budget models.Budget) (models.Budget, error)
+	GetBudgets(ctx context.Context, userID int) ([]models.Budget, error)
+	DeleteBudget(ctx context.Context, userID int, category string) error
+}
+
+type BudgetHandler struct {
+	budService budgetService
+	tokenMng   tokenManager
+}
+
+func NewBudgetHandler(budService budgetService, tokenMng tokenManager) *BudgetHandler {
+	return &BudgetHandler{
+		budService: budService,
+		tokenMng:   tokenMng,
+	}
+}
+
+func (h *BudgetHandler) RegisterRoutesBudget(router routeRegistrar) {
+	router.PUT("/budgets", withScope(models.ScopeBudgetsWrite, h.SetBudget))
+	router.GET("/budgets", withScope(models.ScopeBudgetsRead, h.GetBudgets))
+	router.DELETE("/budgets/:category", withScope(models.ScopeBudgetsWrite, h.DeleteBudget))
+}
+
+// SetBudget створює або змінює місячний бюджет категорії
+func (h *BudgetHandler) SetBudget(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	var budget models.Budget
+	err := json.NewDecoder(r.Body).Decode(&budget)
+	if err != nil {
+		writeMalformedBody(w, r)
+		return
+	}
+
+	// Отримання айді користувача з заголовка авторизації
+	userID, err := h.tokenMng.ExtractUserIDFromRequest(r)
+	if err != nil {
+		writeUnauthorized(w, r, err)
+		return
+	}
+
+	savedBudget, err := h.budService.SetBudget(r.Context(), userID, budget)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, savedBudget)
+}
+
+func (h *BudgetHandler) GetBudgets(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	// Отримання айді користувача з заголовка авторизації
+	userID, err := h.tokenMng.ExtractUserIDFromRequest(r)
+	if err != nil {
+		writeUnauthorized(w, r, err)
+		return
+	}
+
+	budgets, err := h.budService.GetBudgets(r.Context(), userID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, budgets)
+}
+
+func (h *BudgetHandler) DeleteBudget(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	// Отримання айді користувача з заголовка авторизації
+	userID, err := h.tokenMng.ExtractUserIDFromRequest(r)
+	if err != nil {
+		writeUnauthorized(w, r, err)
+		return
+	}
+
+	err = h.budService.DeleteBudget(r.Context(), userID, params.ByName("category"))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,23 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// WithDBDeadline обмежує час обробки запиту: контекст запиту передається до запитів у базу,
+// тож після timeout повільні запити скасовуються і клієнт отримує 503 замість обірваного з'єднання.
+// Запити скасовуються й тоді, коли клієнт від'єднався раніше. Нульовий timeout вимикає обмеження
+func WithDBDeadline(timeout time.Duration, next http.Handler) http.Handler {
+	if timeout <= 0 {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
@@ -1,8 +1,10 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
+	"strconv"
 
 	"github.com/julienschmidt/httprouter"
 
@@ -12,10 +14,10 @@ import (
 
 // інтерфейс expenseService, tokenManager описується в тому ж файлі що і використовується
 type expenseService interface {
-	CreateExpense(userID int, expense models.Expense) error
-	GetExpenses(userID int, sortExpensesBy string) ([]models.Expense, error)
-	UpdateExpense(userID int, updatedExpense models.Expense) error
-	DeleteExpense(userID int, expenseID string) error
+	CreateExpense(ctx context.Context, userID, ledgerID int, expense models.Expense) error
+	GetExpenses(ctx context.Context, userID, ledgerID int, sortExpensesBy string) ([]models.Expense, error)
+	UpdateExpense(ctx context.Context, userID, ledgerID int, updatedExpense models.Expense) error
+	DeleteExpense(ctx context.Context, userID, ledgerID int, expenseID string) error
 }
 
 type tokenManager interface {
@@ -34,32 +36,42 @@ func NewExpenseHandler(expService expenseService, tokenMng tokenManager) *Expens
 	}
 }
 
-func (h *ExpenseHandler) RegisterRoutes(router *httprouter.Router) {
-	router.POST("/expenses", h.CreateExpense)
-	router.GET("/expenses", h.GetExpenses)
-	router.DELETE("/expenses/:id", h.DeleteExpense)
-	router.PUT("/expenses/:id", h.UpdateExpense)
+func (h *ExpenseHandler) RegisterRoutes(router routeRegistrar) {
+	router.POST("/expenses", withScope(models.ScopeExpensesWrite, h.CreateExpense))
+	router.GET("/expenses", withScope(models.ScopeExpensesRead, h.GetExpenses))
+	router.DELETE("/expenses/:id", withScope(models.ScopeExpensesWrite, h.DeleteExpense))
+	router.PUT("/expenses/:id", withScope(models.ScopeExpensesWrite, h.UpdateExpense))
 }
 
 func (h *ExpenseHandler) CreateExpense(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	r, span := startSpan(r, "ExpenseHandler.CreateExpense")
+	defer span.End()
+
 	var expense models.Expense
 	err := json.NewDecoder(r.Body).Decode(&expense)
 	if err != nil {
-		w.WriteHeader(http.StatusBadRequest)
+		writeDecodeError(w, r, err)
 		return
 	}
 
 	// Отримання айді користувача з заголовка авторизації
 	userID, err := h.tokenMng.ExtractUserIDFromRequest(r)
 	if err != nil {
-		w.WriteHeader(http.StatusUnauthorized)
+		writeUnauthorized(w, r, err)
+		return
+	}
+
+	// Вибір спільного журналу (за замовчуванням - особисті витрати)
+	ledgerID, err := ledgerIDFromRequest(r)
+	if err != nil {
+		writeInvalidParam(w, r, "ledger")
 		return
 	}
 
 	// Створення витрат
-	err = h.expService.CreateExpense(userID, expense)
+	err = h.expService.CreateExpense(r.Context(), userID, ledgerID, expense)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
@@ -67,51 +79,66 @@ func (h *ExpenseHandler) CreateExpense(w http.ResponseWriter, r *http.Request, _
 }
 
 func (h *ExpenseHandler) GetExpenses(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	r, span := startSpan(r, "ExpenseHandler.GetExpenses")
+	defer span.End()
+
 	// Отримання айді користувача з заголовка авторизації
 	userID, err := h.tokenMng.ExtractUserIDFromRequest(r)
 	if err != nil {
-		w.WriteHeader(http.StatusUnauthorized)
+		writeUnauthorized(w, r, err)
 		return
 	}
 
-	sortExpensesBy := r.URL.Query().Get("sort")
-
-	// Отримання витрат
-	userExpenses, err := h.expService.GetExpenses(userID, sortExpensesBy)
+	// Вибір спільного журналу (за замовчуванням - особисті витрати)
+	ledgerID, err := ledgerIDFromRequest(r)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
+		writeInvalidParam(w, r, "ledger")
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	err = json.NewEncoder(w).Encode(userExpenses)
+	sortExpensesBy := r.URL.Query().Get("sort")
+
+	// Отримання витрат
+	userExpenses, err := h.expService.GetExpenses(r.Context(), userID, ledgerID, sortExpensesBy)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
+	writeJSON(w, http.StatusOK, userExpenses)
+
 	//w.WriteHeader(http.StatusOK)
 }
 
 func (h *ExpenseHandler) UpdateExpense(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	r, span := startSpan(r, "ExpenseHandler.UpdateExpense")
+	defer span.End()
+
 	var updatedExpense models.Expense
 	err := json.NewDecoder(r.Body).Decode(&updatedExpense)
 	if err != nil {
-		w.WriteHeader(http.StatusBadRequest)
+		writeDecodeError(w, r, err)
 		return
 	}
 
 	// Отримання айді користувача з заголовка авторизації
 	userID, err := h.tokenMng.ExtractUserIDFromRequest(r)
 	if err != nil {
-		w.WriteHeader(http.StatusUnauthorized)
+		writeUnauthorized(w, r, err)
+		return
+	}
+
+	// Вибір спільного журналу (за замовчуванням - особисті витрати)
+	ledgerID, err := ledgerIDFromRequest(r)
+	if err != nil {
+		writeInvalidParam(w, r, "ledger")
 		return
 	}
 
 	// Оновлення витрати
-	err = h.expService.UpdateExpense(userID, updatedExpense)
+	err = h.expService.UpdateExpense(r.Context(), userID, ledgerID, updatedExpense)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
@@ -119,19 +146,39 @@ func (h *ExpenseHandler) UpdateExpense(w http.ResponseWriter, r *http.Request, p
 }
 
 func (h *ExpenseHandler) DeleteExpense(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	r, span := startSpan(r, "ExpenseHandler.DeleteExpense")
+	defer span.End()
+
 	// Отримання айді користувача з заголовка авторизації
 	userID, err := h.tokenMng.ExtractUserIDFromRequest(r)
 	if err != nil {
-		w.WriteHeader(http.StatusUnauthorized)
+		writeUnauthorized(w, r, err)
+		return
+	}
+
+	// Вибір спільного журналу (за замовчуванням - особисті витрати)
+	ledgerID, err := ledgerIDFromRequest(r)
+	if err != nil {
+		writeInvalidParam(w, r, "ledger")
 		return
 	}
 
 	// Видалення витрати
-	err = h.expService.DeleteExpense(userID, params.ByName("id"))
+	err = h.expService.DeleteExpense(r.Context(), userID, ledgerID, params.ByName("id"))
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
 	w.WriteHeader(http.StatusOK)
 }
+
+// ledgerIDFromRequest повертає ідентифікатор журналу з параметра запиту ?ledger=, або 0 якщо його не вказано
+func ledgerIDFromRequest(r *http.Request) (int, error) {
+	rawLedgerID := r.URL.Query().Get("ledger")
+	if rawLedgerID == "" {
+		return 0, nil
+	}
+
+	return strconv.Atoi(rawLedgerID)
+}
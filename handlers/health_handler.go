@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// інтерфейс healthChecker описується в тому ж файлі що і використовується
+type healthChecker interface {
+	Ping(ctx context.Context) error
+	SchemaVersion(ctx context.Context) (int, bool, error)
+}
+
+// healthTimeout обмежує перевірку готовності, щоб балансувальник не чекав на завислу базу
+const healthTimeout = 2 * time.Second
+
+type HealthHandler struct {
+	checker       healthChecker
+	schemaVersion int // версія схеми, з якою працює цей бінарник
+}
+
+func NewHealthHandler(checker healthChecker, schemaVersion int) *HealthHandler {
+	return &HealthHandler{
+		checker:       checker,
+		schemaVersion: schemaVersion,
+	}
+}
+
+func (h *HealthHandler) RegisterRoutesHealth(router routeRegistrar) {
+	router.GET("/healthz", h.Healthz)
+	router.GET("/readyz", h.Readyz)
+}
+
+type healthStatus struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+// Healthz повідомляє, що процес живий і обробляє запити; залежності не перевіряються,
+// щоб недоступність бази не призводила до перезапуску сервера
+func (h *HealthHandler) Healthz(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	writeJSON(w, http.StatusOK, healthStatus{Status: "ok"})
+}
+
+// Readyz перевіряє, що база доступна і її схема не старша за ту, яку очікує сервер
+func (h *HealthHandler) Readyz(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	ctx, cancel := context.WithTimeout(r.Context(), healthTimeout)
+	defer cancel()
+
+	status := healthStatus{Status: "ok", Checks: map[string]string{}}
+	fail := func(check, message string) {
+		status.Status = "unavailable"
+		status.Checks[check] = message
+	}
+
+	err := h.checker.Ping(ctx)
+	if err != nil {
+		fail("database", "unreachable")
+		fail("migrations", "unknown")
+		writeJSON(w, http.StatusServiceUnavailable, status)
+		return
+	}
+	status.Checks["database"] = "ok"
+
+	version, dirty, err := h.checker.SchemaVersion(ctx)
+	switch {
+	case err != nil:
+		fail("migrations", "unknown")
+	case dirty:
+		fail("migrations", fmt.Sprintf("version %d is dirty", version))
+	case version < h.schemaVersion:
+		fail("migrations", fmt.Sprintf("version %d, expected %d", version, h.schemaVersion))
+	default:
+		status.Checks["migrations"] = fmt.Sprintf("version %d", version)
+	}
+
+	if status.Status != "ok" {
+		writeJSON(w, http.StatusServiceUnavailable, status)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, status)
+}
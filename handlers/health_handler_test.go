@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type stubHealthChecker struct {
+	pingErr error
+	version int
+	dirty   bool
+}
+
+func (c stubHealthChecker) Ping(ctx context.Context) error { return c.pingErr }
+
+func (c stubHealthChecker) SchemaVersion(ctx context.Context) (int, bool, error) {
+	return c.version, c.dirty, nil
+}
+
+func TestHealthHandler_Readyz(t *testing.T) {
+	tests := []struct {
+		name           string
+		checker        stubHealthChecker
+		expectedStatus int
+		expectedCheck  string
+	}{
+		{"ready", stubHealthChecker{version: 7}, http.StatusOK, "version 7"},
+		{"database down", stubHealthChecker{pingErr: errors.New("connection refused")}, http.StatusServiceUnavailable, "unknown"},
+		{"outdated schema", stubHealthChecker{version: 6}, http.StatusServiceUnavailable, "version 6, expected 7"},
+		{"dirty schema", stubHealthChecker{version: 7, dirty: true}, http.StatusServiceUnavailable, "version 7 is dirty"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			h := NewHealthHandler(tt.checker, 7)
+			w := httptest.NewRecorder()
+
+			// Act
+			h.Readyz(w, httptest.NewRequest(http.MethodGet, "/readyz", nil), nil)
+
+			// Assert
+			var status healthStatus
+			_ = json.Unmarshal(w.Body.Bytes(), &status)
+			if w.Code != tt.expectedStatus || status.Checks["migrations"] != tt.expectedCheck {
+				t.Errorf("Received %d %+v, expected %d with migrations %q", w.Code, status, tt.expectedStatus, tt.expectedCheck)
+			}
+		})
+	}
+}
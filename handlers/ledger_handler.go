@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/julienschmidt/httprouter"
+
+	"github.com/ChomuCake/uni-golang-labs/models"
+)
+
+// інтерфейс ledgerService описується в тому ж файлі що і використовується
+type ledgerService interface {
+	CreateLedger(ctx context.Context, userID int, ledger models.Ledger) (models.Ledger, error)
+	GetLedgers(ctx context.Context, userID int) ([]models.Ledger, error)
+	GetMembers(ctx context.Context, userID, ledgerID int) ([]models.LedgerMember, error)
+	InviteMember(ctx context.Context, userID, ledgerID int, username, role string) (models.LedgerMember, error)
+	RemoveMember(ctx context.Context, userID, ledgerID, memberID int) error
+}
+
+type LedgerHandler struct {
+	ledService ledgerService
+	tokenMng   tokenManager
+}
+
+func NewLedgerHandler(ledService ledgerService, tokenMng tokenManager) *LedgerHandler {
+	return &LedgerHandler{
+		ledService: ledService,
+		tokenMng:   tokenMng,
+	}
+}
+
+func (h *LedgerHandler) RegisterRoutesLedger(router routeRegistrar) {
+	router.POST("/ledgers", withScope(models.ScopeLedgersWrite, h.CreateLedger))
+	router.GET("/ledgers", withScope(models.ScopeLedgersRead, h.GetLedgers))
+	router.GET("/ledgers/:id/members", withScope(models.ScopeLedgersRead, h.GetMembers))
+	router.POST("/ledgers/:id/members", withScope(models.ScopeLedgersWrite, h.InviteMember))
+	router.DELETE("/ledgers/:id/members/:user_id", withScope(models.ScopeLedgersWrite, h.RemoveMember))
+}
+
+type inviteRequest struct {
+	Username string `json:"username"`
+	Role     string `json:"role"`
+}
+
+func (h *LedgerHandler) CreateLedger(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	var ledger models.Ledger
+	err := json.NewDecoder(r.Body).Decode(&ledger)
+	if err != nil {
+		writeMalformedBody(w, r)
+		return
+	}
+
+	// Отримання айді користувача з заголовка авторизації
+	userID, err := h.tokenMng.ExtractUserIDFromRequest(r)
+	if err != nil {
+		writeUnauthorized(w, r, err)
+		return
+	}
+
+	createdLedger, err := h.ledService.CreateLedger(r.Context(), userID, ledger)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, createdLedger)
+}
+
+func (h *LedgerHandler) GetLedgers(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	// Отримання айді користувача з заголовка авторизації
+	userID, err := h.tokenMng.ExtractUserIDFromRequest(r)
+	if err != nil {
+		writeUnauthorized(w, r, err)
+		return
+	}
+
+	ledgers, err := h.ledService.GetLedgers(r.Context(), userID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, ledgers)
+}
+
+func (h *LedgerHandler) GetMembers(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	// Отримання айді користувача з заголовка авторизації
+	userID, err := h.tokenMng.ExtractUserIDFromRequest(r)
+	if err != nil {
+		writeUnauthorized(w, r, err)
+		return
+	}
+
+	ledgerID, err := strconv.Atoi(params.ByName("id"))
+	if err != nil {
+		writeInvalidParam(w, r, "id")
+		return
+	}
+
+	members, err := h.ledService.GetMembers(r.Context(), userID, ledgerID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, members)
+}
+
+func (h *LedgerHandler) InviteMember(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	var invite inviteRequest
+	err := json.NewDecoder(r.Body).Decode(&invite)
+	if err != nil {
+		writeMalformedBody(w, r)
+		return
+	}
+
+	// Отримання айді користувача з заголовка авторизації
+	userID, err := h.tokenMng.ExtractUserIDFromRequest(r)
+	if err != nil {
+		writeUnauthorized(w, r, err)
+		return
+	}
+
+	ledgerID, err := strconv.Atoi(params.ByName("id"))
+	if err != nil {
+		writeInvalidParam(w, r, "id")
+		return
+	}
+
+	member, err := h.ledService.InviteMember(r.Context(), userID, ledgerID, invite.Username, invite.Role)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, member)
+}
+
+func (h *LedgerHandler) RemoveMember(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	// Отримання айді користувача з заголовка авторизації
+	userID, err := h.tokenMng.ExtractUserIDFromRequest(r)
+	if err != nil {
+		writeUnauthorized(w, r, err)
+		return
+	}
+
+	ledgerID, err := strconv.Atoi(params.ByName("id"))
+	if err != nil {
+		writeInvalidParam(w, r, "id")
+		return
+	}
+
+	memberID, err := strconv.Atoi(params.ByName("user_id"))
+	if err != nil {
+		writeInvalidParam(w, r, "user_id")
+		return
+	}
+
+	err = h.ledService.RemoveMember(r.Context(), userID, ledgerID, memberID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,171 @@
+package handlers
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"net/http"
+	"strings"
+
+	"github.com/julienschmidt/httprouter"
+
+	"github.com/ChomuCake/uni-golang-labs/logging"
+	"github.com/ChomuCake/uni-golang-labs/models"
+	"github.com/ChomuCake/uni-golang-labs/util"
+)
+
+// інтерфейс oidcProvider описується в тому ж файлі що і використовується
+type oidcProvider interface {
+	AuthCodeURL(ctx context.Context, state, nonce, verifier string) (string, error)
+	Exchange(ctx context.Context, code, nonce, verifier string) (models.OIDCIdentity, error)
+}
+
+type oidcService interface {
+	LoginOIDC(ctx context.Context, identity models.OIDCIdentity) (models.User, error)
+}
+
+type tokenGenerator interface {
+	GenerateToken(user models.User) (string, error)
+}
+
+// oidcCookie зберігає state, nonce і PKCE verifier між переходом до постачальника і поверненням на callback
+const (
+	oidcCookie       = "fintrack_oidc"
+	oidcCookieMaxAge = 10 * 60
+)
+
+// oidcSuccessPage отримує JWT у фрагменті адреси, який браузер не надсилає на сервер і не пише в журнали;
+// у cookie-сесії токен уже в cookie, і браузер одразу потрапляє на oidcSessionPage
+const (
+	oidcSuccessPage = "/login.html"
+	oidcSessionPage = "/expenses.html"
+)
+
+type OIDCHandler struct {
+	provider        oidcProvider
+	oService        oidcService
+	tokenMng        tokenGenerator
+	insecureCookies bool // cookie без Secure - лише для локальної розробки по HTTP
+}
+
+func NewOIDCHandler(provider oidcProvider, oService oidcService, tokenMng tokenGenerator) *OIDCHandler {
+	return &OIDCHandler{provider: provider, oService: oService, tokenMng: tokenMng}
+}
+
+// SetCookieSecure задає прапорець Secure для cookie входу і сесії (типово ввімкнений)
+func (h *OIDCHandler) SetCookieSecure(secure bool) {
+	h.insecureCookies = !secure
+}
+
+func (h *OIDCHandler) RegisterRoutesOIDC(router routeRegistrar) {
+	router.GET("/oidc/login", h.Login)
+	router.GET("/oidc/callback", h.Callback)
+}
+
+// Login перенаправляє браузер на сторінку входу постачальника; з ?session=cookie вхід завершиться
+// cookie-сесією, а не токеном у фрагменті адреси
+func (h *OIDCHandler) Login(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	state, nonce, verifier := randomToken(), randomToken(), randomToken()
+	mode := "token"
+	if sessionRequested(r) {
+		mode = "cookie"
+	}
+
+	authURL, err := h.provider.AuthCodeURL(r.Context(), state, nonce, verifier)
+	if err != nil {
+		logging.FromContext(r.Context()).Error("oidc provider unavailable", "error", err.Error())
+		writeProblem(w, r, problemDetails{
+			Status: http.StatusBadGateway,
+			Code:   "oidc_unavailable",
+			Detail: "identity provider is unavailable",
+		})
+		return
+	}
+
+	setOIDCCookie(w, !h.insecureCookies, strings.Join([]string{state, nonce, verifier, mode}, "."), oidcCookieMaxAge)
+	http.Redirect(w, r, authURL, http.StatusFound)
+}
+
+// Callback завершує вхід: перевіряє state, обмінює код на ID-токен і видає JWT. Браузер
+// перенаправляється на сторінку входу з токеном у фрагменті, API-клієнт може взяти його з заголовка
+func (h *OIDCHandler) Callback(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	// Збережені параметри одноразові: повторний callback з тим самим кодом не пройде
+	cookie, cookieErr := r.Cookie(oidcCookie)
+	setOIDCCookie(w, !h.insecureCookies, "", -1)
+
+	query := r.URL.Query()
+	if query.Get("error") != "" {
+		writeProblem(w, r, problemDetails{
+			Status: http.StatusUnauthorized,
+			Code:   "oidc_denied",
+			Detail: "identity provider denied the login: " + query.Get("error"),
+		})
+		return
+	}
+
+	var saved []string
+	if cookieErr == nil {
+		saved = strings.Split(cookie.Value, ".")
+	}
+	if len(saved) != 4 || subtle.ConstantTimeCompare([]byte(saved[0]), []byte(query.Get("state"))) != 1 {
+		writeProblem(w, r, problemDetails{
+			Status: http.StatusBadRequest,
+			Code:   "oidc_invalid_state",
+			Detail: "login session is missing or expired, start the login again",
+		})
+		return
+	}
+
+	identity, err := h.provider.Exchange(r.Context(), query.Get("code"), saved[1], saved[2])
+	if err != nil {
+		logging.FromContext(r.Context()).Warn("oidc login failed", "error", err.Error())
+		writeProblem(w, r, problemDetails{
+			Status: http.StatusUnauthorized,
+			Code:   "oidc_login_failed",
+			Detail: "identity provider login failed",
+		})
+		return
+	}
+
+	user, err := h.oService.LoginOIDC(r.Context(), identity)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	tokenString, err := h.tokenMng.GenerateToken(user)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if saved[3] == "cookie" {
+		setSessionCookies(w, !h.insecureCookies, tokenString, int(util.TokenTTL.Seconds()))
+		http.Redirect(w, r, oidcSessionPage, http.StatusSeeOther)
+		return
+	}
+
+	w.Header().Set("Authorization", tokenString)
+	http.Redirect(w, r, oidcSuccessPage+"#token="+tokenString, http.StatusSeeOther)
+}
+
+func setOIDCCookie(w http.ResponseWriter, secure bool, value string, maxAge int) {
+	// SameSite=Lax: cookie має надійти з переходом від постачальника назад на callback
+	http.SetCookie(w, &http.Cookie{
+		Name:     oidcCookie,
+		Value:    value,
+		Path:     "/oidc/",
+		MaxAge:   maxAge,
+		HttpOnly: true,
+		Secure:   secure,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// randomToken повертає 256 випадкових бітів у base64url (придатне і для PKCE verifier)
+func randomToken() string {
+	raw := make([]byte, 32)
+	_, _ = rand.Read(raw)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/julienschmidt/httprouter"
+
+	"github.com/ChomuCake/uni-golang-labs/models"
+	"github.com/ChomuCake/uni-golang-labs/util"
+)
+
+// stubOIDCProvider видає код "code-1" і приймає його лише з nonce та verifier зі своєї адреси входу
+type stubOIDCProvider struct {
+	nonce, verifier string
+}
+
+func (p *stubOIDCProvider) AuthCodeURL(ctx context.Context, state, nonce, verifier string) (string, error) {
+	p.nonce, p.verifier = nonce, verifier
+	return "https://idp.example.com/authorize?state=" + url.QueryEscape(state), nil
+}
+
+func (p *stubOIDCProvider) Exchange(ctx context.Context, code, nonce, verifier string) (models.OIDCIdentity, error) {
+	if code != "code-1" || nonce != p.nonce || verifier != p.verifier {
+		return models.OIDCIdentity{}, errors.New("invalid_grant")
+	}
+	return models.OIDCIdentity{Issuer: "https://idp.example.com", Subject: "ext-42"}, nil
+}
+
+type stubOIDCService struct{}
+
+func (stubOIDCService) LoginOIDC(ctx context.Context, identity models.OIDCIdentity) (models.User, error) {
+	return models.User{ID: 7, Username: "alice"}, nil
+}
+
+func TestOIDCHandler_LoginAndCallback(t *testing.T) {
+	// Arrange
+	tokenMng := util.JWTTokenManager{}
+	router := httprouter.New()
+	NewOIDCHandler(&stubOIDCProvider{}, stubOIDCService{}, tokenMng).RegisterRoutesOIDC(router)
+
+	callback := func(state string, cookies []*http.Cookie) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/oidc/callback?code=code-1&state="+url.QueryEscape(state), nil)
+		for _, cookie := range cookies {
+			req.AddCookie(cookie)
+		}
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr
+	}
+
+	// Act
+	login := httptest.NewRecorder()
+	router.ServeHTTP(login, httptest.NewRequest(http.MethodGet, "/oidc/login", nil))
+	redirect, _ := url.Parse(login.Header().Get("Location"))
+	state := redirect.Query().Get("state")
+	cookies := login.Result().Cookies()
+
+	wrongState := callback("forged", cookies)
+	noCookie := callback(state, nil)
+	completed := callback(state, cookies)
+
+	// Assert
+	if login.Code != http.StatusFound || state == "" || len(cookies) != 1 || !cookies[0].HttpOnly {
+		t.Fatalf("Received incorrect login redirect: received %v %q, cookies %v", login.Code, redirect, cookies)
+	}
+	if wrongState.Code != http.StatusBadRequest || noCookie.Code != http.StatusBadRequest {
+		t.Errorf("Received incorrect status for invalid state: received %v and %v, expected %v", wrongState.Code, noCookie.Code, http.StatusBadRequest)
+	}
+
+	location := completed.Header().Get("Location")
+	token, _ := strings.CutPrefix(location, "/login.html#token=")
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	userID, err := tokenMng.ExtractUserIDFromRequest(req)
+	if completed.Code != http.StatusSeeOther || err != nil || userID != 7 {
+		t.Errorf("Received incorrect callback: received %v %q, user %v, error %v", completed.Code, location, userID, err)
+	}
+}
+
+func TestOIDCHandler_CookieSession(t *testing.T) {
+	// Arrange
+	router := httprouter.New()
+	NewOIDCHandler(&stubOIDCProvider{}, stubOIDCService{}, util.JWTTokenManager{}).RegisterRoutesOIDC(router)
+
+	login := httptest.NewRecorder()
+	router.ServeHTTP(login, httptest.NewRequest(http.MethodGet, "/oidc/login?session=cookie", nil))
+	redirect, _ := url.Parse(login.Header().Get("Location"))
+
+	req := httptest.NewRequest(http.MethodGet, "/oidc/callback?code=code-1&state="+url.QueryEscape(redirect.Query().Get("state")), nil)
+	for _, cookie := range login.Result().Cookies() {
+		req.AddCookie(cookie)
+	}
+	completed := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(completed, req)
+
+	// Assert: токен не потрапляє в адресу, сесія - в HttpOnly cookie
+	location := completed.Header().Get("Location")
+	session := sessionCookies(completed)[util.SessionCookie]
+	if completed.Code != http.StatusSeeOther || location != "/expenses.html" || strings.Contains(location, "token=") {
+		t.Errorf("Received incorrect callback: received %v %q, expected %v to /expenses.html", completed.Code, location, http.StatusSeeOther)
+	}
+	if session == nil || session.Value == "" || !session.HttpOnly || sessionCookies(completed)[util.CSRFCookie] == nil {
+		t.Errorf("Received incorrect session cookies: %v", completed.Result().Cookies())
+	}
+}
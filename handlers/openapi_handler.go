@@ -0,0 +1,26 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// OpenAPIHandler віддає опис API, щоб клієнти могли згенерувати або перевірити свої типи
+type OpenAPIHandler struct {
+	spec []byte
+}
+
+func NewOpenAPIHandler(spec []byte) *OpenAPIHandler {
+	return &OpenAPIHandler{spec: spec}
+}
+
+func (h *OpenAPIHandler) RegisterRoutesOpenAPI(router routeRegistrar) {
+	router.GET("/openapi.json", h.GetSpec)
+}
+
+func (h *OpenAPIHandler) GetSpec(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(h.spec)
+}
@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/julienschmidt/httprouter"
+
+	"github.com/ChomuCake/uni-golang-labs/api"
+)
+
+// recordingRouter запам'ятовує зареєстровані маршрути у вигляді "METHOD /path/{param}"
+type recordingRouter struct {
+	routes map[string]bool
+}
+
+func (r *recordingRouter) add(method, path string) {
+	// Параметри httprouter (:id) записуються так, як у OpenAPI ({id})
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") {
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+	r.routes[method+" "+strings.Join(segments, "/")] = true
+}
+
+func (r *recordingRouter) GET(path string, _ httprouter.Handle)    { r.add("GET", path) }
+func (r *recordingRouter) POST(path string, _ httprouter.Handle)   { r.add("POST", path) }
+func (r *recordingRouter) PUT(path string, _ httprouter.Handle)    { r.add("PUT", path) }
+func (r *recordingRouter) PATCH(path string, _ httprouter.Handle)  { r.add("PATCH", path) }
+func (r *recordingRouter) DELETE(path string, _ httprouter.Handle) { r.add("DELETE", path) }
+
+func TestOpenAPI_MatchesRegisteredRoutes(t *testing.T) {
+	// Arrange: обробники лише реєструють маршрути, тож сервіси не потрібні
+	router := &recordingRouter{routes: map[string]bool{}}
+	NewExpenseHandler(nil, nil).RegisterRoutes(router)
+	NewUserHandler(nil, nil).RegisterRoutesUser(router)
+	NewPasswordResetHandler(nil).RegisterRoutesPasswordReset(router)
+	NewTwoFactorHandler(nil, nil).RegisterRoutesTwoFactor(router)
+	NewAccessTokenHandler(nil, nil).RegisterRoutesAccessTokens(router)
+	NewOIDCHandler(nil, nil, nil).RegisterRoutesOIDC(router)
+	NewLedgerHandler(nil, nil).RegisterRoutesLedger(router)
+	NewSettlementHandler(nil, nil).RegisterRoutesSettlement(router)
+	NewAccountHandler(nil, nil).RegisterRoutesAccount(router)
+	NewBudgetHandler(nil, nil).RegisterRoutesBudget(router)
+	NewReportHandler(nil, nil).RegisterRoutesReport(router)
+	NewOpenAPIHandler(api.OpenAPI).RegisterRoutesOpenAPI(router)
+	NewHealthHandler(nil, 0).RegisterRoutesHealth(router)
+
+	// Act
+	var spec struct {
+		Paths map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	err := json.Unmarshal(api.OpenAPI, &spec)
+	if err != nil {
+		t.Fatalf("Received an error: received %v, expected valid OpenAPI JSON", err)
+	}
+
+	documented := map[string]bool{}
+	for path, operations := range spec.Paths {
+		for method := range operations {
+			documented[strings.ToUpper(method)+" "+path] = true
+		}
+	}
+
+	// Assert
+	var missing, stale []string
+	for route := range router.routes {
+		if !documented[route] {
+			missing = append(missing, route)
+		}
+	}
+	for route := range documented {
+		if !router.routes[route] {
+			stale = append(stale, route)
+		}
+	}
+	sort.Strings(missing)
+	sort.Strings(stale)
+
+	if len(missing) > 0 {
+		t.Errorf("Routes missing from api/openapi.json: %v", missing)
+	}
+	if len(stale) > 0 {
+		t.Errorf("Documented routes that aren't registered: %v", stale)
+	}
+}
+
+func TestOpenAPI_SchemaReferencesResolve(t *testing.T) {
+	var spec struct {
+		Components struct {
+			Schemas   map[string]json.RawMessage `json:"schemas"`
+			Responses map[string]json.RawMessage `json:"responses"`
+		} `json:"components"`
+	}
+	err := json.Unmarshal(api.OpenAPI, &spec)
+	if err != nil {
+		t.Fatalf("Received an error: received %v, expected valid OpenAPI JSON", err)
+	}
+
+	for _, part := range strings.Split(string(api.OpenAPI), `"$ref": "`)[1:] {
+		ref := part[:strings.Index(part, `"`)]
+		switch {
+		case strings.HasPrefix(ref, "#/components/schemas/"):
+			if spec.Components.Schemas[strings.TrimPrefix(ref, "#/components/schemas/")] == nil {
+				t.Errorf("Unresolved reference %v", ref)
+			}
+		case strings.HasPrefix(ref, "#/components/responses/"):
+			if spec.Components.Responses[strings.TrimPrefix(ref, "#/components/responses/")] == nil {
+				t.Errorf("Unresolved reference %v", ref)
+			}
+		default:
+			t.Errorf("Unexpected reference %v", ref)
+		}
+	}
+}
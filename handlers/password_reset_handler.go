@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+
+	"github.com/ChomuCake/uni-golang-labs/models"
+)
+
+// інтерфейс passwordResetService описується в тому ж файлі що і використовується
+type passwordResetService interface {
+	ForgotPassword(ctx context.Context, email string) error
+	ResetPassword(ctx context.Context, reset models.PasswordReset) error
+}
+
+type PasswordResetHandler struct {
+	resetService passwordResetService
+}
+
+func NewPasswordResetHandler(resetService passwordResetService) *PasswordResetHandler {
+	return &PasswordResetHandler{resetService: resetService}
+}
+
+func (h *PasswordResetHandler) RegisterRoutesPasswordReset(router routeRegistrar) {
+	router.POST("/password/forgot", h.ForgotPassword)
+	router.POST("/password/reset", h.ResetPassword)
+}
+
+// ForgotPassword завжди відповідає 202, навіть для незареєстрованої адреси
+func (h *PasswordResetHandler) ForgotPassword(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	var request struct {
+		Email string `json:"email"`
+	}
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		writeMalformedBody(w, r)
+		return
+	}
+
+	err = h.resetService.ForgotPassword(r.Context(), request.Email)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (h *PasswordResetHandler) ResetPassword(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	var reset models.PasswordReset
+	err := json.NewDecoder(r.Body).Decode(&reset)
+	if err != nil {
+		writeMalformedBody(w, r)
+		return
+	}
+
+	err = h.resetService.ResetPassword(r.Context(), reset)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
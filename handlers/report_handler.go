@@ -0,0 +1,273 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+
+	"github.com/ChomuCake/uni-golang-labs/chart"
+	"github.com/ChomuCake/uni-golang-labs/models"
+)
+
+// інтерфейс reportService описується в тому ж файлі що і використовується
+type reportService interface {
+	GetTimeSeries(ctx context.Context, userID int, interval string, from, to time.Time, category string, window int) (models.TimeSeries, error)
+	GetCategoryTotals(ctx context.Context, userID int, from, to time.Time) (models.CategoryReport, error)
+	GetBudgetProgress(ctx context.Context, userID int, month time.Time) ([]models.BudgetProgress, error)
+	GetStatement(ctx context.Context, userID int, month time.Time) (models.Statement, error)
+}
+
+type ReportHandler struct {
+	repService reportService
+	tokenMng   tokenManager
+}
+
+func NewReportHandler(repService reportService, tokenMng tokenManager) *ReportHandler {
+	return &ReportHandler{
+		repService: repService,
+		tokenMng:   tokenMng,
+	}
+}
+
+func (h *ReportHandler) RegisterRoutesReport(router routeRegistrar) {
+	router.GET("/reports/timeseries", withScope(models.ScopeReportsRead, h.GetTimeSeries))
+	router.GET("/reports/categories", withScope(models.ScopeReportsRead, h.GetCategoryTotals))
+	router.GET("/reports/budgets", withScope(models.ScopeReportsRead, h.GetBudgetProgress))
+	router.GET("/reports/chart.svg", withScope(models.ScopeReportsRead, h.GetChart))
+	router.GET("/reports/statement.pdf", withScope(models.ScopeReportsRead, h.GetStatement))
+}
+
+// GetTimeSeries повертає динаміку витрат: ?interval=day|week|month, ?from= і ?to= (включно),
+// ?category= для однієї категорії та ?window= - кількість інтервалів у ковзному середньому
+func (h *ReportHandler) GetTimeSeries(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	// Отримання айді користувача з заголовка авторизації
+	userID, err := h.tokenMng.ExtractUserIDFromRequest(r)
+	if err != nil {
+		writeUnauthorized(w, r, err)
+		return
+	}
+
+	from, err := parseDayParam(r, "from", false)
+	if err != nil {
+		writeInvalidParam(w, r, "from")
+		return
+	}
+
+	to, err := parseDayParam(r, "to", true)
+	if err != nil {
+		writeInvalidParam(w, r, "to")
+		return
+	}
+
+	query := r.URL.Query()
+	window := 0
+	if rawWindow := query.Get("window"); rawWindow != "" {
+		window, err = strconv.Atoi(rawWindow)
+		if err != nil {
+			writeInvalidParam(w, r, "window")
+			return
+		}
+	}
+
+	series, err := h.repService.GetTimeSeries(r.Context(), userID, query.Get("interval"), from, to, query.Get("category"), window)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, series)
+}
+
+// GetCategoryTotals повертає витрати за категоріями за ?from= і ?to= (включно), за замовчуванням - поточний місяць
+func (h *ReportHandler) GetCategoryTotals(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	// Отримання айді користувача з заголовка авторизації
+	userID, err := h.tokenMng.ExtractUserIDFromRequest(r)
+	if err != nil {
+		writeUnauthorized(w, r, err)
+		return
+	}
+
+	from, err := parseDayParam(r, "from", false)
+	if err != nil {
+		writeInvalidParam(w, r, "from")
+		return
+	}
+
+	to, err := parseDayParam(r, "to", true)
+	if err != nil {
+		writeInvalidParam(w, r, "to")
+		return
+	}
+
+	report, err := h.repService.GetCategoryTotals(r.Context(), userID, from, to)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, report)
+}
+
+// GetBudgetProgress повертає виконання бюджетів за ?month=2006-01, за замовчуванням - поточний місяць
+func (h *ReportHandler) GetBudgetProgress(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	// Отримання айді користувача з заголовка авторизації
+	userID, err := h.tokenMng.ExtractUserIDFromRequest(r)
+	if err != nil {
+		writeUnauthorized(w, r, err)
+		return
+	}
+
+	month, err := parseMonthParam(r, "month")
+	if err != nil {
+		writeInvalidParam(w, r, "month")
+		return
+	}
+
+	progress, err := h.repService.GetBudgetProgress(r.Context(), userID, month)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, progress)
+}
+
+// GetChart малює діаграму у форматі SVG: ?type=categories (кругова, ?from=&to=), monthly
+// (стовпчики за місяцями, ?from=&to=&category=) або budgets (виконання бюджетів, ?month=)
+func (h *ReportHandler) GetChart(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	// Отримання айді користувача з заголовка авторизації
+	userID, err := h.tokenMng.ExtractUserIDFromRequest(r)
+	if err != nil {
+		writeUnauthorized(w, r, err)
+		return
+	}
+
+	from, err := parseDayParam(r, "from", false)
+	if err != nil {
+		writeInvalidParam(w, r, "from")
+		return
+	}
+
+	to, err := parseDayParam(r, "to", true)
+	if err != nil {
+		writeInvalidParam(w, r, "to")
+		return
+	}
+
+	month, err := parseMonthParam(r, "month")
+	if err != nil {
+		writeInvalidParam(w, r, "month")
+		return
+	}
+
+	chartType := r.URL.Query().Get("type")
+	if chartType == "" {
+		chartType = "categories"
+	}
+	if chartType != "categories" && chartType != "monthly" && chartType != "budgets" {
+		writeInvalidParam(w, r, "type")
+		return
+	}
+
+	// Діаграма малюється в буфер, щоб помилку можна було повернути як звичайну відповідь
+	var svg bytes.Buffer
+	err = h.renderChart(r.Context(), &svg, userID, chartType, from, to, month, r.URL.Query().Get("category"))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/svg+xml")
+	w.Header().Set("Cache-Control", "private, no-store")
+	w.WriteHeader(http.StatusOK)
+	w.Write(svg.Bytes())
+}
+
+func (h *ReportHandler) renderChart(ctx context.Context, w io.Writer, userID int, chartType string, from, to, month time.Time, category string) error {
+	switch chartType {
+	case "monthly":
+		series, err := h.repService.GetTimeSeries(ctx, userID, "month", from, to, category, 0)
+		if err != nil {
+			return err
+		}
+
+		points := make([]chart.Point, 0, len(series.Buckets))
+		for _, bucket := range series.Buckets {
+			points = append(points, chart.Point{Label: bucket.Start.Format("Jan 2006"), Value: float64(bucket.Total)})
+		}
+		return chart.Bars(w, "Monthly spending", points)
+
+	case "budgets":
+		progress, err := h.repService.GetBudgetProgress(ctx, userID, month)
+		if err != nil {
+			return err
+		}
+
+		items := make([]chart.Progress, 0, len(progress))
+		for _, budget := range progress {
+			items = append(items, chart.Progress{Label: budget.Category, Value: float64(budget.Spent), Limit: float64(budget.Budget)})
+		}
+		return chart.ProgressBars(w, "Budgets", items)
+	}
+
+	report, err := h.repService.GetCategoryTotals(ctx, userID, from, to)
+	if err != nil {
+		return err
+	}
+
+	points := make([]chart.Point, 0, len(report.Categories))
+	for _, category := range report.Categories {
+		points = append(points, chart.Point{Label: category.Category, Value: float64(category.Total)})
+	}
+	return chart.Pie(w, "Spending by category", points)
+}
+
+// GetStatement повертає місячну виписку у форматі PDF за ?month=2006-01, за замовчуванням - поточний місяць
+func (h *ReportHandler) GetStatement(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	// Отримання айді користувача з заголовка авторизації
+	userID, err := h.tokenMng.ExtractUserIDFromRequest(r)
+	if err != nil {
+		writeUnauthorized(w, r, err)
+		return
+	}
+
+	month, err := parseMonthParam(r, "month")
+	if err != nil {
+		writeInvalidParam(w, r, "month")
+		return
+	}
+
+	statement, err := h.repService.GetStatement(r.Context(), userID, month)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	var document bytes.Buffer
+	err = writeStatementPDF(&document, statement)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", `attachment; filename="statement-`+statement.Month+`.pdf"`)
+	w.Header().Set("Cache-Control", "private, no-store")
+	w.WriteHeader(http.StatusOK)
+	w.Write(document.Bytes())
+}
+
+// parseMonthParam розбирає місяць у форматі 2006-01 з параметра запиту (перший день місяця)
+func parseMonthParam(r *http.Request, name string) (time.Time, error) {
+	rawMonth := r.URL.Query().Get(name)
+	if rawMonth == "" {
+		return time.Time{}, nil
+	}
+
+	return time.Parse("2006-01", rawMonth)
+}
@@ -0,0 +1,177 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/ChomuCake/uni-golang-labs/logging"
+	"github.com/ChomuCake/uni-golang-labs/models"
+	"github.com/ChomuCake/uni-golang-labs/ratelimit"
+	"github.com/ChomuCake/uni-golang-labs/services"
+	"github.com/ChomuCake/uni-golang-labs/util"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// problemDetails - тіло відповіді з помилкою у форматі RFC 7807 (application/problem+json).
+// Code - машиночитний код помилки, Errors - проблеми з окремими полями запиту
+type problemDetails struct {
+	Type     string                `json:"type"`
+	Title    string                `json:"title"`
+	Status   int                   `json:"status"`
+	Detail   string                `json:"detail,omitempty"`
+	Instance string                `json:"instance,omitempty"`
+	Code     string                `json:"code"`
+	Errors   []services.FieldError `json:"errors,omitempty"`
+}
+
+func writeJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	// Після WriteHeader статус змінити вже не можна, тому помилку кодування лише ігноруємо
+	_ = json.NewEncoder(w).Encode(data)
+}
+
+func writeProblem(w http.ResponseWriter, r *http.Request, problem problemDetails) {
+	if problem.Type == "" {
+		problem.Type = "about:blank"
+	}
+	problem.Title = http.StatusText(problem.Status)
+	problem.Instance = r.URL.Path
+
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(problem.Status)
+	_ = json.NewEncoder(w).Encode(problem)
+}
+
+// writeError відображає помилку сервісного шару на HTTP-статус; внутрішні деталі клієнту не показуються,
+// а записуються в журнал разом з ідентифікатором запиту і користувача
+func writeError(w http.ResponseWriter, r *http.Request, err error) {
+	problem := problemDetails{
+		Status: statusFromError(err),
+		Code:   "internal_error",
+		Detail: "internal server error",
+	}
+
+	var serviceErr *services.Error
+	if errors.As(err, &serviceErr) && problem.Status != http.StatusInternalServerError {
+		problem.Code = serviceErr.Code
+		problem.Detail = serviceErr.Message
+		problem.Errors = serviceErr.Fields
+
+		if serviceErr.RetryAfter > 0 {
+			w.Header().Set("Retry-After", ratelimit.RetryAfterSeconds(serviceErr.RetryAfter))
+		}
+	}
+
+	// Запит до бази скасовано за дедлайном або через від'єднання клієнта - це не збій сервера
+	if problem.Status == http.StatusServiceUnavailable {
+		problem.Code = "timeout"
+		problem.Detail = "request took too long to process"
+		logging.FromContext(r.Context()).Warn("request cancelled", "method", r.Method, "path", r.URL.Path, "error", err.Error())
+	}
+
+	if problem.Status == http.StatusInternalServerError {
+		logInternalError(r, err)
+		span := trace.SpanFromContext(r.Context())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	writeProblem(w, r, problem)
+}
+
+func logInternalError(r *http.Request, err error) {
+	attrs := []any{"method", r.Method, "path", r.URL.Path, "error", err.Error()}
+
+	var serviceErr *services.Error
+	if errors.As(err, &serviceErr) {
+		attrs = append(attrs, "code", serviceErr.Code)
+		if serviceErr.Err != nil {
+			attrs = append(attrs, "cause", serviceErr.Err.Error())
+		}
+	}
+
+	logging.FromContext(r.Context()).Error("request failed", attrs...)
+}
+
+func statusFromError(err error) int {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, context.Canceled):
+		return http.StatusServiceUnavailable
+	case errors.Is(err, services.ErrInvalid):
+		return http.StatusBadRequest
+	case errors.Is(err, services.ErrUnauthorized):
+		return http.StatusUnauthorized
+	case errors.Is(err, services.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, services.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrConflict):
+		return http.StatusConflict
+	case errors.Is(err, services.ErrTooMany):
+		return http.StatusTooManyRequests
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func writeMalformedBody(w http.ResponseWriter, r *http.Request) {
+	writeProblem(w, r, problemDetails{
+		Status: http.StatusBadRequest,
+		Code:   "malformed_body",
+		Detail: "request body must be valid JSON",
+	})
+}
+
+// writeDecodeError розрізняє некоректний JSON і некоректний формат дати у тілі запиту
+func writeDecodeError(w http.ResponseWriter, r *http.Request, err error) {
+	var dateErr *models.DateFormatError
+	if !errors.As(err, &dateErr) {
+		writeMalformedBody(w, r)
+		return
+	}
+
+	writeProblem(w, r, problemDetails{
+		Status: http.StatusBadRequest,
+		Code:   "validation_failed",
+		Detail: "validation failed",
+		Errors: []services.FieldError{{Field: "date", Code: "invalid_format", Message: dateErr.Error()}},
+	})
+}
+
+// writeUnauthorized відповідає на невдалу автентифікацію. Персональний токен доступу без потрібного
+// scope чи на маршруті, недоступному для таких токенів, отримує 403 з кодом причини, як і запит
+// cookie-сесії без CSRF-токена. Збій перевірки версії токенів - помилка сервера, а не 401
+func writeUnauthorized(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, services.ErrForbidden) || errors.Is(err, services.ErrInternal) || errors.Is(err, util.ErrTokenCheck) {
+		writeError(w, r, err)
+		return
+	}
+	if errors.Is(err, util.ErrCSRFToken) {
+		writeProblem(w, r, problemDetails{
+			Status: http.StatusForbidden,
+			Code:   "invalid_csrf_token",
+			Detail: "missing or invalid " + util.CSRFHeader + " header",
+		})
+		return
+	}
+
+	writeProblem(w, r, problemDetails{
+		Status: http.StatusUnauthorized,
+		Code:   "invalid_token",
+		Detail: "missing or invalid authorization token",
+	})
+}
+
+// writeInvalidParam повідомляє про некоректний параметр шляху або запиту
+func writeInvalidParam(w http.ResponseWriter, r *http.Request, name string) {
+	writeProblem(w, r, problemDetails{
+		Status: http.StatusBadRequest,
+		Code:   "invalid_parameter",
+		Detail: "invalid request parameter",
+		Errors: []services.FieldError{{Field: name, Code: "invalid", Message: "parameter " + name + " has invalid format"}},
+	})
+}
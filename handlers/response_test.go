@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ChomuCake/uni-golang-labs/logging"
+	"github.com/ChomuCake/uni-golang-labs/services"
+)
+
+func TestWriteError_ProblemDetails(t *testing.T) {
+	tests := []struct {
+		name           string
+		err            error
+		expectedStatus int
+		expectedCode   string
+	}{
+		{"not found", &services.Error{Kind: services.ErrNotFound, Code: "ledger_not_found", Message: "ledger not found"}, http.StatusNotFound, "ledger_not_found"},
+		{"forbidden", &services.Error{Kind: services.ErrForbidden, Code: "insufficient_ledger_role", Message: "insufficient ledger role"}, http.StatusForbidden, "insufficient_ledger_role"},
+		{"wrapped invalid", fmt.Errorf("create: %w", &services.Error{Kind: services.ErrInvalid, Code: "invalid_sort", Message: "bad sort"}), http.StatusBadRequest, "invalid_sort"},
+		{"internal hides cause", &services.Error{Kind: services.ErrInternal, Code: "expense_create_failed", Message: "failed", Err: errors.New("dial tcp")}, http.StatusInternalServerError, "internal_error"},
+		{"unknown error", errors.New("boom"), http.StatusInternalServerError, "internal_error"},
+		{"account locked", &services.Error{Kind: services.ErrTooMany, Code: "account_locked", Message: "locked", RetryAfter: 90 * time.Second}, http.StatusTooManyRequests, "account_locked"},
+		{"query deadline", &services.Error{Kind: services.ErrInternal, Code: "expenses_fetch_failed", Message: "failed", Err: context.DeadlineExceeded}, http.StatusServiceUnavailable, "timeout"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			rr := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/expenses", nil)
+
+			// Act
+			writeError(rr, req, tt.err)
+
+			// Assert
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("Received incorrect status: received %v, expected %v", rr.Code, tt.expectedStatus)
+			}
+
+			if contentType := rr.Header().Get("Content-Type"); contentType != "application/problem+json" {
+				t.Errorf("Received incorrect content type: received %v", contentType)
+			}
+
+			var problem problemDetails
+			err := json.NewDecoder(rr.Body).Decode(&problem)
+			if err != nil {
+				t.Fatalf("Failed to decode problem: %v", err)
+			}
+
+			if problem.Code != tt.expectedCode || problem.Status != tt.expectedStatus || problem.Instance != "/expenses" {
+				t.Errorf("Received incorrect problem: received %+v", problem)
+			}
+
+			if retryAfter := rr.Header().Get("Retry-After"); (tt.expectedStatus == http.StatusTooManyRequests) != (retryAfter == "90") {
+				t.Errorf("Received incorrect Retry-After: received %q", retryAfter)
+			}
+		})
+	}
+}
+
+func TestWriteError_LogsInternalCause(t *testing.T) {
+	// Arrange
+	var buf bytes.Buffer
+	logger := logging.New(&buf, slog.LevelInfo, logging.FormatJSON)
+	handler := logging.Middleware(logger, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, r, &services.Error{Kind: services.ErrInternal, Code: "expense_create_failed", Message: "failed", Err: errors.New("dial tcp")})
+	}))
+	req := httptest.NewRequest(http.MethodPost, "/expenses", nil)
+	req.Header.Set(logging.RequestIDHeader, "req-1")
+
+	// Act
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	// Assert
+	var entry map[string]interface{}
+	_ = json.Unmarshal([]byte(strings.SplitN(buf.String(), "\n", 2)[0]), &entry)
+	if entry["msg"] != "request failed" || entry["cause"] != "dial tcp" || entry["request_id"] != "req-1" {
+		t.Errorf("Received incorrect error log: %s", buf.String())
+	}
+}
+
+func TestWithDBDeadline_CancelsContext(t *testing.T) {
+	// Arrange
+	var deadlineSet bool
+	handler := WithDBDeadline(time.Millisecond, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, deadlineSet = r.Context().Deadline()
+		<-r.Context().Done()
+		writeError(w, r, &services.Error{Kind: services.ErrInternal, Code: "expenses_fetch_failed", Message: "failed", Err: r.Context().Err()})
+	}))
+	rr := httptest.NewRecorder()
+
+	// Act
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/expenses", nil))
+
+	// Assert
+	if !deadlineSet || rr.Code != http.StatusServiceUnavailable {
+		t.Errorf("Received incorrect response: deadline %v, status %v, expected status %v", deadlineSet, rr.Code, http.StatusServiceUnavailable)
+	}
+}
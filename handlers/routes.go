@@ -0,0 +1,27 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+
+	"github.com/ChomuCake/uni-golang-labs/util"
+)
+
+// routeRegistrar - частина *httprouter.Router, потрібна обробникам для реєстрації маршрутів;
+// завдяки інтерфейсу маршрути можна перелічити в тестах без запуску сервера
+type routeRegistrar interface {
+	GET(path string, handle httprouter.Handle)
+	POST(path string, handle httprouter.Handle)
+	PUT(path string, handle httprouter.Handle)
+	PATCH(path string, handle httprouter.Handle)
+	DELETE(path string, handle httprouter.Handle)
+}
+
+// withScope вказує, який scope персонального токена доступу потрібен маршруту; маршрути без
+// withScope (керування обліковим записом і токенами) персональні токени не приймають
+func withScope(scope string, handle httprouter.Handle) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		handle(w, r.WithContext(util.WithScope(r.Context(), scope)), ps)
+	}
+}
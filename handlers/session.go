@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/ChomuCake/uni-golang-labs/util"
+)
+
+// sessionRequested - браузерний інтерфейс входить з ?session=cookie і отримує JWT не в заголовку,
+// а в HttpOnly cookie, тож XSS не може викрасти токен
+func sessionRequested(r *http.Request) bool {
+	return r.URL.Query().Get("session") == "cookie"
+}
+
+// writeToken віддає новий JWT: у cookie-сесії (запитаній чи вже наявній) - в cookie разом
+// з CSRF-токеном, інакше - в заголовку Authorization
+func writeToken(w http.ResponseWriter, r *http.Request, secure bool, tokenString string) {
+	if sessionRequested(r) || util.SessionCookieUsed(r) {
+		setSessionCookies(w, secure, tokenString, int(util.TokenTTL.Seconds()))
+		return
+	}
+
+	w.Header().Set("Authorization", tokenString)
+}
+
+// setSessionCookies встановлює (або з maxAge < 0 видаляє) cookie сесії і CSRF-токена.
+// CSRF cookie не HttpOnly: скрипт інтерфейсу повторює його значення в заголовку util.CSRFHeader.
+// secure вимикається лише для локальної розробки по HTTP: за проксі, що завершує TLS, r.TLS
+// завжди nil, тож з'єднання не показує, чи браузер звертався по HTTPS
+func setSessionCookies(w http.ResponseWriter, secure bool, tokenString string, maxAge int) {
+	csrfToken := ""
+	if tokenString != "" {
+		csrfToken = util.CSRFToken(tokenString)
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     util.SessionCookie,
+		Value:    tokenString,
+		Path:     "/",
+		MaxAge:   maxAge,
+		HttpOnly: true,
+		Secure:   secure,
+		SameSite: http.SameSiteStrictMode,
+	})
+	http.SetCookie(w, &http.Cookie{
+		Name:     util.CSRFCookie,
+		Value:    csrfToken,
+		Path:     "/",
+		MaxAge:   maxAge,
+		Secure:   secure,
+		SameSite: http.SameSiteStrictMode,
+	})
+}
+
+func clearSessionCookies(w http.ResponseWriter, secure bool) {
+	setSessionCookies(w, secure, "", -1)
+}
@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/julienschmidt/httprouter"
+
+	"github.com/ChomuCake/uni-golang-labs/models"
+	"github.com/ChomuCake/uni-golang-labs/util"
+)
+
+// sessionUserService пускає будь-кого як користувача 7 і приймає зміни профілю
+type sessionUserService struct {
+	passwordUserService
+}
+
+func (s *sessionUserService) LoginUser(ctx context.Context, user models.User) (models.User, error) {
+	return models.User{ID: 7, Username: user.Username, TokenVersion: s.versions[7]}, nil
+}
+
+func (s *sessionUserService) UpdateProfile(ctx context.Context, userID int, update models.ProfileUpdate) (models.User, error) {
+	return models.User{ID: userID, Username: "alice", TimeZone: "UTC"}, nil
+}
+
+// sessionCookies повертає cookie відповіді за назвою
+func sessionCookies(rr *httptest.ResponseRecorder) map[string]*http.Cookie {
+	cookies := map[string]*http.Cookie{}
+	for _, cookie := range rr.Result().Cookies() {
+		cookies[cookie.Name] = cookie
+	}
+	return cookies
+}
+
+func TestUserHandler_CookieSession(t *testing.T) {
+	// Arrange
+	versions := tokenVersionStub{7: 0}
+	tokenMng := util.JWTTokenManager{Versions: versions}
+	router := httprouter.New()
+	NewUserHandler(&sessionUserService{passwordUserService{versions: versions}}, tokenMng).RegisterRoutesUser(router)
+
+	request := func(method, path string, session *http.Cookie, csrf, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		if session != nil {
+			req.AddCookie(session)
+		}
+		if csrf != "" {
+			req.Header.Set(util.CSRFHeader, csrf)
+		}
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr
+	}
+
+	// Act
+	login := request(http.MethodPost, "/login?session=cookie", nil, "", `{"username":"alice","password":"secret-pass1"}`)
+	cookies := sessionCookies(login)
+	session, csrf := cookies[util.SessionCookie], cookies[util.CSRFCookie]
+	if session == nil || csrf == nil {
+		t.Fatalf("Received incorrect cookies: received %v, expected %s and %s", cookies, util.SessionCookie, util.CSRFCookie)
+	}
+
+	profile := request(http.MethodGet, "/me", session, "", "")
+	noCSRF := request(http.MethodPatch, "/me", session, "", `{"time_zone":"UTC"}`)
+	forged := request(http.MethodPatch, "/me", session, util.CSRFToken("other session"), `{"time_zone":"UTC"}`)
+	withCSRF := request(http.MethodPatch, "/me", session, csrf.Value, `{"time_zone":"UTC"}`)
+	changed := request(http.MethodPost, "/me/password", session, csrf.Value, `{"current_password":"secret-pass1","new_password":"new-pass1"}`)
+	oldSession := request(http.MethodGet, "/me", session, "", "")
+	current := sessionCookies(changed)[util.SessionCookie]
+	newSession := request(http.MethodGet, "/me", current, "", "")
+	logoutNoCSRF := request(http.MethodPost, "/logout", current, "", "")
+	logout := request(http.MethodPost, "/logout", current, util.CSRFToken(current.Value), "")
+
+	// Assert
+	if login.Code != http.StatusOK || login.Header().Get("Authorization") != "" {
+		t.Errorf("Received incorrect login response: received %v, Authorization %q, expected %v without the header", login.Code, login.Header().Get("Authorization"), http.StatusOK)
+	}
+	if !session.HttpOnly || !session.Secure || session.SameSite != http.SameSiteStrictMode || session.Path != "/" {
+		t.Errorf("Received insecure session cookie: %+v", session)
+	}
+	if csrf.HttpOnly || !csrf.Secure || csrf.Value != util.CSRFToken(session.Value) {
+		t.Errorf("Received incorrect CSRF cookie: %+v", csrf)
+	}
+	if profile.Code != http.StatusOK || withCSRF.Code != http.StatusOK || changed.Code != http.StatusOK {
+		t.Errorf("Received incorrect status: received %v, %v, %v, expected %v", profile.Code, withCSRF.Code, changed.Code, http.StatusOK)
+	}
+	for name, rr := range map[string]*httptest.ResponseRecorder{"without CSRF token": noCSRF, "with forged CSRF token": forged, "logout without CSRF token": logoutNoCSRF} {
+		if rr.Code != http.StatusForbidden || !strings.Contains(rr.Body.String(), "invalid_csrf_token") {
+			t.Errorf("Received incorrect response %s: received %v %s, expected %v", name, rr.Code, rr.Body, http.StatusForbidden)
+		}
+	}
+	if oldSession.Code != http.StatusUnauthorized || newSession.Code != http.StatusOK {
+		t.Errorf("Received incorrect status after password change: received %v and %v, expected %v and %v", oldSession.Code, newSession.Code, http.StatusUnauthorized, http.StatusOK)
+	}
+	if cleared := sessionCookies(logoutNoCSRF)[util.SessionCookie]; cleared != nil {
+		t.Errorf("Received incorrect logout cookie without CSRF token: %+v, expected the session to stay", cleared)
+	}
+	if cleared := sessionCookies(logout)[util.SessionCookie]; cleared == nil || cleared.MaxAge >= 0 {
+		t.Errorf("Received incorrect logout cookie: %+v, expected an expired cookie", cleared)
+	}
+}
+
+func TestUserHandler_SessionCookieSecure(t *testing.T) {
+	tests := []struct {
+		name   string
+		secure bool
+	}{
+		// За проксі, що завершує TLS, запит приходить по HTTP, а cookie все одно мають бути Secure
+		{"secure", true},
+		// FINTRACK_COOKIE_SECURE=false для локальної розробки по HTTP
+		{"local HTTP", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			versions := tokenVersionStub{7: 0}
+			router := httprouter.New()
+			handler := NewUserHandler(&sessionUserService{passwordUserService{versions: versions}}, util.JWTTokenManager{Versions: versions})
+			handler.SetCookieSecure(tt.secure)
+			handler.RegisterRoutesUser(router)
+			req := httptest.NewRequest(http.MethodPost, "/login?session=cookie", strings.NewReader(`{"username":"alice","password":"secret-pass1"}`))
+			rr := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(rr, req)
+
+			// Assert
+			cookies := sessionCookies(rr)
+			for _, name := range []string{util.SessionCookie, util.CSRFCookie} {
+				if cookies[name] == nil || cookies[name].Secure != tt.secure {
+					t.Errorf("Received incorrect %s cookie: %+v, expected Secure %v", name, cookies[name], tt.secure)
+				}
+			}
+		})
+	}
+}
+
+func TestUserHandler_BearerTokenNeedsNoCSRF(t *testing.T) {
+	// Arrange: заголовок Authorization браузер сам не додає, тож CSRF-токен API-клієнтам не потрібен
+	tokenMng := util.JWTTokenManager{Versions: tokenVersionStub{7: 0}}
+	router := httprouter.New()
+	NewUserHandler(&sessionUserService{passwordUserService{versions: tokenVersionStub{7: 0}}}, tokenMng).RegisterRoutesUser(router)
+	token, _ := tokenMng.GenerateToken(models.User{ID: 7, Username: "alice"})
+
+	req := httptest.NewRequest(http.MethodPatch, "/me", strings.NewReader(`{"time_zone":"UTC"}`))
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.AddCookie(&http.Cookie{Name: util.SessionCookie, Value: "stale"})
+	rr := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(rr, req)
+
+	// Assert
+	if rr.Code != http.StatusOK {
+		t.Errorf("Received incorrect status: received %v, expected %v", rr.Code, http.StatusOK)
+	}
+}
@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/julienschmidt/httprouter"
+
+	"github.com/ChomuCake/uni-golang-labs/models"
+)
+
+// інтерфейс settlementService описується в тому ж файлі що і використовується
+type settlementService interface {
+	GetBalances(ctx context.Context, userID, ledgerID int) ([]models.Balance, error)
+	SuggestSettlements(ctx context.Context, userID, ledgerID int) ([]models.Balance, error)
+	GetSettlements(ctx context.Context, userID, ledgerID int) ([]models.Settlement, error)
+	RecordSettlement(ctx context.Context, userID, ledgerID int, settlement models.Settlement) (models.Settlement, error)
+}
+
+type SettlementHandler struct {
+	setService settlementService
+	tokenMng   tokenManager
+}
+
+func NewSettlementHandler(setService settlementService, tokenMng tokenManager) *SettlementHandler {
+	return &SettlementHandler{
+		setService: setService,
+		tokenMng:   tokenMng,
+	}
+}
+
+func (h *SettlementHandler) RegisterRoutesSettlement(router routeRegistrar) {
+	router.GET("/ledgers/:id/balances", withScope(models.ScopeLedgersRead, h.GetBalances))
+	router.GET("/ledgers/:id/settle-up", withScope(models.ScopeLedgersRead, h.SuggestSettlements))
+	router.GET("/ledgers/:id/settlements", withScope(models.ScopeLedgersRead, h.GetSettlements))
+	router.POST("/ledgers/:id/settlements", withScope(models.ScopeLedgersWrite, h.RecordSettlement))
+}
+
+func (h *SettlementHandler) GetBalances(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	h.writeLedgerData(w, r, params, func(userID, ledgerID int) (interface{}, error) {
+		return h.setService.GetBalances(r.Context(), userID, ledgerID)
+	})
+}
+
+func (h *SettlementHandler) SuggestSettlements(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	h.writeLedgerData(w, r, params, func(userID, ledgerID int) (interface{}, error) {
+		return h.setService.SuggestSettlements(r.Context(), userID, ledgerID)
+	})
+}
+
+func (h *SettlementHandler) GetSettlements(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	h.writeLedgerData(w, r, params, func(userID, ledgerID int) (interface{}, error) {
+		return h.setService.GetSettlements(r.Context(), userID, ledgerID)
+	})
+}
+
+func (h *SettlementHandler) RecordSettlement(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	var settlement models.Settlement
+	err := json.NewDecoder(r.Body).Decode(&settlement)
+	if err != nil {
+		writeMalformedBody(w, r)
+		return
+	}
+
+	// Отримання айді користувача з заголовка авторизації
+	userID, err := h.tokenMng.ExtractUserIDFromRequest(r)
+	if err != nil {
+		writeUnauthorized(w, r, err)
+		return
+	}
+
+	ledgerID, err := strconv.Atoi(params.ByName("id"))
+	if err != nil {
+		writeInvalidParam(w, r, "id")
+		return
+	}
+
+	recorded, err := h.setService.RecordSettlement(r.Context(), userID, ledgerID, settlement)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, recorded)
+}
+
+// writeLedgerData виконує спільну для GET-запитів журналу роботу: авторизація, розбір id та відповідь у JSON
+func (h *SettlementHandler) writeLedgerData(w http.ResponseWriter, r *http.Request, params httprouter.Params, get func(userID, ledgerID int) (interface{}, error)) {
+	// Отримання айді користувача з заголовка авторизації
+	userID, err := h.tokenMng.ExtractUserIDFromRequest(r)
+	if err != nil {
+		writeUnauthorized(w, r, err)
+		return
+	}
+
+	ledgerID, err := strconv.Atoi(params.ByName("id"))
+	if err != nil {
+		writeInvalidParam(w, r, "id")
+		return
+	}
+
+	data, err := get(userID, ledgerID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, data)
+}
@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/ChomuCake/uni-golang-labs/models"
+	"github.com/ChomuCake/uni-golang-labs/pdf"
+)
+
+const (
+	statementMargin   = 50.0
+	statementRight    = pdf.PageWidth - statementMargin
+	statementFontSize = 10.0
+	statementRow      = 16.0
+)
+
+// statementLayout розкладає виписку по сторінках, починаючи нову, коли поточна заповнена
+type statementLayout struct {
+	doc  *pdf.Document
+	page *pdf.Page
+	y    float64
+}
+
+func (l *statementLayout) newPage() {
+	l.page = l.doc.AddPage()
+	l.y = pdf.PageHeight - statementMargin
+}
+
+// row резервує місце під рядок висотою height і повертає його базову лінію
+func (l *statementLayout) row(height float64) float64 {
+	if l.y-height < statementMargin {
+		l.newPage()
+	}
+	l.y -= height
+	return l.y
+}
+
+// section виводить заголовок розділу та заголовки колонок таблиці (ліва колонка і праві, вирівняні по краю)
+func (l *statementLayout) section(title string, left []string, right []string, rightEdges []float64) {
+	// Заголовок розділу не залишається внизу сторінки без жодного рядка таблиці
+	if l.y-statementRow*5 < statementMargin {
+		l.newPage()
+	}
+	l.page.Text(statementMargin, l.row(statementRow*2), pdf.Bold, 13, title)
+	l.tableRow(pdf.Bold, left, right, rightEdges)
+	l.page.Line(statementMargin, l.y-4, statementRight, l.y-4)
+}
+
+func (l *statementLayout) tableRow(font pdf.Font, left []string, right []string, rightEdges []float64) {
+	y := l.row(statementRow)
+	for i, text := range left {
+		l.page.Text(statementMargin+float64(i)*90, y, font, statementFontSize, text)
+	}
+	for i, text := range right {
+		l.page.TextRight(rightEdges[i], y, font, statementFontSize, text)
+	}
+}
+
+// writeStatementPDF малює місячну виписку: власника й період, підсумки за категоріями,
+// порівняння з бюджетами та перелік витрат
+func writeStatementPDF(w io.Writer, statement models.Statement) error {
+	loc, err := time.LoadLocation(statement.TimeZone)
+	if err != nil {
+		loc = time.UTC
+	}
+
+	l := &statementLayout{doc: pdf.New("Expense statement " + statement.Month)}
+	l.newPage()
+
+	l.page.Text(statementMargin, l.row(20), pdf.Bold, 18, "Expense statement")
+	l.page.Text(statementMargin, l.row(24), pdf.Regular, 11, "Account holder: "+statement.Username)
+	l.page.Text(statementMargin, l.row(statementRow), pdf.Regular, 11, fmt.Sprintf("Period: %s - %s (%s)",
+		statement.From.In(loc).Format("2006-01-02"), statement.To.In(loc).AddDate(0, 0, -1).Format("2006-01-02"), loc))
+	l.page.Text(statementMargin, l.row(statementRow), pdf.Bold, 11, "Total spent: "+strconv.Itoa(statement.Total))
+
+	// Підсумки за категоріями
+	edges := []float64{statementRight - 100, statementRight}
+	l.section("Totals by category", []string{"Category"}, []string{"Amount", "Share"}, edges)
+	for _, category := range statement.Categories {
+		l.tableRow(pdf.Regular, []string{category.Category},
+			[]string{strconv.Itoa(category.Total), fmt.Sprintf("%.2f%%", category.Share)}, edges)
+	}
+
+	// Порівняння з бюджетами
+	if len(statement.Budgets) > 0 {
+		edges = []float64{statementRight - 300, statementRight - 200, statementRight - 100, statementRight}
+		l.section("Budget comparison", []string{"Category"}, []string{"Budget", "Spent", "Remaining", "Used"}, edges)
+		for _, budget := range statement.Budgets {
+			l.tableRow(pdf.Regular, []string{budget.Category}, []string{strconv.Itoa(budget.Budget),
+				strconv.Itoa(budget.Spent), strconv.Itoa(budget.Remaining), fmt.Sprintf("%.2f%%", budget.Percent)}, edges)
+		}
+	}
+
+	// Перелік витрат
+	edges = []float64{statementRight}
+	l.section("Expenses", []string{"Date", "Category"}, []string{"Amount"}, edges)
+	for _, expense := range statement.Expenses {
+		l.tableRow(pdf.Regular, []string{expense.Date.In(loc).Format("2006-01-02"), expense.Category},
+			[]string{strconv.Itoa(expense.Amount)}, edges)
+	}
+	l.page.Line(statementMargin, l.y-4, statementRight, l.y-4)
+	l.tableRow(pdf.Bold, []string{"Total"}, []string{strconv.Itoa(statement.Total)}, edges)
+
+	_, err = l.doc.WriteTo(w)
+	return err
+}
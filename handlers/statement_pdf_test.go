@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ChomuCake/uni-golang-labs/models"
+	"github.com/ChomuCake/uni-golang-labs/pdf"
+)
+
+func TestWriteStatementPDF(t *testing.T) {
+	// Arrange
+	statement := models.Statement{
+		Username:   "Оксана",
+		TimeZone:   "UTC",
+		Month:      "2024-03",
+		From:       time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		To:         time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC),
+		Categories: []models.CategoryTotal{{Category: "Їжа", Total: 100, Share: 100}},
+		Budgets:    []models.BudgetProgress{{Category: "Їжа", Budget: 80, Spent: 100, Remaining: -20, Percent: 125}},
+	}
+	// Достатньо витрат, щоб перелік не вмістився на одну сторінку
+	for i := 0; i < 100; i++ {
+		statement.Expenses = append(statement.Expenses, models.Expense{ID: i, Category: "Їжа", Amount: 1, Date: statement.From})
+		statement.Total++
+	}
+
+	// Act
+	var buf bytes.Buffer
+	err := writeStatementPDF(&buf, statement)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Received an error: received %v, expected %v", err, nil)
+	}
+	out := buf.String()
+	if !strings.HasPrefix(out, "%PDF-1.4") || !strings.Contains(out, "/Count 3") {
+		t.Errorf("Expected a three-page PDF document")
+	}
+	texts := strings.Join(pdf.ExtractText(buf.Bytes()), "\n")
+	for _, expected := range []string{"Account holder: Оксана", "Period: 2024-03-01 - 2024-03-31 (UTC)", "Budget comparison", "\nЇжа\n"} {
+		if !strings.Contains(texts, expected) {
+			t.Errorf("Expected %q in statement", expected)
+		}
+	}
+}
@@ -0,0 +1,17 @@
+package handlers
+
+import (
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("github.com/ChomuCake/uni-golang-labs/handlers")
+
+// startSpan починає спан обробника і повертає запит з контекстом цього спану,
+// щоб спани сервісу та репозиторію стали його дочірніми
+func startSpan(r *http.Request, name string) (*http.Request, trace.Span) {
+	ctx, span := tracer.Start(r.Context(), name)
+	return r.WithContext(ctx), span
+}
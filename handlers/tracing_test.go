@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ChomuCake/uni-golang-labs/models"
+	"github.com/ChomuCake/uni-golang-labs/services"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// failingExpenseService перевіряє, що сервіс отримав контекст зі спаном обробника
+type failingExpenseService struct {
+	parent trace.SpanContext
+}
+
+func (s *failingExpenseService) CreateExpense(ctx context.Context, userID, ledgerID int, expense models.Expense) error {
+	return nil
+}
+
+func (s *failingExpenseService) GetExpenses(ctx context.Context, userID, ledgerID int, sortExpensesBy string) ([]models.Expense, error) {
+	s.parent = trace.SpanContextFromContext(ctx)
+	return nil, &services.Error{Kind: services.ErrInternal, Code: "expenses_fetch_failed", Message: "failed", Err: errors.New("dial tcp")}
+}
+
+func (s *failingExpenseService) UpdateExpense(ctx context.Context, userID, ledgerID int, updatedExpense models.Expense) error {
+	return nil
+}
+
+func (s *failingExpenseService) DeleteExpense(ctx context.Context, userID, ledgerID int, expenseID string) error {
+	return nil
+}
+
+type staticTokenManager struct{}
+
+func (staticTokenManager) ExtractUserIDFromRequest(r *http.Request) (int, error) { return 1, nil }
+
+func TestExpenseHandler_GetExpenses_RecordsSpan(t *testing.T) {
+	// Arrange
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	service := &failingExpenseService{}
+	h := NewExpenseHandler(service, staticTokenManager{})
+
+	// Act
+	h.GetExpenses(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/expenses", nil), nil)
+
+	// Assert
+	spans := recorder.Ended()
+	if len(spans) != 1 || spans[0].Name() != "ExpenseHandler.GetExpenses" {
+		t.Fatalf("Received incorrect spans: received %v, expected ExpenseHandler.GetExpenses", spans)
+	}
+
+	if spans[0].Status().Code != codes.Error || spans[0].SpanContext().SpanID() != service.parent.SpanID() {
+		t.Errorf("Received incorrect span: status %v, service context %v", spans[0].Status(), service.parent)
+	}
+}
@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+
+	"github.com/ChomuCake/uni-golang-labs/models"
+)
+
+// інтерфейс twoFactorService описується в тому ж файлі що і використовується
+type twoFactorService interface {
+	Setup(ctx context.Context, userID int) (models.TOTPSetup, error)
+	Enable(ctx context.Context, userID int, code string) (models.RecoveryCodes, error)
+	Disable(ctx context.Context, userID int, code string) error
+}
+
+type TwoFactorHandler struct {
+	tfService twoFactorService
+	tokenMng  tokenManager
+}
+
+func NewTwoFactorHandler(tfService twoFactorService, tokenMng tokenManager) *TwoFactorHandler {
+	return &TwoFactorHandler{tfService: tfService, tokenMng: tokenMng}
+}
+
+func (h *TwoFactorHandler) RegisterRoutesTwoFactor(router routeRegistrar) {
+	router.POST("/me/2fa/setup", h.Setup)
+	router.POST("/me/2fa/enable", h.Enable)
+	router.POST("/me/2fa/disable", h.Disable)
+}
+
+// Setup повертає новий секрет, otpauth URI і QR-код; 2FA запрацює після Enable
+func (h *TwoFactorHandler) Setup(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	// Отримання айді користувача з заголовка авторизації
+	userID, err := h.tokenMng.ExtractUserIDFromRequest(r)
+	if err != nil {
+		writeUnauthorized(w, r, err)
+		return
+	}
+
+	setup, err := h.tfService.Setup(r.Context(), userID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, setup)
+}
+
+// Enable підтверджує секрет першим кодом і повертає коди відновлення (лише цього разу)
+func (h *TwoFactorHandler) Enable(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	var code models.TwoFactorCode
+	err := json.NewDecoder(r.Body).Decode(&code)
+	if err != nil {
+		writeMalformedBody(w, r)
+		return
+	}
+
+	// Отримання айді користувача з заголовка авторизації
+	userID, err := h.tokenMng.ExtractUserIDFromRequest(r)
+	if err != nil {
+		writeUnauthorized(w, r, err)
+		return
+	}
+
+	codes, err := h.tfService.Enable(r.Context(), userID, code.Code)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, codes)
+}
+
+func (h *TwoFactorHandler) Disable(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	var code models.TwoFactorCode
+	err := json.NewDecoder(r.Body).Decode(&code)
+	if err != nil {
+		writeMalformedBody(w, r)
+		return
+	}
+
+	// Отримання айді користувача з заголовка авторизації
+	userID, err := h.tokenMng.ExtractUserIDFromRequest(r)
+	if err != nil {
+		writeUnauthorized(w, r, err)
+		return
+	}
+
+	err = h.tfService.Disable(r.Context(), userID, code.Code)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
@@ -1,26 +1,37 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 
 	"github.com/ChomuCake/uni-golang-labs/models"
+	"github.com/ChomuCake/uni-golang-labs/util"
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/julienschmidt/httprouter"
 )
 
 type userService interface {
-	RegisterUser(user models.User) error
-	LoginUser(user models.User) (models.User, error)
+	RegisterUser(ctx context.Context, user models.User) error
+	LoginUser(ctx context.Context, user models.User) (models.User, error)
+	CompleteLogin(ctx context.Context, userID int, code string) (models.User, error)
+	GetProfile(ctx context.Context, userID int) (models.User, error)
+	UpdateProfile(ctx context.Context, userID int, update models.ProfileUpdate) (models.User, error)
+	ChangePassword(ctx context.Context, userID int, change models.PasswordChange) (models.User, error)
+	DeleteAccount(ctx context.Context, userID int) error
 }
 
 type tokenManagerUser interface {
 	GenerateToken(user models.User) (string, error)
+	GenerateChallengeToken(user models.User) (string, error)
+	VerifyChallengeToken(token string) (int, error)
+	ExtractUserIDFromRequest(r *http.Request) (int, error)
 }
 
 type UserHandler struct {
-	uService userService
-	tokenMng tokenManagerUser
+	uService        userService
+	tokenMng        tokenManagerUser
+	insecureCookies bool // cookie сесії без Secure - лише для локальної розробки по HTTP
 }
 
 func NewUserHandler(uService userService, tokenMng tokenManagerUser) *UserHandler {
@@ -30,22 +41,33 @@ func NewUserHandler(uService userService, tokenMng tokenManagerUser) *UserHandle
 	}
 }
 
-func (h *UserHandler) RegisterRoutesUser(router *httprouter.Router) {
+// SetCookieSecure задає прапорець Secure для cookie сесії (типово ввімкнений)
+func (h *UserHandler) SetCookieSecure(secure bool) {
+	h.insecureCookies = !secure
+}
+
+func (h *UserHandler) RegisterRoutesUser(router routeRegistrar) {
 	router.POST("/register", h.RegisterUser)
 	router.POST("/login", h.LoginUser)
+	router.POST("/login/2fa", h.LoginTwoFactor)
+	router.POST("/logout", h.Logout)
+	router.GET("/me", h.GetProfile)
+	router.PATCH("/me", h.UpdateProfile)
+	router.POST("/me/password", h.ChangePassword)
+	router.DELETE("/me", h.DeleteAccount)
 }
 
 func (h *UserHandler) RegisterUser(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
 	var user models.User
 	err := json.NewDecoder(r.Body).Decode(&user)
 	if err != nil {
-		w.WriteHeader(http.StatusBadRequest)
+		writeMalformedBody(w, r)
 		return
 	}
 
-	err = h.uService.RegisterUser(user)
+	err = h.uService.RegisterUser(r.Context(), user)
 	if err != nil {
-		w.WriteHeader(http.StatusBadRequest)
+		writeError(w, r, err)
 		return
 	}
 
@@ -55,23 +77,172 @@ func (h *UserHandler) LoginUser(w http.ResponseWriter, r *http.Request, _ httpro
 	var user models.User
 	err := json.NewDecoder(r.Body).Decode(&user)
 	if err != nil {
-		w.WriteHeader(http.StatusBadRequest)
+		writeMalformedBody(w, r)
 		return
 	}
 
-	existingUser, err := h.uService.LoginUser(user)
+	existingUser, err := h.uService.LoginUser(r.Context(), user)
 	if err != nil {
-		w.WriteHeader(http.StatusUnauthorized)
+		writeError(w, r, err)
+		return
+	}
+
+	// З увімкненою 2FA замість JWT повертається токен для другого кроку (POST /login/2fa)
+	if existingUser.TwoFactorEnabled {
+		challenge, err := h.tokenMng.GenerateChallengeToken(existingUser)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, models.TwoFactorChallenge{Required: true, ChallengeToken: challenge})
 		return
 	}
 
 	tokenString, err := h.tokenMng.GenerateToken(existingUser)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
+		writeError(w, r, err)
+		return
+	}
+
+	// Встановлення токена в заголовок відповіді (або в cookie для cookie-сесії)
+	writeToken(w, r, !h.insecureCookies, tokenString)
+	w.WriteHeader(http.StatusOK)
+}
+
+// LoginTwoFactor - другий крок входу: токен з POST /login і код з автентифікатора або код відновлення
+func (h *UserHandler) LoginTwoFactor(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	var login models.TwoFactorLogin
+	err := json.NewDecoder(r.Body).Decode(&login)
+	if err != nil {
+		writeMalformedBody(w, r)
+		return
+	}
+
+	userID, err := h.tokenMng.VerifyChallengeToken(login.ChallengeToken)
+	if err != nil {
+		writeUnauthorized(w, r, err)
+		return
+	}
+
+	user, err := h.uService.CompleteLogin(r.Context(), userID, login.Code)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	tokenString, err := h.tokenMng.GenerateToken(user)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeToken(w, r, !h.insecureCookies, tokenString)
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *UserHandler) GetProfile(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	// Отримання айді користувача з заголовка авторизації
+	userID, err := h.tokenMng.ExtractUserIDFromRequest(r)
+	if err != nil {
+		writeUnauthorized(w, r, err)
+		return
+	}
+
+	user, err := h.uService.GetProfile(r.Context(), userID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, user)
+}
+
+// UpdateProfile змінює лише передані поля профілю (username, time_zone)
+func (h *UserHandler) UpdateProfile(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	var update models.ProfileUpdate
+	err := json.NewDecoder(r.Body).Decode(&update)
+	if err != nil {
+		writeMalformedBody(w, r)
+		return
+	}
+
+	// Отримання айді користувача з заголовка авторизації
+	userID, err := h.tokenMng.ExtractUserIDFromRequest(r)
+	if err != nil {
+		writeUnauthorized(w, r, err)
+		return
+	}
+
+	user, err := h.uService.UpdateProfile(r.Context(), userID, update)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, user)
+}
+
+// ChangePassword відкликає всі видані токени, а поточній сесії повертає новий у заголовку Authorization
+// (cookie-сесії - оновлює cookie)
+func (h *UserHandler) ChangePassword(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	var change models.PasswordChange
+	err := json.NewDecoder(r.Body).Decode(&change)
+	if err != nil {
+		writeMalformedBody(w, r)
+		return
+	}
+
+	// Отримання айді користувача з заголовка авторизації
+	userID, err := h.tokenMng.ExtractUserIDFromRequest(r)
+	if err != nil {
+		writeUnauthorized(w, r, err)
+		return
+	}
+
+	user, err := h.uService.ChangePassword(r.Context(), userID, change)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	tokenString, err := h.tokenMng.GenerateToken(user)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeToken(w, r, !h.insecureCookies, tokenString)
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *UserHandler) DeleteAccount(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	// Отримання айді користувача з заголовка авторизації
+	userID, err := h.tokenMng.ExtractUserIDFromRequest(r)
+	if err != nil {
+		writeUnauthorized(w, r, err)
+		return
+	}
+
+	err = h.uService.DeleteAccount(r.Context(), userID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	clearSessionCookies(w, !h.insecureCookies)
+	w.WriteHeader(http.StatusOK)
+}
+
+// Logout завершує cookie-сесію браузера; JWT у заголовку клієнт просто забуває сам.
+// Як і інші запити cookie-сесії, вихід вимагає CSRF-токен, інакше чужа сторінка могла б розлогінити користувача
+func (h *UserHandler) Logout(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	err := util.CheckCSRF(r)
+	if err != nil {
+		writeUnauthorized(w, r, err)
 		return
 	}
 
-	// Встановлення токена в заголовок відповіді
-	w.Header().Set("Authorization", tokenString)
+	clearSessionCookies(w, !h.insecureCookies)
 	w.WriteHeader(http.StatusOK)
 }
@@ -0,0 +1,179 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/julienschmidt/httprouter"
+
+	"github.com/ChomuCake/uni-golang-labs/models"
+	"github.com/ChomuCake/uni-golang-labs/services"
+	"github.com/ChomuCake/uni-golang-labs/util"
+)
+
+// tokenVersionStub зберігає версії токенів, як стовпець users.token_version
+type tokenVersionStub map[int]int
+
+func (v tokenVersionStub) GetTokenVersion(ctx context.Context, userID int) (int, error) {
+	return v[userID], nil
+}
+
+// failingTokenVersions повертає задану помилку, як недоступна база
+type failingTokenVersions struct{ err error }
+
+func (v failingTokenVersions) GetTokenVersion(ctx context.Context, userID int) (int, error) {
+	return 0, v.err
+}
+
+// passwordUserService імітує зміну пароля: версія токенів користувача збільшується
+type passwordUserService struct {
+	userService
+	versions tokenVersionStub
+}
+
+func (s *passwordUserService) GetProfile(ctx context.Context, userID int) (models.User, error) {
+	return models.User{ID: userID, Username: "alice", TimeZone: "UTC"}, nil
+}
+
+func (s *passwordUserService) ChangePassword(ctx context.Context, userID int, change models.PasswordChange) (models.User, error) {
+	s.versions[userID]++
+	return models.User{ID: userID, Username: "alice", TokenVersion: s.versions[userID]}, nil
+}
+
+func TestUserHandler_ChangePassword_RevokesOtherTokens(t *testing.T) {
+	// Arrange
+	versions := tokenVersionStub{7: 0}
+	tokenMng := util.JWTTokenManager{Versions: versions}
+	router := httprouter.New()
+	NewUserHandler(&passwordUserService{versions: versions}, tokenMng).RegisterRoutesUser(router)
+
+	oldToken, err := tokenMng.GenerateToken(models.User{ID: 7, Username: "alice"})
+	if err != nil {
+		t.Fatalf("Received an error: received %v, expected %v", err, nil)
+	}
+
+	request := func(method, path, token, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer "+token)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr
+	}
+
+	// Act
+	changed := request(http.MethodPost, "/me/password", oldToken, `{"current_password":"old-pass1","new_password":"new-pass1"}`)
+	newToken := changed.Header().Get("Authorization")
+	withOld := request(http.MethodGet, "/me", oldToken, "")
+	withNew := request(http.MethodGet, "/me", newToken, "")
+
+	// Assert
+	if changed.Code != http.StatusOK || newToken == "" {
+		t.Fatalf("Received incorrect response: received %v, token %q, expected %v with a new token", changed.Code, newToken, http.StatusOK)
+	}
+	if withOld.Code != http.StatusUnauthorized {
+		t.Errorf("Received incorrect status for revoked token: received %v, expected %v", withOld.Code, http.StatusUnauthorized)
+	}
+
+	var profile map[string]interface{}
+	_ = json.NewDecoder(withNew.Body).Decode(&profile)
+	if _, hasPassword := profile["password"]; withNew.Code != http.StatusOK || profile["username"] != "alice" || hasPassword {
+		t.Errorf("Received incorrect profile: received %v %v", withNew.Code, profile)
+	}
+}
+
+func TestUserHandler_TokenVersionLookupFailure(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected int
+	}{
+		{name: "database outage", err: errors.New("dial tcp: connection refused"), expected: http.StatusInternalServerError},
+		{name: "deleted user", err: sql.ErrNoRows, expected: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			tokenMng := util.JWTTokenManager{Versions: failingTokenVersions{err: tt.err}}
+			router := httprouter.New()
+			NewUserHandler(&passwordUserService{}, tokenMng).RegisterRoutesUser(router)
+
+			token, err := tokenMng.GenerateToken(models.User{ID: 7, Username: "alice"})
+			if err != nil {
+				t.Fatalf("Received an error: received %v, expected %v", err, nil)
+			}
+			req := httptest.NewRequest(http.MethodGet, "/me", nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			rr := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(rr, req)
+
+			// Assert
+			if rr.Code != tt.expected {
+				t.Errorf("Received incorrect status: received %v, expected %v", rr.Code, tt.expected)
+			}
+		})
+	}
+}
+
+// twoFactorUserService імітує користувача з увімкненою 2FA і кодом "123456"
+type twoFactorUserService struct {
+	passwordUserService
+}
+
+func (s *twoFactorUserService) LoginUser(ctx context.Context, user models.User) (models.User, error) {
+	return models.User{ID: 7, Username: user.Username, TwoFactorEnabled: true}, nil
+}
+
+func (s *twoFactorUserService) CompleteLogin(ctx context.Context, userID int, code string) (models.User, error) {
+	if code != "123456" {
+		return models.User{}, &services.Error{Kind: services.ErrUnauthorized, Code: "invalid_2fa_code", Message: "two-factor code is invalid"}
+	}
+	return models.User{ID: userID, Username: "alice"}, nil
+}
+
+func TestUserHandler_LoginUser_TwoFactorChallenge(t *testing.T) {
+	// Arrange
+	tokenMng := util.JWTTokenManager{Versions: tokenVersionStub{7: 0}}
+	router := httprouter.New()
+	NewUserHandler(&twoFactorUserService{}, tokenMng).RegisterRoutesUser(router)
+
+	request := func(method, path, token, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer "+token)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr
+	}
+
+	// Act
+	login := request(http.MethodPost, "/login", "", `{"username":"alice","password":"pass-word1"}`)
+	var challenge models.TwoFactorChallenge
+	_ = json.NewDecoder(login.Body).Decode(&challenge)
+
+	withChallenge := request(http.MethodGet, "/me", challenge.ChallengeToken, "")
+	wrongCode := request(http.MethodPost, "/login/2fa", "", `{"challenge_token":"`+challenge.ChallengeToken+`","code":"000000"}`)
+	completed := request(http.MethodPost, "/login/2fa", "", `{"challenge_token":"`+challenge.ChallengeToken+`","code":"123456"}`)
+	token := completed.Header().Get("Authorization")
+
+	// Assert
+	if login.Code != http.StatusOK || login.Header().Get("Authorization") != "" || !challenge.Required || challenge.ChallengeToken == "" {
+		t.Fatalf("Received incorrect first step: received %v %+v, expected a challenge without a JWT", login.Code, challenge)
+	}
+	if withChallenge.Code != http.StatusUnauthorized {
+		t.Errorf("Challenge token must not grant API access: received %v, expected %v", withChallenge.Code, http.StatusUnauthorized)
+	}
+	if wrongCode.Code != http.StatusUnauthorized {
+		t.Errorf("Received incorrect status for wrong code: received %v, expected %v", wrongCode.Code, http.StatusUnauthorized)
+	}
+	if completed.Code != http.StatusOK || request(http.MethodGet, "/me", token, "").Code != http.StatusOK {
+		t.Errorf("Received incorrect second step: received %v, token %q", completed.Code, token)
+	}
+}
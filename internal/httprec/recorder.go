@@ -0,0 +1,37 @@
+// Package httprec містить спільну обгортку http.ResponseWriter для проміжних обробників журналу доступу,
+// трасування і метрик HTTP
+package httprec
+
+import "net/http"
+
+// Recorder запам'ятовує статус і розмір відповіді; якщо обробник не викликав WriteHeader, статус - 200
+type Recorder struct {
+	http.ResponseWriter
+	Status      int
+	Bytes       int
+	wroteHeader bool
+}
+
+func New(w http.ResponseWriter) *Recorder {
+	return &Recorder{ResponseWriter: w, Status: http.StatusOK}
+}
+
+func (w *Recorder) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.Status = status
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *Recorder) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	n, err := w.ResponseWriter.Write(b)
+	w.Bytes += n
+	return n, err
+}
+
+// Unwrap дає http.ResponseController доступ до Flush та дедлайнів початкового ResponseWriter
+func (w *Recorder) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,40 @@
+package httprec
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRecorder_StatusAndBytes(t *testing.T) {
+	// Arrange
+	implicit, explicit := New(httptest.NewRecorder()), New(httptest.NewRecorder())
+
+	// Act: статус фіксує перший WriteHeader або перший Write
+	_, _ = implicit.Write([]byte("hello"))
+	implicit.WriteHeader(http.StatusTeapot)
+	explicit.WriteHeader(http.StatusNotFound)
+	_, _ = explicit.Write([]byte("not found"))
+
+	// Assert
+	if implicit.Status != http.StatusOK || implicit.Bytes != 5 {
+		t.Errorf("Received incorrect result: received %v and %v bytes, expected %v and %v bytes", implicit.Status, implicit.Bytes, http.StatusOK, 5)
+	}
+	if explicit.Status != http.StatusNotFound || explicit.Bytes != 9 {
+		t.Errorf("Received incorrect result: received %v and %v bytes, expected %v and %v bytes", explicit.Status, explicit.Bytes, http.StatusNotFound, 9)
+	}
+}
+
+func TestRecorder_FlushThroughResponseController(t *testing.T) {
+	// Arrange
+	inner := httptest.NewRecorder()
+	rec := New(inner)
+
+	// Act
+	err := http.NewResponseController(rec).Flush()
+
+	// Assert
+	if err != nil || !inner.Flushed {
+		t.Errorf("Received incorrect flush: received %v, flushed %v, expected %v, %v", err, inner.Flushed, nil, true)
+	}
+}
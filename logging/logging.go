@@ -0,0 +1,139 @@
+// Package logging налаштовує структуроване журналювання (log/slog) і прив'язує до кожного
+// запиту ідентифікатор X-Request-ID та користувача, щоб записи одного запиту можна було зібрати разом
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/ChomuCake/uni-golang-labs/internal/httprec"
+)
+
+// Формати виводу
+const (
+	FormatJSON = "json"
+	FormatText = "text"
+)
+
+// RequestIDHeader - заголовок, у якому ідентифікатор запиту приймається від проксі і повертається клієнту
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength обмежує прийнятий від клієнта ідентифікатор, щоб він не роздував журнали
+const maxRequestIDLength = 128
+
+// New створює логер із заданим рівнем; format - FormatJSON або FormatText
+func New(w io.Writer, level slog.Level, format string) *slog.Logger {
+	opts := &slog.HandlerOptions{Level: level}
+	if format == FormatText {
+		return slog.New(slog.NewTextHandler(w, opts))
+	}
+
+	return slog.New(slog.NewJSONHandler(w, opts))
+}
+
+type stateKey struct{}
+
+// requestState живе в контексті запиту; userID заповнюється після перевірки токена,
+// тому поле змінюється вже після того, як Middleware поклав стан у контекст
+type requestState struct {
+	logger *slog.Logger
+	id     string
+	userID int
+}
+
+// Middleware призначає запиту ідентифікатор (приймає X-Request-ID від клієнта або генерує новий),
+// повертає його в заголовку відповіді і після обробки пише рядок журналу доступу
+func Middleware(logger *slog.Logger, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+
+		id := r.Header.Get(RequestIDHeader)
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+		w.Header().Set(RequestIDHeader, id)
+
+		state := &requestState{logger: logger, id: id}
+		rec := httprec.New(w)
+
+		next.ServeHTTP(rec, r.WithContext(context.WithValue(r.Context(), stateKey{}, state)))
+
+		attrs := []slog.Attr{
+			slog.String("request_id", id),
+			slog.String("method", r.Method),
+			slog.String("path", r.URL.Path),
+			slog.Int("status", rec.Status),
+			slog.Int("bytes", rec.Bytes),
+			slog.Duration("duration", time.Since(start)),
+			slog.String("remote_addr", r.RemoteAddr),
+		}
+		if state.userID != 0 {
+			attrs = append(attrs, slog.Int("user_id", state.userID))
+		}
+		if sc := trace.SpanContextFromContext(r.Context()); sc.HasTraceID() {
+			attrs = append(attrs, slog.String("trace_id", sc.TraceID().String()))
+		}
+		logger.LogAttrs(r.Context(), slog.LevelInfo, "request", attrs...)
+	})
+}
+
+// FromContext повертає логер з ідентифікатором запиту, користувача і трасування; поза запитом - slog.Default()
+func FromContext(ctx context.Context) *slog.Logger {
+	state, ok := ctx.Value(stateKey{}).(*requestState)
+	if !ok {
+		return slog.Default()
+	}
+
+	logger := state.logger.With("request_id", state.id)
+	if state.userID != 0 {
+		logger = logger.With("user_id", state.userID)
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.HasTraceID() {
+		logger = logger.With("trace_id", sc.TraceID().String())
+	}
+
+	return logger
+}
+
+// RequestID повертає ідентифікатор поточного запиту або порожній рядок
+func RequestID(ctx context.Context) string {
+	if state, ok := ctx.Value(stateKey{}).(*requestState); ok {
+		return state.id
+	}
+	return ""
+}
+
+// SetUserID запам'ятовує автентифікованого користувача для журналу доступу і журналу помилок
+func SetUserID(ctx context.Context, userID int) {
+	if state, ok := ctx.Value(stateKey{}).(*requestState); ok {
+		state.userID = userID
+	}
+}
+
+// validRequestID приймає лише видимі ASCII-символи, щоб клієнт не міг підробити рядки текстового журналу
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+
+	return true
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,61 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMiddleware_PropagatesRequestIDAndLogsAccess(t *testing.T) {
+	// Arrange
+	var buf bytes.Buffer
+	logger := New(&buf, slog.LevelInfo, FormatJSON)
+	handler := Middleware(logger, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		SetUserID(r.Context(), 7)
+		FromContext(r.Context()).Error("request failed", "error", "boom")
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	req := httptest.NewRequest(http.MethodGet, "/expenses", nil)
+	req.Header.Set(RequestIDHeader, "abc-123")
+	w := httptest.NewRecorder()
+
+	// Act
+	handler.ServeHTTP(w, req)
+
+	// Assert
+	if got := w.Header().Get(RequestIDHeader); got != "abc-123" {
+		t.Errorf("Received incorrect request id: received %v, expected %v", got, "abc-123")
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Received incorrect number of log lines: received %v, expected %v", len(lines), 2)
+	}
+	for _, line := range lines {
+		var entry map[string]interface{}
+		_ = json.Unmarshal([]byte(line), &entry)
+		if entry["request_id"] != "abc-123" || entry["user_id"] != float64(7) {
+			t.Errorf("Received log entry without request context: %s", line)
+		}
+	}
+}
+
+func TestMiddleware_ReplacesInvalidRequestID(t *testing.T) {
+	// Arrange
+	handler := Middleware(New(&bytes.Buffer{}, slog.LevelInfo, FormatText), http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(RequestIDHeader, "forged\nline")
+	w := httptest.NewRecorder()
+
+	// Act
+	handler.ServeHTTP(w, req)
+
+	// Assert
+	if got := w.Header().Get(RequestIDHeader); len(got) != 32 {
+		t.Errorf("Received incorrect generated request id: received %q", got)
+	}
+}
@@ -0,0 +1,36 @@
+package mailer
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// FileMailer записує кожен лист в окремий файл .eml у каталозі Dir; такі файли відкриває
+// будь-який поштовий клієнт, тож лист можна переглянути без SMTP-сервера
+type FileMailer struct {
+	Dir  string
+	From string
+}
+
+func NewFileMailer(dir, from string) *FileMailer {
+	return &FileMailer{Dir: dir, From: from}
+}
+
+func (m *FileMailer) Send(_ context.Context, to, subject, body string) error {
+	now := time.Now()
+	msg, err := buildMessage(m.From, to, subject, body, now)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(m.Dir, 0o700)
+	if err != nil {
+		return err
+	}
+
+	// Лист містить посилання для відновлення пароля, тож читати його може лише власник процесу
+	name := now.UTC().Format("20060102T150405.000000000") + "-" + randomID()[:8] + ".eml"
+	return os.WriteFile(filepath.Join(m.Dir, name), msg, 0o600)
+}
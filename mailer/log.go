@@ -0,0 +1,21 @@
+package mailer
+
+import (
+	"context"
+	"log/slog"
+)
+
+// LogMailer лише записує лист у журнал разом з тілом, тобто й з посиланням для відновлення
+// пароля. Призначений для локальної розробки, у продакшені слід використовувати SMTPMailer
+type LogMailer struct {
+	logger *slog.Logger
+}
+
+func NewLogMailer(logger *slog.Logger) *LogMailer {
+	return &LogMailer{logger: logger}
+}
+
+func (m *LogMailer) Send(ctx context.Context, to, subject, body string) error {
+	m.logger.InfoContext(ctx, "mail not sent, logged instead", "to", to, "subject", subject, "body", body)
+	return nil
+}
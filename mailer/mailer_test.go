@@ -0,0 +1,201 @@
+package mailer
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"log/slog"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// smtpStandIn - мінімальний SMTP-сервер для тестів: приймає один лист і запам'ятовує конверт і дані
+type smtpStandIn struct {
+	addr     string
+	from     string
+	rcpt     []string
+	data     string
+	received chan struct{}
+}
+
+func startSMTPStandIn(t *testing.T) *smtpStandIn {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Received an error: received %v, expected %v", err, nil)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	s := &smtpStandIn{addr: listener.Addr().String(), received: make(chan struct{})}
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		s.serve(textproto.NewConn(conn))
+	}()
+
+	return s
+}
+
+func (s *smtpStandIn) serve(conn *textproto.Conn) {
+	_ = conn.PrintfLine("220 localhost ESMTP stand-in")
+	for {
+		line, err := conn.ReadLine()
+		if err != nil {
+			return
+		}
+
+		command := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+		switch command {
+		case "EHLO", "HELO":
+			_ = conn.PrintfLine("250 localhost")
+		case "MAIL":
+			s.from = line
+			_ = conn.PrintfLine("250 OK")
+		case "RCPT":
+			s.rcpt = append(s.rcpt, line)
+			_ = conn.PrintfLine("250 OK")
+		case "DATA":
+			_ = conn.PrintfLine("354 End data with <CR><LF>.<CR><LF>")
+			data, _ := conn.ReadDotBytes()
+			s.data = string(data)
+			_ = conn.PrintfLine("250 OK: queued")
+			close(s.received)
+		case "QUIT":
+			_ = conn.PrintfLine("221 Bye")
+			return
+		default:
+			_ = conn.PrintfLine("502 Command not implemented")
+		}
+	}
+}
+
+const testBody = "Відновлення пароля: http://localhost:8080/reset.html?token=abc\nПосилання дійсне годину."
+
+func TestSMTPMailer_Send(t *testing.T) {
+	// Arrange
+	server := startSMTPStandIn(t)
+	m := NewSMTPMailer(server.addr, "Fintrack <no-reply@fintrack.test>", "", "")
+
+	// Act
+	err := m.Send(context.Background(), "alice@example.com", "Відновлення пароля", testBody)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Received an error: received %v, expected %v", err, nil)
+	}
+	<-server.received
+
+	if server.from != "MAIL FROM:<no-reply@fintrack.test>" || len(server.rcpt) != 1 || server.rcpt[0] != "RCPT TO:<alice@example.com>" {
+		t.Errorf("Received incorrect envelope: from %q, rcpt %q", server.from, server.rcpt)
+	}
+	assertMessage(t, server.data)
+}
+
+func TestSMTPMailer_Send_ContextDeadline(t *testing.T) {
+	// Arrange: сервер приймає з'єднання, але нічого не відповідає
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Received an error: received %v, expected %v", err, nil)
+	}
+	defer listener.Close()
+	go func() {
+		conn, err := listener.Accept()
+		if err == nil {
+			defer conn.Close()
+			_, _ = io.Copy(io.Discard, conn)
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	// Act
+	start := time.Now()
+	err = NewSMTPMailer(listener.Addr().String(), "no-reply@fintrack.test", "", "").Send(ctx, "alice@example.com", "subject", "body")
+
+	// Assert
+	if err == nil || time.Since(start) > 5*time.Second {
+		t.Errorf("Received incorrect result: received %v after %v, expected timeout", err, time.Since(start))
+	}
+}
+
+func TestFileMailer_Send(t *testing.T) {
+	// Arrange
+	dir := filepath.Join(t.TempDir(), "mail")
+	m := NewFileMailer(dir, "Fintrack <no-reply@fintrack.test>")
+
+	// Act
+	err := m.Send(context.Background(), "alice@example.com", "Відновлення пароля", testBody)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Received an error: received %v, expected %v", err, nil)
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.eml"))
+	if len(files) != 1 {
+		t.Fatalf("Received incorrect files: received %v, expected one .eml", files)
+	}
+	data, _ := os.ReadFile(files[0])
+	assertMessage(t, string(data))
+}
+
+func TestMailer_RejectsHeaderInjection(t *testing.T) {
+	// Arrange
+	m := NewFileMailer(t.TempDir(), "no-reply@fintrack.test")
+
+	// Act
+	err := m.Send(context.Background(), "alice@example.com\r\nBcc: eve@example.com", "subject", "body")
+
+	// Assert
+	if err != errHeaderInjection {
+		t.Errorf("Received incorrect error: received %v, expected %v", err, errHeaderInjection)
+	}
+}
+
+func TestLogMailer_Send(t *testing.T) {
+	// Arrange
+	var out strings.Builder
+	m := NewLogMailer(slog.New(slog.NewJSONHandler(&out, nil)))
+
+	// Act
+	err := m.Send(context.Background(), "alice@example.com", "subject", testBody)
+
+	// Assert
+	if err != nil || !strings.Contains(out.String(), `"to":"alice@example.com"`) || !strings.Contains(out.String(), "token=abc") {
+		t.Errorf("Received incorrect log: received %v, %s", err, out.String())
+	}
+}
+
+// assertMessage розбирає лист як поштовий клієнт і перевіряє заголовки й декодоване тіло
+func assertMessage(t *testing.T, data string) {
+	t.Helper()
+
+	msg, err := mail.ReadMessage(bufio.NewReader(strings.NewReader(data)))
+	if err != nil {
+		t.Fatalf("Received an error: received %v, expected valid message", err)
+	}
+
+	subject, _ := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	if msg.Header.Get("To") != "alice@example.com" || subject != "Відновлення пароля" || msg.Header.Get("Message-ID") == "" {
+		t.Errorf("Received incorrect headers: %v", msg.Header)
+	}
+
+	body, _ := io.ReadAll(quotedprintable.NewReader(msg.Body))
+	// ReadDotBytes замінює CRLF на LF і залишає перенесення в кінці
+	if strings.TrimSuffix(strings.ReplaceAll(string(body), "\r\n", "\n"), "\n") != testBody {
+		t.Errorf("Received incorrect body: received %q, expected %q", body, testBody)
+	}
+}
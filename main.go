@@ -1,43 +1,341 @@
 package main
 
 import (
-	"log"
+	"context"
+	"fmt"
+	"log/slog"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+	_ "time/tzdata" // база часових поясів вбудовується в бінарник для контейнерів без /usr/share/zoneinfo
 
+	"github.com/ChomuCake/uni-golang-labs/api"
+	"github.com/ChomuCake/uni-golang-labs/config"
 	db "github.com/ChomuCake/uni-golang-labs/database"
 	"github.com/ChomuCake/uni-golang-labs/drepo"
 	"github.com/ChomuCake/uni-golang-labs/handlers"
+	"github.com/ChomuCake/uni-golang-labs/logging"
+	"github.com/ChomuCake/uni-golang-labs/mailer"
+	"github.com/ChomuCake/uni-golang-labs/metrics"
+	"github.com/ChomuCake/uni-golang-labs/migration"
+	"github.com/ChomuCake/uni-golang-labs/oidc"
+	"github.com/ChomuCake/uni-golang-labs/ratelimit"
 	"github.com/ChomuCake/uni-golang-labs/services"
+	"github.com/ChomuCake/uni-golang-labs/tracing"
 	"github.com/ChomuCake/uni-golang-labs/util"
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/julienschmidt/httprouter"
+	"github.com/redis/go-redis/v9"
 )
 
 func main() {
-	DB := &db.RealDatabase{}
-	defer DB.CloseDB()
+	// Уся робота виконується в run, щоб відкладене закриття бази спрацювало до виходу з процесу
+	err := run()
+	if err != nil {
+		slog.Error("server stopped", "error", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+
+	logger := logging.New(os.Stderr, cfg.LogLevel, cfg.LogFormat)
+	slog.SetDefault(logger)
+
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Config{
+		Exporter:     cfg.TraceExporter,
+		OTLPEndpoint: cfg.TraceOTLPEndpoint,
+		OTLPInsecure: cfg.TraceOTLPInsecure,
+		Stdout:       os.Stdout,
+	})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		// Спани, що залишилися в буфері, надсилаються після зупинки HTTP-сервера
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			logger.Error("flush traces", "error", err)
+		}
+	}()
+
+	DB := &db.RealDatabase{
+		DSN: cfg.DatabaseDSN,
+		Pool: db.PoolConfig{
+			MaxOpenConns:    cfg.DBMaxOpenConns,
+			MaxIdleConns:    cfg.DBMaxIdleConns,
+			ConnMaxLifetime: cfg.DBConnMaxLifetime,
+			ConnMaxIdleTime: cfg.DBConnMaxIdleTime,
+		},
+	}
+	err = DB.InitDB()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := DB.CloseDB(); err != nil {
+			logger.Error("close database", "error", err)
+		}
+	}()
+
+	// Фонові задачі мають завершуватися за цим контекстом: він скасовується за SIGINT/SIGTERM
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Сервер не запускається, поки база недоступна, інакше кожен запит завершувався б помилкою 500
+	err = DB.WaitReady(ctx, cfg.DBConnectAttempts, cfg.DBConnectBackoff, func(attempt int, err error) {
+		logger.Warn("database is not reachable", "attempt", attempt, "attempts", cfg.DBConnectAttempts, "error", err)
+	})
+	if err != nil {
+		return fmt.Errorf("connect to database: %w", err)
+	}
+
+	m := metrics.New()
+	m.RegisterDBStats(DB.GetDB(), "fintrack")
+
+	limiter, closeLimiter := newAuthLimiter(cfg)
+	defer func() {
+		if err := closeLimiter(); err != nil {
+			logger.Error("close rate limit store", "error", err)
+		}
+	}()
+
+	// Обгортки застосовуються зсередини назовні: спан трасування охоплює весь запит,
+	// тож його ідентифікатор потрапляє в журнал, а дедлайн діє лише на обробку в роутері.
+	// Відхилені обмежувачем запити (429) теж потрапляють у журнал доступу і метрики
+	var handler http.Handler = newRouter(DB, m, cfg, newMailer(cfg, logger))
+	handler = handlers.WithDBDeadline(cfg.DBRequestTimeout, handler)
+	handler = limiter.Middleware(handler)
+	handler = m.Middleware(handler)
+	handler = logging.Middleware(logger, handler)
+	handler = tracing.Middleware(handler)
+
+	server := &http.Server{
+		Addr:              cfg.HTTPAddr,
+		Handler:           handler,
+		ReadTimeout:       cfg.ReadTimeout,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+		ErrorLog:          slog.NewLogLogger(logger.Handler(), slog.LevelWarn),
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		logger.Info("listening", "addr", cfg.HTTPAddr, "tls", cfg.TLSEnabled(), "cookie_secure", cfg.CookieSecure)
+		if cfg.TLSEnabled() {
+			serveErr <- server.ListenAndServeTLS(cfg.TLSCertFile, cfg.TLSKeyFile)
+		} else {
+			serveErr <- server.ListenAndServe()
+		}
+	}()
+
+	select {
+	case err := <-serveErr:
+		return err
+	case <-ctx.Done():
+	}
+
+	// Нові з'єднання більше не приймаються, запити, що вже обробляються, отримують ShutdownTimeout на завершення
+	logger.Info("shutting down, waiting for in-flight requests", "timeout", cfg.ShutdownTimeout)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
 
-	err := DB.InitDB()
+	err = server.Shutdown(shutdownCtx)
 	if err != nil {
-		log.Fatal(err)
+		return fmt.Errorf("shutdown: %w", err)
 	}
 
+	return nil
+}
+
+// newAuthLimiter обмежує спроби входу (обидва кроки) з однієї адреси і для одного імені користувача, а також
+// запити листів відновлення пароля (тими самими лімітами - на адресу клієнта і на email);
+// повернена функція закриває з'єднання з Redis, якщо ліміти зберігаються там
+func newAuthLimiter(cfg config.Config) (*ratelimit.Limiter, func() error) {
+	var store ratelimit.Store = ratelimit.NewMemoryStore()
+	closeStore := func() error { return nil }
+
+	if cfg.RateLimitBackend == "redis" {
+		client := redis.NewClient(&redis.Options{Addr: cfg.RedisAddr})
+		store = ratelimit.NewRedisStore(client)
+		closeStore = client.Close
+	}
+
+	limiter := ratelimit.New(store,
+		ratelimit.Rule{
+			Name:   "login_ip",
+			Method: http.MethodPost,
+			Path:   "/login",
+			Limit:  ratelimit.Limit{Burst: cfg.LoginRateIPBurst, Every: cfg.LoginRateIPEvery},
+			Key:    ratelimit.ByIP,
+		},
+		ratelimit.Rule{
+			Name:   "login_username",
+			Method: http.MethodPost,
+			Path:   "/login",
+			Limit:  ratelimit.Limit{Burst: cfg.LoginRateUsernameBurst, Every: cfg.LoginRateUsernameEvery},
+			Key:    ratelimit.ByJSONField("username"),
+		},
+		ratelimit.Rule{
+			Name:   "login_2fa_ip",
+			Method: http.MethodPost,
+			Path:   "/login/2fa",
+			Limit:  ratelimit.Limit{Burst: cfg.LoginRateIPBurst, Every: cfg.LoginRateIPEvery},
+			Key:    ratelimit.ByIP,
+		},
+		ratelimit.Rule{
+			Name:   "forgot_ip",
+			Method: http.MethodPost,
+			Path:   "/password/forgot",
+			Limit:  ratelimit.Limit{Burst: cfg.LoginRateIPBurst, Every: cfg.LoginRateIPEvery},
+			Key:    ratelimit.ByIP,
+		},
+		ratelimit.Rule{
+			Name:   "forgot_email",
+			Method: http.MethodPost,
+			Path:   "/password/forgot",
+			Limit:  ratelimit.Limit{Burst: cfg.LoginRateUsernameBurst, Every: cfg.LoginRateUsernameEvery},
+			Key:    ratelimit.ByJSONField("email"),
+		},
+	)
+
+	return limiter, closeStore
+}
+
+// newMailer вибирає спосіб доставки листів за FINTRACK_MAIL_BACKEND
+func newMailer(cfg config.Config, logger *slog.Logger) services.Mailer {
+	switch cfg.MailBackend {
+	case "smtp":
+		return mailer.NewSMTPMailer(cfg.SMTPAddr, cfg.MailFrom, cfg.SMTPUsername, cfg.SMTPPassword)
+	case "file":
+		return mailer.NewFileMailer(cfg.MailDir, cfg.MailFrom)
+	default:
+		return mailer.NewLogMailer(logger)
+	}
+}
+
+// newRouter збирає репозиторії, сервіси та обробники і реєструє їх маршрути;
+// маршрути реєструються через metrics.Router, щоб метрики HTTP мали шаблон шляху
+func newRouter(DB *db.RealDatabase, m *metrics.Metrics, cfg config.Config, mail services.Mailer) *httprouter.Router {
 	router := httprouter.New()
+	routes := metrics.NewRouter(router)
 
 	expenseDB := drepo.NewExpenseDBMySQL(DB)
+	expenseDB.Observer = m
 	userDB := drepo.NewUserDBMySQL(DB)
+	userDB.Observer = m
+	ledgerDB := drepo.NewLedgerDBMySQL(DB)
+	ledgerDB.Observer = m
+	accountDB := drepo.NewAccountDBMySQL(DB)
+	accountDB.Observer = m
 
-	tokenManager := util.JWTTokenManager{}
-	expenseService := services.NewExpenseService(expenseDB, userDB)
+	accessTokenDB := drepo.NewAccessTokenDBMySQL(DB)
+	accessTokenDB.Observer = m
+	accessTokenService := services.NewAccessTokenService(accessTokenDB)
+
+	// Токени перевіряються за версією користувача, тож зміна пароля відкликає інші сесії;
+	// поряд з JWT приймаються персональні токени доступу зі scope маршруту
+	tokenManager := util.Authenticator{
+		JWTTokenManager: util.JWTTokenManager{Versions: userDB},
+		AccessTokens:    accessTokenService,
+	}
+	expenseService := services.NewExpenseService(expenseDB, userDB, ledgerDB, accountDB)
+	expenseService.SetMetrics(m)
 	expenseHandler := handlers.NewExpenseHandler(expenseService, tokenManager)
-	expenseHandler.RegisterRoutes(router)
+	expenseHandler.RegisterRoutes(routes)
+
+	twoFactorDB := drepo.NewTwoFactorDBMySQL(DB)
+	twoFactorDB.Observer = m
+	twoFactorService := services.NewTwoFactorService(twoFactorDB, userDB)
+	twoFactorHandler := handlers.NewTwoFactorHandler(twoFactorService, tokenManager)
+	twoFactorHandler.RegisterRoutesTwoFactor(routes)
 
 	userService := services.NewUserService(userDB)
+	userService.SetMetrics(m)
+	userService.SetTwoFactor(twoFactorService)
+	userService.SetAccessTokens(accessTokenService)
 	userHandler := handlers.NewUserHandler(userService, tokenManager)
-	userHandler.RegisterRoutesUser(router)
+	userHandler.SetCookieSecure(cfg.CookieSecure)
+	userHandler.RegisterRoutesUser(routes)
+
+	accessTokenHandler := handlers.NewAccessTokenHandler(accessTokenService, tokenManager)
+	accessTokenHandler.RegisterRoutesAccessTokens(routes)
+
+	// Вхід через OpenID Connect доступний лише з налаштованим постачальником
+	if cfg.OIDCEnabled() {
+		identityDB := drepo.NewIdentityDBMySQL(DB)
+		identityDB.Observer = m
+		provider := oidc.New(oidc.Config{
+			Issuer:       cfg.OIDCIssuer,
+			ClientID:     cfg.OIDCClientID,
+			ClientSecret: cfg.OIDCClientSecret,
+			RedirectURL:  cfg.OIDCRedirectURL,
+			Scopes:       cfg.OIDCScopes,
+		})
+		oidcHandler := handlers.NewOIDCHandler(provider, services.NewOIDCService(identityDB, userDB), tokenManager)
+		oidcHandler.SetCookieSecure(cfg.CookieSecure)
+		oidcHandler.RegisterRoutesOIDC(routes)
+	}
+
+	resetDB := drepo.NewPasswordResetDBMySQL(DB)
+	resetDB.Observer = m
+	resetService := services.NewPasswordResetService(userDB, resetDB, mail, cfg.PasswordResetURL, cfg.PasswordResetTTL)
+	resetService.SetAccessTokens(accessTokenService)
+	resetHandler := handlers.NewPasswordResetHandler(resetService)
+	resetHandler.RegisterRoutesPasswordReset(routes)
+
+	ledgerService := services.NewLedgerService(ledgerDB, userDB)
+	ledgerHandler := handlers.NewLedgerHandler(ledgerService, tokenManager)
+	ledgerHandler.RegisterRoutesLedger(routes)
+
+	settlementDB := drepo.NewSettlementDBMySQL(DB)
+	settlementDB.Observer = m
+	settlementService := services.NewSettlementService(settlementDB, ledgerDB)
+	settlementHandler := handlers.NewSettlementHandler(settlementService, tokenManager)
+	settlementHandler.RegisterRoutesSettlement(routes)
+
+	accountService := services.NewAccountService(accountDB, userDB)
+	accountHandler := handlers.NewAccountHandler(accountService, tokenManager)
+	accountHandler.RegisterRoutesAccount(routes)
+
+	budgetDB := drepo.NewBudgetDBMySQL(DB)
+	budgetDB.Observer = m
+	budgetService := services.NewBudgetService(budgetDB)
+	budgetHandler := handlers.NewBudgetHandler(budgetService, tokenManager)
+	budgetHandler.RegisterRoutesBudget(routes)
+
+	reportDB := drepo.NewReportDBMySQL(DB)
+	reportDB.Observer = m
+	reportService := services.NewReportService(reportDB, budgetDB, userDB)
+	reportHandler := handlers.NewReportHandler(reportService, tokenManager)
+	reportHandler.RegisterRoutesReport(routes)
+
+	openAPIHandler := handlers.NewOpenAPIHandler(api.OpenAPI)
+	openAPIHandler.RegisterRoutesOpenAPI(routes)
+
+	healthHandler := handlers.NewHealthHandler(drepo.NewHealthDBMySQL(DB), migration.Latest())
+	healthHandler.RegisterRoutesHealth(routes)
+
+	// Дашборд з діаграмами доступний за коротким шляхом, решта фронтенду - як статичні файли
+	routes.GET("/dashboard", func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+		http.ServeFile(w, r, "./frontend/dashboard.html")
+	})
+
+	metricsHandler := m.Handler()
+	routes.GET("/metrics", func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+		metricsHandler.ServeHTTP(w, r)
+	})
 
 	fs := http.FileServer(http.Dir("./frontend"))
 	router.NotFound = fs
 
-	log.Fatal(http.ListenAndServe(":8080", router))
+	return router
 }
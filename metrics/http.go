@@ -0,0 +1,87 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+
+	"github.com/ChomuCake/uni-golang-labs/internal/httprec"
+)
+
+// otherRoute - мітка для запитів, що не потрапили в жоден зареєстрований маршрут
+// (статичні файли фронтенду, 404), щоб довільні шляхи не роздували кількість часових рядів
+const otherRoute = "other"
+
+type routeKey struct{}
+
+// routeLabel передається через контекст від Middleware до обгорнутого обробника маршруту,
+// який записує в нього шаблон шляху (/expenses/:id замість /expenses/42)
+type routeLabel struct {
+	route string
+}
+
+// Middleware рахує запити та їх тривалість за маршрутом і статусом відповіді.
+// Шаблон маршруту відомий лише для обробників, зареєстрованих через Router
+func (m *Metrics) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		label := &routeLabel{route: otherRoute}
+		rec := httprec.New(w)
+
+		next.ServeHTTP(rec, r.WithContext(context.WithValue(r.Context(), routeKey{}, label)))
+
+		status := strconv.Itoa(rec.Status)
+		m.requests.WithLabelValues(r.Method, label.route, status).Inc()
+		m.requestDuration.WithLabelValues(r.Method, label.route, status).Observe(time.Since(start).Seconds())
+	})
+}
+
+// інтерфейс registrar описується в тому ж файлі що і використовується
+type registrar interface {
+	GET(path string, handle httprouter.Handle)
+	POST(path string, handle httprouter.Handle)
+	PUT(path string, handle httprouter.Handle)
+	PATCH(path string, handle httprouter.Handle)
+	DELETE(path string, handle httprouter.Handle)
+}
+
+// Router реєструє маршрути в обгорнутому роутері, позначаючи кожен обробник шаблоном його шляху
+type Router struct {
+	next registrar
+}
+
+func NewRouter(next registrar) *Router {
+	return &Router{next: next}
+}
+
+func (r *Router) GET(path string, handle httprouter.Handle) {
+	r.next.GET(path, withRoute(path, handle))
+}
+
+func (r *Router) POST(path string, handle httprouter.Handle) {
+	r.next.POST(path, withRoute(path, handle))
+}
+
+func (r *Router) PUT(path string, handle httprouter.Handle) {
+	r.next.PUT(path, withRoute(path, handle))
+}
+
+func (r *Router) PATCH(path string, handle httprouter.Handle) {
+	r.next.PATCH(path, withRoute(path, handle))
+}
+
+func (r *Router) DELETE(path string, handle httprouter.Handle) {
+	r.next.DELETE(path, withRoute(path, handle))
+}
+
+func withRoute(path string, handle httprouter.Handle) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		if label, ok := r.Context().Value(routeKey{}).(*routeLabel); ok {
+			label.route = path
+		}
+		handle(w, r, ps)
+	}
+}
@@ -0,0 +1,104 @@
+// Package metrics збирає метрики Prometheus для HTTP-шару, запитів до бази даних
+// і подій предметної області та віддає їх на /metrics
+package metrics
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "fintrack"
+
+// Metrics тримає власний реєстр замість глобального prometheus.DefaultRegisterer,
+// щоб тести могли створювати незалежні екземпляри
+type Metrics struct {
+	registry *prometheus.Registry
+
+	requests        *prometheus.CounterVec
+	requestDuration *prometheus.HistogramVec
+	queryDuration   *prometheus.HistogramVec
+	expensesCreated prometheus.Counter
+	loginsFailed    prometheus.Counter
+}
+
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "requests_total",
+			Help:      "HTTP requests by method, route pattern and status code.",
+		}, []string{"method", "route", "status"}),
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "request_duration_seconds",
+			Help:      "HTTP request latency by method, route pattern and status code.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		queryDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "db",
+			Name:      "query_duration_seconds",
+			Help:      "Repository query latency by repository, query and result.",
+			Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+		}, []string{"repository", "query", "result"}),
+		expensesCreated: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "expenses_created_total",
+			Help:      "Expenses and incomes successfully created.",
+		}),
+		loginsFailed: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "logins_failed_total",
+			Help:      "Login attempts rejected because of invalid credentials.",
+		}),
+	}
+
+	m.registry.MustRegister(
+		m.requests,
+		m.requestDuration,
+		m.queryDuration,
+		m.expensesCreated,
+		m.loginsFailed,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+
+	return m
+}
+
+// RegisterDBStats експортує sql.DBStats пулу з'єднань (go_sql_* з міткою db_name)
+func (m *Metrics) RegisterDBStats(db *sql.DB, name string) {
+	m.registry.MustRegister(collectors.NewDBStatsCollector(db, name))
+}
+
+// Handler віддає метрики у текстовому форматі Prometheus
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+// ObserveQuery записує тривалість запиту репозиторію. sql.ErrNoRows не вважається
+// помилкою: відсутність запису - звичайна відповідь бази
+func (m *Metrics) ObserveQuery(repository, query string, duration time.Duration, err error) {
+	result := "ok"
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		result = "error"
+	}
+	m.queryDuration.WithLabelValues(repository, query, result).Observe(duration.Seconds())
+}
+
+func (m *Metrics) ExpenseCreated() {
+	m.expensesCreated.Inc()
+}
+
+func (m *Metrics) LoginFailed() {
+	m.loginsFailed.Inc()
+}
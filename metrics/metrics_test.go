@@ -0,0 +1,73 @@
+package metrics
+
+import (
+	"database/sql"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestMiddleware_LabelsRoutePatternAndStatus(t *testing.T) {
+	// Arrange
+	m := New()
+	router := httprouter.New()
+	NewRouter(router).GET("/expenses/:id", func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	handler := m.Middleware(router)
+
+	// Act
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/expenses/42", nil))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/expenses/43", nil))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/unknown/path", nil))
+
+	// Assert
+	if got := testutil.ToFloat64(m.requests.WithLabelValues("GET", "/expenses/:id", "404")); got != 2 {
+		t.Errorf("Received incorrect count: received %v, expected %v", got, 2)
+	}
+
+	if got := testutil.ToFloat64(m.requests.WithLabelValues("GET", otherRoute, "404")); got != 1 {
+		t.Errorf("Received incorrect count for unmatched route: received %v, expected %v", got, 1)
+	}
+}
+
+func TestObserveQuery_NoRowsIsNotAnError(t *testing.T) {
+	// Arrange
+	m := New()
+
+	// Act
+	m.ObserveQuery("users", "GetUserByUsername", time.Millisecond, sql.ErrNoRows)
+	m.ObserveQuery("users", "GetUserByUsername", time.Millisecond, errors.New("connection reset"))
+
+	// Assert
+	if got := testutil.CollectAndCount(m.queryDuration); got != 2 {
+		t.Errorf("Received incorrect series count: received %v, expected %v", got, 2)
+	}
+}
+
+func TestHandler_ExposesBusinessCounters(t *testing.T) {
+	// Arrange
+	m := New()
+	m.ExpenseCreated()
+	m.LoginFailed()
+	m.LoginFailed()
+	w := httptest.NewRecorder()
+
+	// Act
+	m.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	// Assert
+	body, _ := io.ReadAll(w.Body)
+	for _, expected := range []string{"fintrack_expenses_created_total 1", "fintrack_logins_failed_total 2"} {
+		if !strings.Contains(string(body), expected) {
+			t.Errorf("Received metrics without %q", expected)
+		}
+	}
+}
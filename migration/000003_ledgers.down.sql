@@ -0,0 +1,8 @@
+-- migration/000003_ledgers.down
+
+ALTER TABLE expenses DROP FOREIGN KEY expenses_ibfk_2;
+ALTER TABLE expenses DROP COLUMN ledger_id;
+
+DROP TABLE ledger_members;
+
+DROP TABLE ledgers;
@@ -0,0 +1,24 @@
+-- migration/000003_ledgers.up
+
+-- Спільні журнали витрат
+CREATE TABLE ledgers (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    owner_id INT NOT NULL,
+    FOREIGN KEY (owner_id) REFERENCES users(id)
+);
+
+-- Учасники журналів та їх ролі (owner, editor, viewer)
+CREATE TABLE ledger_members (
+    ledger_id INT NOT NULL,
+    user_id INT NOT NULL,
+    role VARCHAR(16) NOT NULL,
+    PRIMARY KEY (ledger_id, user_id),
+    FOREIGN KEY (ledger_id) REFERENCES ledgers(id),
+    FOREIGN KEY (user_id) REFERENCES users(id)
+);
+
+-- Витрати без журналу (NULL) залишаються особистими
+ALTER TABLE expenses
+    ADD COLUMN ledger_id INT NULL,
+    ADD FOREIGN KEY (ledger_id) REFERENCES ledgers(id);
@@ -0,0 +1,8 @@
+-- migration/000004_expense_splits.down
+
+DROP TABLE settlements;
+
+DROP TABLE expense_splits;
+
+ALTER TABLE expenses DROP FOREIGN KEY expenses_ibfk_3;
+ALTER TABLE expenses DROP COLUMN paid_by, DROP COLUMN split_method;
@@ -0,0 +1,30 @@
+-- migration/000004_expense_splits.up
+
+-- Хто оплатив спільну витрату і як її розподілено
+ALTER TABLE expenses
+    ADD COLUMN paid_by INT NULL,
+    ADD COLUMN split_method VARCHAR(16) NULL,
+    ADD FOREIGN KEY (paid_by) REFERENCES users(id);
+
+-- Частки учасників у спільних витратах
+CREATE TABLE expense_splits (
+    expense_id INT NOT NULL,
+    user_id INT NOT NULL,
+    amount INT NOT NULL,
+    PRIMARY KEY (expense_id, user_id),
+    FOREIGN KEY (expense_id) REFERENCES expenses(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id)
+);
+
+-- Розрахунки між учасниками журналу
+CREATE TABLE settlements (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    ledger_id INT NOT NULL,
+    from_user_id INT NOT NULL,
+    to_user_id INT NOT NULL,
+    amount INT NOT NULL,
+    date TIMESTAMP NOT NULL,
+    FOREIGN KEY (ledger_id) REFERENCES ledgers(id),
+    FOREIGN KEY (from_user_id) REFERENCES users(id),
+    FOREIGN KEY (to_user_id) REFERENCES users(id)
+);
@@ -0,0 +1,8 @@
+-- migration/000005_accounts.down
+
+ALTER TABLE expenses DROP FOREIGN KEY expenses_ibfk_4;
+ALTER TABLE expenses DROP COLUMN account_id, DROP COLUMN kind;
+
+DROP TABLE transfers;
+
+DROP TABLE accounts;
@@ -0,0 +1,37 @@
+-- migration/000005_accounts.up
+
+-- Рахунки користувачів (готівка, картка, банк, заощадження)
+CREATE TABLE accounts (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    type VARCHAR(16) NOT NULL,
+    initial_balance INT NOT NULL DEFAULT 0,
+    FOREIGN KEY (user_id) REFERENCES users(id)
+);
+
+-- Перекази між рахунками не є витратами і зберігаються окремо
+CREATE TABLE transfers (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NOT NULL,
+    from_account_id INT NOT NULL,
+    to_account_id INT NOT NULL,
+    amount INT NOT NULL,
+    date TIMESTAMP NOT NULL,
+    FOREIGN KEY (user_id) REFERENCES users(id),
+    FOREIGN KEY (from_account_id) REFERENCES accounts(id),
+    FOREIGN KEY (to_account_id) REFERENCES accounts(id)
+);
+
+ALTER TABLE expenses
+    ADD COLUMN kind VARCHAR(16) NOT NULL DEFAULT 'expense',
+    ADD COLUMN account_id INT NULL;
+
+-- Кожен наявний користувач отримує рахунок "Cash", до якого прив'язуються його старі записи
+INSERT INTO accounts (user_id, name, type) SELECT id, 'Cash', 'cash' FROM users;
+
+UPDATE expenses e JOIN accounts a ON a.user_id = e.user_id SET e.account_id = a.id;
+
+ALTER TABLE expenses
+    MODIFY account_id INT NOT NULL,
+    ADD FOREIGN KEY (account_id) REFERENCES accounts(id);
@@ -0,0 +1,3 @@
+-- migration/000006_user_time_zone.down
+
+ALTER TABLE users DROP COLUMN time_zone;
@@ -0,0 +1,4 @@
+-- migration/000006_user_time_zone.up
+
+-- Часовий пояс користувача (IANA), у якому рахуються межі днів, тижнів, місяців і років
+ALTER TABLE users ADD COLUMN time_zone VARCHAR(64) NOT NULL DEFAULT 'UTC';
@@ -0,0 +1,3 @@
+-- migration/000007_budgets.down
+
+DROP TABLE budgets;
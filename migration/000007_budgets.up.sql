@@ -0,0 +1,11 @@
+-- migration/000007_budgets.up
+
+-- Місячний бюджет користувача на категорію витрат
+CREATE TABLE budgets (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NOT NULL,
+    category VARCHAR(64) NOT NULL,
+    amount INT NOT NULL,
+    UNIQUE KEY user_category (user_id, category),
+    FOREIGN KEY (user_id) REFERENCES users(id)
+);
@@ -0,0 +1,5 @@
+-- migration/000008_login_lockout.down
+
+ALTER TABLE users
+    DROP COLUMN locked_until,
+    DROP COLUMN failed_logins;
@@ -0,0 +1,6 @@
+-- migration/000008_login_lockout.up
+
+-- Лічильник невдалих спроб входу поспіль і час, до якого вхід заблоковано
+ALTER TABLE users
+    ADD COLUMN failed_logins INT NOT NULL DEFAULT 0,
+    ADD COLUMN locked_until DATETIME NULL;
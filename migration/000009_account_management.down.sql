@@ -0,0 +1,5 @@
+-- migration/000009_account_management.down
+
+ALTER TABLE users
+    DROP COLUMN deleted_at,
+    DROP COLUMN token_version;
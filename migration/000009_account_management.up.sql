@@ -0,0 +1,7 @@
+-- migration/000009_account_management.up
+
+-- Версія токенів: зміна пароля збільшує її, і видані раніше JWT перестають прийматися.
+-- Видалений користувач, на якого посилаються спільні журнали, залишається знеособленим записом
+ALTER TABLE users
+    ADD COLUMN token_version INT NOT NULL DEFAULT 0,
+    ADD COLUMN deleted_at DATETIME NULL;
@@ -0,0 +1,7 @@
+-- migration/000010_password_reset.down
+
+DROP TABLE password_reset_tokens;
+
+ALTER TABLE users
+    DROP INDEX users_email,
+    DROP COLUMN email;
@@ -0,0 +1,17 @@
+-- migration/000010_password_reset.up
+
+-- Адреса для відновлення пароля; у користувачів, зареєстрованих раніше, її немає
+ALTER TABLE users
+    ADD COLUMN email VARCHAR(255) NULL,
+    ADD UNIQUE KEY users_email (email);
+
+-- Токени відновлення пароля: зберігається лише SHA-256 токена, кожен діє до expires_at і лише один раз
+CREATE TABLE password_reset_tokens (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NOT NULL,
+    token_hash CHAR(64) NOT NULL,
+    expires_at DATETIME NOT NULL,
+    used_at DATETIME NULL,
+    UNIQUE KEY password_reset_token_hash (token_hash),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
@@ -0,0 +1,19 @@
+-- migration/000011_two_factor.up
+
+-- Секрет TOTP (base32) з'являється під час налаштування, а вхід з кодом вимагається лише після
+-- підтвердження першим кодом (totp_enabled). totp_last_step - останній прийнятий 30-секундний
+-- інтервал, щоб той самий код не можна було використати вдруге
+ALTER TABLE users
+    ADD COLUMN totp_secret VARCHAR(64) NULL,
+    ADD COLUMN totp_enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN totp_last_step BIGINT NOT NULL DEFAULT 0;
+
+-- Одноразові коди відновлення на випадок втрати пристрою з автентифікатором (зберігається SHA-256)
+CREATE TABLE recovery_codes (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NOT NULL,
+    code_hash CHAR(64) NOT NULL,
+    used_at DATETIME NULL,
+    UNIQUE KEY recovery_code_user_hash (user_id, code_hash),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
@@ -0,0 +1,3 @@
+-- migration/000012_user_identities.down
+
+DROP TABLE user_identities;
@@ -0,0 +1,14 @@
+-- migration/000012_user_identities.up
+
+-- Зв'язок користувача із зовнішнім обліковим записом OpenID Connect: постачальник (issuer)
+-- і незмінний ідентифікатор користувача в ньому (sub). Користувачі, створені під час першого
+-- входу через OIDC, не мають пароля (порожній рядок) і не можуть увійти за паролем
+CREATE TABLE user_identities (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NOT NULL,
+    issuer VARCHAR(255) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY user_identity_issuer_subject (issuer, subject),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
@@ -0,0 +1,3 @@
+-- migration/000013_access_tokens.down
+
+DROP TABLE access_tokens;
@@ -0,0 +1,16 @@
+-- migration/000013_access_tokens.up
+
+-- Персональні токени доступу для скриптів та інтеграцій: зберігається лише SHA-256 токена,
+-- scopes - дозволені операції через кому (наприклад, "expenses:read,expenses:write");
+-- expires_at NULL - безстроковий токен
+CREATE TABLE access_tokens (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NOT NULL,
+    name VARCHAR(64) NOT NULL,
+    token_hash CHAR(64) NOT NULL UNIQUE,
+    scopes VARCHAR(255) NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at DATETIME NULL,
+    last_used_at DATETIME NULL,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
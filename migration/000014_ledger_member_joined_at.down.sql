@@ -0,0 +1,3 @@
+-- migration/000014_ledger_member_joined_at.down
+
+ALTER TABLE ledger_members DROP COLUMN joined_at;
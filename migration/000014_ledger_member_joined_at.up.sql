@@ -0,0 +1,5 @@
+-- migration/000014_ledger_member_joined_at.up
+
+-- Час вступу до журналу: при видаленні акаунта власника журнал переходить до учасника,
+-- який приєднався найраніше (наявні учасники отримують однаковий час міграції)
+ALTER TABLE ledger_members ADD COLUMN joined_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6);
@@ -0,0 +1,11 @@
+-- migration/000015_login_failures.down
+
+ALTER TABLE users
+    ADD COLUMN failed_logins INT NOT NULL DEFAULT 0,
+    ADD COLUMN locked_until DATETIME NULL;
+
+UPDATE users u JOIN login_failures f ON f.username = u.username
+SET u.failed_logins = f.failed_logins, u.locked_until = f.locked_until
+WHERE u.deleted_at IS NULL;
+
+DROP TABLE login_failures;
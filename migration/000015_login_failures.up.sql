@@ -0,0 +1,17 @@
+-- migration/000015_login_failures.up
+
+-- Лічильник невдалих входів і блокування ведуться за ім'ям, а не за записом users: так само
+-- рахуються і спроби з неіснуючими іменами, і відповідь на вхід не видає, чи існує користувач
+CREATE TABLE login_failures (
+    username VARCHAR(255) NOT NULL PRIMARY KEY,
+    failed_logins INT NOT NULL DEFAULT 0,
+    locked_until DATETIME NULL
+);
+
+INSERT INTO login_failures (username, failed_logins, locked_until)
+SELECT username, failed_logins, locked_until FROM users
+WHERE (failed_logins > 0 OR locked_until IS NOT NULL) AND deleted_at IS NULL;
+
+ALTER TABLE users
+    DROP COLUMN locked_until,
+    DROP COLUMN failed_logins;
@@ -0,0 +1,36 @@
+// Package migration вбудовує SQL-міграції в бінарник, щоб сервер знав, якої версії схеми він очікує
+package migration
+
+import (
+	"embed"
+	"path"
+	"strconv"
+	"strings"
+)
+
+//go:embed *.sql
+var Files embed.FS
+
+// Latest повертає номер останньої міграції (префікс імені файлу 000007_budgets.up.sql)
+func Latest() int {
+	entries, err := Files.ReadDir(".")
+	if err != nil {
+		return 0
+	}
+
+	latest := 0
+	for _, entry := range entries {
+		name := path.Base(entry.Name())
+		prefix, _, ok := strings.Cut(name, "_")
+		if !ok || !strings.HasSuffix(name, ".up.sql") {
+			continue
+		}
+
+		version, err := strconv.Atoi(prefix)
+		if err == nil && version > latest {
+			latest = version
+		}
+	}
+
+	return latest
+}
@@ -0,0 +1,24 @@
+package migration
+
+import "testing"
+
+func TestLatest(t *testing.T) {
+	// Кожна міграція має пару up/down, а остання версія відповідає найбільшому номеру
+	if latest := Latest(); latest < 7 {
+		t.Errorf("Received incorrect latest migration: received %v, expected at least %v", latest, 7)
+	}
+
+	ups, _ := Files.ReadDir(".")
+	count := map[string]int{}
+	for _, entry := range ups {
+		name := entry.Name()
+		if len(name) > 7 {
+			count[name[:6]]++
+		}
+	}
+	for version, n := range count {
+		if n != 2 {
+			t.Errorf("Migration %v must have up and down files, found %d", version, n)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package models
+
+import "time"
+
+// AccessTokenPrefix відрізняє персональний токен доступу від JWT (і допомагає сканерам секретів знайти його в коді)
+const AccessTokenPrefix = "fintrack_pat_"
+
+// Scopes персональних токенів: read - перегляд, write - створення, зміна та видалення
+const (
+	ScopeExpensesRead  = "expenses:read"
+	ScopeExpensesWrite = "expenses:write"
+	ScopeAccountsRead  = "accounts:read"
+	ScopeAccountsWrite = "accounts:write"
+	ScopeBudgetsRead   = "budgets:read"
+	ScopeBudgetsWrite  = "budgets:write"
+	ScopeLedgersRead   = "ledgers:read"
+	ScopeLedgersWrite  = "ledgers:write"
+	ScopeReportsRead   = "reports:read"
+)
+
+// Scopes - усі scopes, які можна видати токену
+var Scopes = []string{
+	ScopeExpensesRead, ScopeExpensesWrite,
+	ScopeAccountsRead, ScopeAccountsWrite,
+	ScopeBudgetsRead, ScopeBudgetsWrite,
+	ScopeLedgersRead, ScopeLedgersWrite,
+	ScopeReportsRead,
+}
+
+// AccessToken - персональний токен доступу; сам токен показується лише при створенні
+type AccessToken struct {
+	ID         int        `json:"id"`
+	UserID     int        `json:"-"`
+	Name       string     `json:"name"`
+	Scopes     []string   `json:"scopes"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"` // nil - безстроковий
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+}
+
+// HasScope повідомляє, чи дозволяє токен операцію
+func (t AccessToken) HasScope(scope string) bool {
+	for _, s := range t.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// AccessTokenRequest - тіло POST /me/tokens
+type AccessTokenRequest struct {
+	Name      string     `json:"name"`
+	Scopes    []string   `json:"scopes"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// CreatedAccessToken - відповідь POST /me/tokens: опис токена і сам токен
+type CreatedAccessToken struct {
+	AccessToken
+	Token string `json:"token"`
+}
@@ -0,0 +1,54 @@
+package models
+
+import "time"
+
+// Типи рахунків користувача
+const (
+	AccountCash    = "cash"
+	AccountCard    = "card"
+	AccountBank    = "bank"
+	AccountSavings = "savings"
+)
+
+// Типи записів: витрата зменшує баланс рахунку, дохід - збільшує
+const (
+	KindExpense = "expense"
+	KindIncome  = "income"
+)
+
+// Типи рухів коштів в історії рахунку
+const (
+	EntryExpense     = "expense"
+	EntryIncome      = "income"
+	EntryTransferIn  = "transfer_in"
+	EntryTransferOut = "transfer_out"
+)
+
+type Account struct {
+	ID             int    `json:"id"`
+	UserID         int    `json:"user_id"`
+	Name           string `json:"name"`
+	Type           string `json:"type"`
+	InitialBalance int    `json:"initial_balance"`
+	Balance        int    `json:"balance"` // обчислюється з історії рахунку
+}
+
+// Transfer - переказ між рахунками одного користувача, що не вважається витратою
+type Transfer struct {
+	ID            int       `json:"id"`
+	UserID        int       `json:"user_id"`
+	FromAccountID int       `json:"from_account_id"`
+	ToAccountID   int       `json:"to_account_id"`
+	Amount        int       `json:"amount"`
+	Date          time.Time `json:"date"`
+}
+
+// AccountEntry - рух коштів по рахунку; Amount додатний для надходжень і від'ємний для списань
+type AccountEntry struct {
+	Date        time.Time `json:"date"`
+	Kind        string    `json:"kind"`
+	ReferenceID int       `json:"reference_id"` // id витрати або переказу
+	Category    string    `json:"category,omitempty"`
+	Amount      int       `json:"amount"`
+	Balance     int       `json:"balance"` // баланс після цього руху
+}
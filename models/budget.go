@@ -0,0 +1,18 @@
+package models
+
+// Budget - місячний ліміт витрат користувача на категорію
+type Budget struct {
+	ID       int    `json:"id"`
+	UserID   int    `json:"user_id"`
+	Category string `json:"category"`
+	Amount   int    `json:"amount"`
+}
+
+// BudgetProgress показує, яку частину бюджету категорії витрачено за місяць
+type BudgetProgress struct {
+	Category  string  `json:"category"`
+	Budget    int     `json:"budget"`
+	Spent     int     `json:"spent"`
+	Remaining int     `json:"remaining"` // від'ємний, якщо бюджет перевищено
+	Percent   float64 `json:"percent"`
+}
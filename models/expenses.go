@@ -1,14 +1,96 @@
 package models
 
 import (
+	"encoding/json"
 	"time"
 )
 
 type Expense struct {
 	ID       int       `json:"id"`
 	Date     time.Time `json:"date"`
-	RawDate  string    `json:"rawdate"`
+	DateOnly bool      `json:"-"` // клієнт передав лише дату без часу, її відносять до часового поясу користувача
 	Category string    `json:"category"`
 	Amount   int       `json:"amount"`
 	UserID   int       `json:"user_id"`
+	LedgerID int       `json:"ledger_id,omitempty"` // 0 - особисті витрати користувача
+
+	// Рахунок, з якого сплачено витрату (або на який надійшов дохід)
+	AccountID int    `json:"account_id"`
+	Kind      string `json:"kind"` // expense або income
+
+	// Розподіл спільної витрати (лише для витрат у журналі)
+	PaidBy      int            `json:"paid_by,omitempty"`
+	SplitMethod string         `json:"split_method,omitempty"`
+	Splits      []ExpenseSplit `json:"splits,omitempty"`
+}
+
+// Формати дати, які приймає API: повна мітка часу RFC 3339 або лише дата
+const (
+	DateTimeLayout = time.RFC3339
+	DateLayout     = "2006-01-02"
+)
+
+// DateFormatError повертається при розборі JSON, якщо дата не відповідає жодному з форматів
+type DateFormatError struct {
+	Value string
+}
+
+func (e *DateFormatError) Error() string {
+	return "date must be in RFC 3339 or YYYY-MM-DD format, got " + e.Value
+}
+
+// ParseDate розбирає дату у форматі RFC 3339 або YYYY-MM-DD; порожній рядок дає нульовий час
+func ParseDate(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	if date, err := time.Parse(DateTimeLayout, value); err == nil {
+		return date, nil
+	}
+
+	date, err := time.Parse(DateLayout, value)
+	if err != nil {
+		return time.Time{}, &DateFormatError{Value: value}
+	}
+
+	return date, nil
+}
+
+// UnmarshalJSON приймає дату в будь-якому з форматів ParseDate; відсутня дата залишається нульовою
+func (e *Expense) UnmarshalJSON(data []byte) error {
+	type expenseAlias Expense
+	aux := struct {
+		*expenseAlias
+		Date *string `json:"date"`
+	}{expenseAlias: (*expenseAlias)(e)}
+
+	err := json.Unmarshal(data, &aux)
+	if err != nil {
+		return err
+	}
+
+	if aux.Date != nil {
+		e.Date, err = ParseDate(*aux.Date)
+		if err != nil {
+			return err
+		}
+		e.DateOnly = len(*aux.Date) == len(DateLayout)
+	}
+
+	return nil
+}
+
+// MarshalJSON передає дату без часу так само, як її було отримано, щоб сервер відніс її до дня
+// в часовому поясі користувача; інші дати кодуються як мітки часу RFC 3339
+func (e Expense) MarshalJSON() ([]byte, error) {
+	type expenseAlias Expense
+	if !e.DateOnly {
+		return json.Marshal(expenseAlias(e))
+	}
+
+	return json.Marshal(struct {
+		expenseAlias
+		Date string `json:"date"`
+	}{expenseAlias(e), e.Date.Format(DateLayout)})
 }
@@ -0,0 +1,74 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestExpense_UnmarshalJSON_Date(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		expected time.Time
+	}{
+		{"rfc 3339", `{"amount": 5, "date": "2024-03-10T08:30:00+02:00"}`, time.Date(2024, 3, 10, 6, 30, 0, 0, time.UTC)},
+		{"plain date", `{"amount": 5, "date": "2024-03-10"}`, time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)},
+		{"omitted", `{"amount": 5}`, time.Time{}},
+		{"empty", `{"amount": 5, "date": ""}`, time.Time{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			var expense Expense
+			err := json.Unmarshal([]byte(tt.body), &expense)
+
+			// Assert
+			if err != nil {
+				t.Fatalf("Received an error: received %v, expected %v", err, nil)
+			}
+
+			if !expense.Date.Equal(tt.expected) || expense.Amount != 5 {
+				t.Errorf("Received incorrect expense: received %v, expected date %v", expense, tt.expected)
+			}
+		})
+	}
+}
+
+func TestExpense_UnmarshalJSON_InvalidDate(t *testing.T) {
+	// Act
+	var expense Expense
+	err := json.Unmarshal([]byte(`{"date": "10.03.2024"}`), &expense)
+
+	// Assert
+	var dateErr *DateFormatError
+	if !errors.As(err, &dateErr) {
+		t.Errorf("Received incorrect error: received %v, expected %T", err, dateErr)
+	}
+}
+
+func TestExpense_MarshalJSON_RoundTrip(t *testing.T) {
+	for _, body := range []string{`{"amount": 5, "date": "2024-03-10"}`, `{"amount": 5, "date": "2024-03-10T08:30:00Z"}`} {
+		// Arrange
+		var expense Expense
+		err := json.Unmarshal([]byte(body), &expense)
+		if err != nil {
+			t.Fatalf("Received an error: received %v, expected %v", err, nil)
+		}
+
+		// Act
+		data, err := json.Marshal(expense)
+		if err != nil {
+			t.Fatalf("Received an error: received %v, expected %v", err, nil)
+		}
+		var decoded Expense
+		err = json.Unmarshal(data, &decoded)
+
+		// Assert
+		if err != nil || !decoded.Date.Equal(expense.Date) || decoded.DateOnly != expense.DateOnly {
+			t.Errorf("Received incorrect round trip for %s: %s", body, data)
+		}
+	}
+}
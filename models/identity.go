@@ -0,0 +1,11 @@
+package models
+
+// OIDCIdentity - перевірені дані з ID-токена постачальника OpenID Connect
+type OIDCIdentity struct {
+	Issuer            string
+	Subject           string // незмінний ідентифікатор користувача в постачальника (claim "sub")
+	Email             string
+	EmailVerified     bool
+	PreferredUsername string
+	Name              string
+}
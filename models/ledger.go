@@ -0,0 +1,22 @@
+package models
+
+// Ролі учасників спільного журналу витрат
+const (
+	RoleOwner  = "owner"
+	RoleEditor = "editor"
+	RoleViewer = "viewer"
+)
+
+type Ledger struct {
+	ID      int    `json:"id"`
+	Name    string `json:"name"`
+	OwnerID int    `json:"owner_id"`
+	Role    string `json:"role,omitempty"` // роль поточного користувача в журналі
+}
+
+type LedgerMember struct {
+	LedgerID int    `json:"ledger_id"`
+	UserID   int    `json:"user_id"`
+	Username string `json:"username"`
+	Role     string `json:"role"`
+}
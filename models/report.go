@@ -0,0 +1,67 @@
+package models
+
+import "time"
+
+// DateRange - напіввідкритий проміжок часу [Start, End)
+type DateRange struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+type TimeSeriesBucket struct {
+	Start         time.Time `json:"start"`
+	End           time.Time `json:"end"`
+	Total         int       `json:"total"`
+	Change        int       `json:"change"`               // різниця з попереднім інтервалом
+	ChangePct     *float64  `json:"change_pct,omitempty"` // відсутня, якщо попередній інтервал нульовий
+	MovingAverage float64   `json:"moving_average"`
+}
+
+// PeriodComparison порівнює витрати за поточний і попередній період
+type PeriodComparison struct {
+	Current  int      `json:"current"`
+	Previous int      `json:"previous"`
+	Delta    int      `json:"delta"`
+	DeltaPct *float64 `json:"delta_pct,omitempty"`
+}
+
+type TimeSeries struct {
+	Interval       string             `json:"interval"`
+	Category       string             `json:"category,omitempty"`
+	TimeZone       string             `json:"time_zone"`
+	From           time.Time          `json:"from"`
+	To             time.Time          `json:"to"`
+	Window         int                `json:"window"` // кількість інтервалів у ковзному середньому
+	Total          int                `json:"total"`
+	Buckets        []TimeSeriesBucket `json:"buckets"`
+	MonthOverMonth PeriodComparison   `json:"month_over_month"`
+	YearOverYear   PeriodComparison   `json:"year_over_year"`
+}
+
+// CategoryTotal - сума витрат за категорією та її частка у відсотках від усіх витрат періоду
+type CategoryTotal struct {
+	Category string  `json:"category"`
+	Total    int     `json:"total"`
+	Share    float64 `json:"share"`
+}
+
+type CategoryReport struct {
+	TimeZone   string          `json:"time_zone"`
+	From       time.Time       `json:"from"`
+	To         time.Time       `json:"to"`
+	Total      int             `json:"total"`
+	Categories []CategoryTotal `json:"categories"`
+}
+
+// Statement - дані місячної виписки: власник, підсумки за категоріями, перелік витрат і бюджети
+type Statement struct {
+	Username   string           `json:"username"`
+	TimeZone   string           `json:"time_zone"`
+	Month      string           `json:"month"` // 2006-01
+	From       time.Time        `json:"from"`
+	To         time.Time        `json:"to"`
+	Total      int              `json:"total"`
+	Categories []CategoryTotal  `json:"categories"`
+	Expenses   []Expense        `json:"expenses"`
+	Budgets    []BudgetProgress `json:"budgets"`
+}
@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// Способи розподілу спільної витрати між учасниками журналу
+const (
+	SplitEqual      = "equal"
+	SplitExact      = "exact"
+	SplitPercentage = "percentage"
+	SplitShares     = "shares"
+)
+
+// ExpenseSplit - частка учасника у спільній витраті. Value задає клієнт (сума, відсоток
+// або кількість часток залежно від способу розподілу), Amount обчислюється сервісом
+type ExpenseSplit struct {
+	UserID int `json:"user_id"`
+	Value  int `json:"value,omitempty"`
+	Amount int `json:"amount"`
+}
+
+// Balance - борг одного учасника іншому: FromUserID винен ToUserID суму Amount
+type Balance struct {
+	FromUserID int `json:"from_user_id"`
+	ToUserID   int `json:"to_user_id"`
+	Amount     int `json:"amount"`
+}
+
+// Settlement - зафіксований переказ між учасниками журналу, що погашає борг
+type Settlement struct {
+	ID         int       `json:"id"`
+	LedgerID   int       `json:"ledger_id"`
+	FromUserID int       `json:"from_user_id"`
+	ToUserID   int       `json:"to_user_id"`
+	Amount     int       `json:"amount"`
+	Date       time.Time `json:"date"`
+}
@@ -0,0 +1,37 @@
+package models
+
+// TOTPState - налаштування двофакторної автентифікації користувача
+type TOTPState struct {
+	Secret   string // порожній - 2FA не налаштовано
+	Enabled  bool   // секрет підтверджено першим кодом, вхід вимагає код
+	LastStep int64  // останній використаний 30-секундний інтервал
+}
+
+// TOTPSetup - відповідь на POST /me/2fa/setup: секрет для ручного введення, otpauth URI і той самий URI як QR-код
+type TOTPSetup struct {
+	Secret string `json:"secret"`
+	URI    string `json:"otpauth_uri"`
+	QRCode string `json:"qr_code"` // data:image/png;base64,...
+}
+
+// TwoFactorCode - код з автентифікатора (6 цифр) або код відновлення
+type TwoFactorCode struct {
+	Code string `json:"code"`
+}
+
+// RecoveryCodes показуються користувачу лише один раз, після ввімкнення 2FA
+type RecoveryCodes struct {
+	Codes []string `json:"recovery_codes"`
+}
+
+// TwoFactorChallenge - відповідь POST /login для користувача з 2FA замість JWT
+type TwoFactorChallenge struct {
+	Required       bool   `json:"two_factor_required"`
+	ChallengeToken string `json:"challenge_token"`
+}
+
+// TwoFactorLogin - другий крок входу: POST /login/2fa
+type TwoFactorLogin struct {
+	ChallengeToken string `json:"challenge_token"`
+	Code           string `json:"code"`
+}
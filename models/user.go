@@ -1,7 +1,47 @@
 package models
 
+import "time"
+
 type User struct {
-	ID       int    `json:"id"`
-	Username string `json:"username"`
-	Password string `json:"password"`
+	ID           int    `json:"id"`
+	Username     string `json:"username"`
+	Password     string `json:"password,omitempty"` // лише у запитах, у відповідях не повертається
+	Email        string `json:"email,omitempty"`    // необов'язкова, потрібна для відновлення пароля
+	TimeZone     string `json:"time_zone"`          // назва з бази IANA, наприклад Europe/Kyiv
+	TokenVersion int    `json:"-"`                  // збільшується при зміні пароля, щоб відкликати видані токени
+
+	TwoFactorEnabled bool `json:"two_factor_enabled"` // вхід вимагає код TOTP після пароля
+}
+
+// ProfileUpdate - зміни профілю в PATCH /me; nil означає, що поле не змінюється
+type ProfileUpdate struct {
+	Username *string `json:"username"`
+	Email    *string `json:"email"` // порожній рядок видаляє адресу
+	TimeZone *string `json:"time_zone"`
+}
+
+// PasswordChange - тіло POST /me/password
+type PasswordChange struct {
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password"`
+}
+
+// LoginState - стан захисту від підбору пароля: невдалі спроби входу поспіль і блокування
+type LoginState struct {
+	FailedLogins int
+	LockedUntil  time.Time // нульовий час - вхід не заблоковано
+}
+
+// PasswordResetToken - виданий токен відновлення пароля; сам токен не зберігається, лише його хеш
+type PasswordResetToken struct {
+	ID        int
+	UserID    int
+	ExpiresAt time.Time
+	Used      bool
+}
+
+// PasswordReset - тіло POST /password/reset
+type PasswordReset struct {
+	Token       string `json:"token"`
+	NewPassword string `json:"new_password"`
 }
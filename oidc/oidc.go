@@ -0,0 +1,129 @@
+// Package oidc реалізує вхід через зовнішнього постачальника OpenID Connect: authorization code flow
+// з PKCE (RFC 7636) і перевіркою ID-токена за ключами постачальника
+package oidc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	gooidc "github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+
+	"github.com/ChomuCake/uni-golang-labs/models"
+)
+
+// ErrNonceMismatch - ID-токен видано не для цієї спроби входу (можливе повторне використання токена)
+var ErrNonceMismatch = errors.New("oidc: id token nonce mismatch")
+
+type Config struct {
+	Issuer       string
+	ClientID     string
+	ClientSecret string // порожній - публічний клієнт, якого захищає лише PKCE
+	RedirectURL  string // адреса GET /oidc/callback, зареєстрована в постачальника
+	Scopes       []string
+}
+
+// Provider підключається до постачальника ліниво, під час першого входу, і кешує його налаштування
+// (discovery-документ і ключі), тож недоступність постачальника не заважає запуску сервера
+type Provider struct {
+	cfg    Config
+	client *http.Client
+
+	mu       sync.Mutex
+	oauth    *oauth2.Config
+	verifier *gooidc.IDTokenVerifier
+}
+
+func New(cfg Config) *Provider {
+	if len(cfg.Scopes) == 0 {
+		cfg.Scopes = []string{"profile", "email"}
+	}
+	return &Provider{cfg: cfg, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// discover завантажує discovery-документ постачальника; невдала спроба не кешується
+func (p *Provider) discover(ctx context.Context) (*oauth2.Config, *gooidc.IDTokenVerifier, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.oauth != nil {
+		return p.oauth, p.verifier, nil
+	}
+
+	provider, err := gooidc.NewProvider(gooidc.ClientContext(ctx, p.client), p.cfg.Issuer)
+	if err != nil {
+		return nil, nil, fmt.Errorf("oidc: discovery: %w", err)
+	}
+
+	p.oauth = &oauth2.Config{
+		ClientID:     p.cfg.ClientID,
+		ClientSecret: p.cfg.ClientSecret,
+		RedirectURL:  p.cfg.RedirectURL,
+		Endpoint:     provider.Endpoint(),
+		Scopes:       append([]string{gooidc.ScopeOpenID}, p.cfg.Scopes...),
+	}
+	p.verifier = provider.Verifier(&gooidc.Config{ClientID: p.cfg.ClientID})
+
+	return p.oauth, p.verifier, nil
+}
+
+// AuthCodeURL повертає адресу сторінки входу постачальника; state, nonce і verifier (PKCE)
+// клієнт має зберегти до повернення на callback
+func (p *Provider) AuthCodeURL(ctx context.Context, state, nonce, verifier string) (string, error) {
+	conf, _, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return conf.AuthCodeURL(state, gooidc.Nonce(nonce), oauth2.S256ChallengeOption(verifier)), nil
+}
+
+// Exchange обмінює код авторизації на токени і повертає дані користувача з перевіреного ID-токена
+func (p *Provider) Exchange(ctx context.Context, code, nonce, verifier string) (models.OIDCIdentity, error) {
+	conf, idVerifier, err := p.discover(ctx)
+	if err != nil {
+		return models.OIDCIdentity{}, err
+	}
+
+	token, err := conf.Exchange(context.WithValue(ctx, oauth2.HTTPClient, p.client), code, oauth2.VerifierOption(verifier))
+	if err != nil {
+		return models.OIDCIdentity{}, fmt.Errorf("oidc: exchange: %w", err)
+	}
+
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		return models.OIDCIdentity{}, errors.New("oidc: token response has no id_token")
+	}
+
+	idToken, err := idVerifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		return models.OIDCIdentity{}, fmt.Errorf("oidc: verify id token: %w", err)
+	}
+	if idToken.Nonce != nonce {
+		return models.OIDCIdentity{}, ErrNonceMismatch
+	}
+
+	var claims struct {
+		Email             string `json:"email"`
+		EmailVerified     bool   `json:"email_verified"`
+		PreferredUsername string `json:"preferred_username"`
+		Name              string `json:"name"`
+	}
+	err = idToken.Claims(&claims)
+	if err != nil {
+		return models.OIDCIdentity{}, fmt.Errorf("oidc: id token claims: %w", err)
+	}
+
+	return models.OIDCIdentity{
+		Issuer:            idToken.Issuer,
+		Subject:           idToken.Subject,
+		Email:             claims.Email,
+		EmailVerified:     claims.EmailVerified,
+		PreferredUsername: claims.PreferredUsername,
+		Name:              claims.Name,
+	}, nil
+}
@@ -0,0 +1,195 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+)
+
+// mockProvider - мінімальний постачальник OpenID Connect: discovery, ключі, видача коду
+// (без сторінки входу: код видається одразу) і обмін коду з перевіркою PKCE
+type mockProvider struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+	// code -> параметри запиту авторизації, з якими його видано
+	codes map[string]url.Values
+	// claims, які потраплять в ID-токен, крім iss, aud, exp, iat і nonce
+	claims map[string]interface{}
+}
+
+func newMockProvider(t *testing.T) *mockProvider {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Received an error: received %v, expected %v", err, nil)
+	}
+
+	p := &mockProvider{key: key, codes: map[string]url.Values{}, claims: map[string]interface{}{"sub": "ext-42"}}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"issuer":                                p.server.URL,
+			"authorization_endpoint":                p.server.URL + "/authorize",
+			"token_endpoint":                        p.server.URL + "/token",
+			"jwks_uri":                              p.server.URL + "/keys",
+			"id_token_signing_alg_values_supported": []string{"RS256"},
+		})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(jose.JSONWebKeySet{Keys: []jose.JSONWebKey{
+			{Key: &p.key.PublicKey, KeyID: "test", Algorithm: "RS256", Use: "sig"},
+		}})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		auth, ok := p.codes[r.PostForm.Get("code")]
+		delete(p.codes, r.PostForm.Get("code"))
+
+		sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+		if !ok || base64.RawURLEncoding.EncodeToString(sum[:]) != auth.Get("code_challenge") {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":"invalid_grant"}`))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token": "access-1",
+			"token_type":   "Bearer",
+			"expires_in":   3600,
+			"id_token":     p.idToken(t, auth.Get("client_id"), auth.Get("nonce")),
+		})
+	})
+	p.server = httptest.NewServer(mux)
+	return p
+}
+
+func (p *mockProvider) idToken(t *testing.T, audience, nonce string) string {
+	claims := map[string]interface{}{
+		"iss":   p.server.URL,
+		"aud":   audience,
+		"iat":   time.Now().Unix(),
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"nonce": nonce,
+	}
+	for name, value := range p.claims {
+		claims[name] = value
+	}
+	payload, _ := json.Marshal(claims)
+
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.RS256, Key: p.key}, (&jose.SignerOptions{}).WithType("JWT").WithHeader("kid", "test"))
+	if err != nil {
+		t.Fatalf("Received an error: received %v, expected %v", err, nil)
+	}
+	signed, err := signer.Sign(payload)
+	if err != nil {
+		t.Fatalf("Received an error: received %v, expected %v", err, nil)
+	}
+	token, _ := signed.CompactSerialize()
+	return token
+}
+
+// authorize імітує вхід користувача на сторінці постачальника і повертає виданий код
+func (p *mockProvider) authorize(t *testing.T, authURL string) string {
+	u, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatalf("Received an error: received %v, expected %v", err, nil)
+	}
+
+	code := "code-" + u.Query().Get("state")
+	p.codes[code] = u.Query()
+	return code
+}
+
+func TestProvider_AuthorizationCodeFlow(t *testing.T) {
+	// Arrange
+	mock := newMockProvider(t)
+	defer mock.server.Close()
+	mock.claims["email"] = "alice@example.com"
+	mock.claims["email_verified"] = true
+	mock.claims["preferred_username"] = "alice"
+	p := New(Config{Issuer: mock.server.URL, ClientID: "fintrack", RedirectURL: "http://localhost:8080/oidc/callback"})
+
+	// Act
+	authURL, err := p.AuthCodeURL(context.Background(), "state-1", "nonce-1", "verifier-verifier-verifier-verifier-verifier")
+	if err != nil {
+		t.Fatalf("Received an error: received %v, expected %v", err, nil)
+	}
+	code := mock.authorize(t, authURL)
+	identity, err := p.Exchange(context.Background(), code, "nonce-1", "verifier-verifier-verifier-verifier-verifier")
+
+	// Assert
+	u, _ := url.Parse(authURL)
+	if u.Query().Get("code_challenge_method") != "S256" || u.Query().Get("redirect_uri") != "http://localhost:8080/oidc/callback" ||
+		u.Query().Get("scope") != "openid profile email" {
+		t.Errorf("Received incorrect authorization URL: %s", authURL)
+	}
+	if err != nil {
+		t.Fatalf("Received an error: received %v, expected %v", err, nil)
+	}
+	if identity.Issuer != mock.server.URL || identity.Subject != "ext-42" || identity.Email != "alice@example.com" ||
+		!identity.EmailVerified || identity.PreferredUsername != "alice" {
+		t.Errorf("Received incorrect identity: %+v", identity)
+	}
+}
+
+func TestProvider_Exchange_RejectsWrongVerifier(t *testing.T) {
+	// Arrange: перехоплений код без verifier з цієї спроби входу непридатний
+	mock := newMockProvider(t)
+	defer mock.server.Close()
+	p := New(Config{Issuer: mock.server.URL, ClientID: "fintrack"})
+	authURL, _ := p.AuthCodeURL(context.Background(), "state-1", "nonce-1", "verifier-verifier-verifier-verifier-verifier")
+	code := mock.authorize(t, authURL)
+
+	// Act
+	_, err := p.Exchange(context.Background(), code, "nonce-1", "another-verifier-another-verifier-another")
+
+	// Assert
+	if err == nil {
+		t.Errorf("Received incorrect result: received %v, expected an error", err)
+	}
+}
+
+func TestProvider_Exchange_RejectsWrongNonce(t *testing.T) {
+	// Arrange
+	mock := newMockProvider(t)
+	defer mock.server.Close()
+	p := New(Config{Issuer: mock.server.URL, ClientID: "fintrack"})
+	authURL, _ := p.AuthCodeURL(context.Background(), "state-1", "nonce-1", "verifier-verifier-verifier-verifier-verifier")
+	code := mock.authorize(t, authURL)
+
+	// Act
+	_, err := p.Exchange(context.Background(), code, "nonce-2", "verifier-verifier-verifier-verifier-verifier")
+
+	// Assert
+	if !errors.Is(err, ErrNonceMismatch) {
+		t.Errorf("Received incorrect error: received %v, expected %v", err, ErrNonceMismatch)
+	}
+}
+
+func TestProvider_DiscoveryFailureIsRetried(t *testing.T) {
+	// Arrange: постачальник недоступний під час першої спроби
+	mock := newMockProvider(t)
+	issuer := mock.server.URL
+	mock.server.Close()
+	p := New(Config{Issuer: issuer, ClientID: "fintrack"})
+
+	// Act
+	_, err := p.AuthCodeURL(context.Background(), "state-1", "nonce-1", "verifier")
+
+	// Assert
+	if err == nil || p.oauth != nil {
+		t.Errorf("Received incorrect result: received %v, expected an uncached discovery error", err)
+	}
+}
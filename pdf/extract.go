@@ -0,0 +1,72 @@
+package pdf
+
+import (
+	"encoding/hex"
+	"regexp"
+	"unicode/utf16"
+)
+
+var (
+	fontResourcePattern = regexp.MustCompile(`/(F\d+) (\d+) 0 R`)
+	toUnicodePattern    = regexp.MustCompile(`/Subtype /Type0 .*/ToUnicode (\d+) 0 R`)
+	bfcharPattern       = regexp.MustCompile(`<([0-9A-F]{4})> <([0-9A-F]+)>`)
+	textPattern         = regexp.MustCompile(`BT /(F\d+) [\d.]+ Tf -?[\d.]+ -?[\d.]+ Td <([0-9A-F]*)> Tj ET`)
+)
+
+// ExtractText повертає рядки, виведені в документі цього пакета, у порядку виведення: номери
+// гліфів перетворюються на символи через ToUnicode CMap шрифтів, як це робить переглядач
+// при копіюванні тексту. Призначена для перевірки згенерованих документів
+func ExtractText(document []byte) []string {
+	cmaps := map[string]map[string]string{}
+	for _, resource := range fontResourcePattern.FindAllSubmatch(document, -1) {
+		font := string(resource[1])
+		if _, ok := cmaps[font]; ok {
+			continue
+		}
+
+		fontObject := objectBody(document, string(resource[2]))
+		toUnicode := toUnicodePattern.FindSubmatch(fontObject)
+		if toUnicode == nil {
+			continue
+		}
+
+		cmap := map[string]string{}
+		for _, pair := range bfcharPattern.FindAllSubmatch(objectBody(document, string(toUnicode[1])), -1) {
+			cmap[string(pair[1])] = decodeUTF16Hex(string(pair[2]))
+		}
+		cmaps[font] = cmap
+	}
+
+	var texts []string
+	for _, text := range textPattern.FindAllSubmatch(document, -1) {
+		cmap := cmaps[string(text[1])]
+		decoded := ""
+		for code := text[2]; len(code) >= 4; code = code[4:] {
+			decoded += cmap[string(code[:4])]
+		}
+		texts = append(texts, decoded)
+	}
+	return texts
+}
+
+// objectBody повертає вміст непрямого об'єкта number
+func objectBody(document []byte, number string) []byte {
+	pattern := regexp.MustCompile(`(?s)\n` + regexp.QuoteMeta(number) + ` 0 obj\n(.*?)\nendobj\n`)
+	if body := pattern.FindSubmatch(document); body != nil {
+		return body[1]
+	}
+	return nil
+}
+
+func decodeUTF16Hex(text string) string {
+	data, err := hex.DecodeString(text)
+	if err != nil {
+		return ""
+	}
+
+	units := make([]uint16, len(data)/2)
+	for i := range units {
+		units[i] = uint16(data[2*i])<<8 | uint16(data[2*i+1])
+	}
+	return string(utf16.Decode(units))
+}
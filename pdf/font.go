@@ -0,0 +1,450 @@
+package pdf
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Шрифти DejaVu Sans (ліцензія в fonts/LICENSE) покривають латиницю, кирилицю та інші поширені
+// письмена; у документ вбудовується лише підмножина гліфів, використаних у тексті
+var (
+	//go:embed fonts/DejaVuSans.ttf
+	regularTTF []byte
+	//go:embed fonts/DejaVuSans-Bold.ttf
+	boldTTF []byte
+)
+
+var (
+	fontsOnce sync.Once
+	fonts     map[Font]*trueType
+)
+
+// fontFor розбирає вбудовані шрифти при першому зверненні: пакет імпортує і сервер,
+// якому PDF може так і не знадобитися
+func fontFor(font Font) *trueType {
+	fontsOnce.Do(func() {
+		fonts = map[Font]*trueType{
+			Regular: mustParseTrueType("DejaVuSans", regularTTF),
+			Bold:    mustParseTrueType("DejaVuSans-Bold", boldTTF),
+		}
+	})
+
+	tt, ok := fonts[font]
+	if !ok {
+		panic(fmt.Sprintf("pdf: unknown font %q", font))
+	}
+	return tt
+}
+
+// trueType - розібраний шрифт TrueType: таблиці, ширини гліфів і відображення символів у гліфи
+type trueType struct {
+	name       string
+	tables     map[string][]byte
+	unitsPerEm int
+	numGlyphs  int
+	advances   []int
+	loca       []int
+	cmap       map[rune]uint16
+
+	// Метрики для FontDescriptor в одиницях шрифту
+	bbox      [4]int
+	ascent    int
+	descent   int
+	capHeight int
+}
+
+func mustParseTrueType(name string, data []byte) *trueType {
+	tt, err := parseTrueType(name, data)
+	if err != nil {
+		panic(fmt.Sprintf("pdf: embedded font %s: %v", name, err))
+	}
+	return tt
+}
+
+var errFontTruncated = errors.New("font data is truncated")
+
+func parseTrueType(name string, data []byte) (*trueType, error) {
+	if len(data) < 12 {
+		return nil, errFontTruncated
+	}
+
+	tt := &trueType{name: name, tables: map[string][]byte{}}
+	numTables := int(binary.BigEndian.Uint16(data[4:]))
+	for i := 0; i < numTables; i++ {
+		record := 12 + 16*i
+		if record+16 > len(data) {
+			return nil, errFontTruncated
+		}
+		offset := int(binary.BigEndian.Uint32(data[record+8:]))
+		length := int(binary.BigEndian.Uint32(data[record+12:]))
+		if offset+length > len(data) {
+			return nil, errFontTruncated
+		}
+		tt.tables[string(data[record:record+4])] = data[offset : offset+length]
+	}
+
+	for _, tag := range []string{"head", "hhea", "hmtx", "maxp", "loca", "glyf", "cmap"} {
+		if _, ok := tt.tables[tag]; !ok {
+			return nil, fmt.Errorf("missing %s table", tag)
+		}
+	}
+
+	head := tt.tables["head"]
+	hhea := tt.tables["hhea"]
+	maxp := tt.tables["maxp"]
+	if len(head) < 54 || len(hhea) < 36 || len(maxp) < 6 {
+		return nil, errFontTruncated
+	}
+	tt.unitsPerEm = int(binary.BigEndian.Uint16(head[18:]))
+	for i := range tt.bbox {
+		tt.bbox[i] = int(int16(binary.BigEndian.Uint16(head[36+2*i:])))
+	}
+	tt.ascent = int(int16(binary.BigEndian.Uint16(hhea[4:])))
+	tt.descent = int(int16(binary.BigEndian.Uint16(hhea[6:])))
+	tt.numGlyphs = int(binary.BigEndian.Uint16(maxp[4:]))
+
+	err := tt.parseMetrics(int(binary.BigEndian.Uint16(hhea[34:])))
+	if err != nil {
+		return nil, err
+	}
+	err = tt.parseLoca(int16(binary.BigEndian.Uint16(head[50:])) == 1)
+	if err != nil {
+		return nil, err
+	}
+	err = tt.parseCmap()
+	if err != nil {
+		return nil, err
+	}
+
+	// Висота великих літер - верх гліфа "H" (таблиця OS/2 першої версії її не містить)
+	tt.capHeight = tt.ascent
+	if glyph := tt.glyph(tt.cmap['H']); len(glyph) >= 10 {
+		tt.capHeight = int(int16(binary.BigEndian.Uint16(glyph[8:])))
+	}
+
+	return tt, nil
+}
+
+// parseMetrics читає ширини гліфів: після numberOfHMetrics записів ширина повторює останню
+func (tt *trueType) parseMetrics(numberOfHMetrics int) error {
+	hmtx := tt.tables["hmtx"]
+	if numberOfHMetrics == 0 || len(hmtx) < 4*numberOfHMetrics {
+		return errFontTruncated
+	}
+
+	tt.advances = make([]int, tt.numGlyphs)
+	for gid := range tt.advances {
+		metric := min(gid, numberOfHMetrics-1)
+		tt.advances[gid] = int(binary.BigEndian.Uint16(hmtx[4*metric:]))
+	}
+	return nil
+}
+
+func (tt *trueType) parseLoca(long bool) error {
+	loca := tt.tables["loca"]
+	tt.loca = make([]int, tt.numGlyphs+1)
+	for i := range tt.loca {
+		switch {
+		case long && len(loca) >= 4*i+4:
+			tt.loca[i] = int(binary.BigEndian.Uint32(loca[4*i:]))
+		case !long && len(loca) >= 2*i+2:
+			tt.loca[i] = 2 * int(binary.BigEndian.Uint16(loca[2*i:]))
+		default:
+			return errFontTruncated
+		}
+	}
+	if tt.loca[tt.numGlyphs] > len(tt.tables["glyf"]) {
+		return errFontTruncated
+	}
+	return nil
+}
+
+// parseCmap читає відображення Unicode у гліфи: формат 12 (усі площини) або формат 4 (BMP)
+func (tt *trueType) parseCmap() error {
+	cmap := tt.tables["cmap"]
+	if len(cmap) < 4 {
+		return errFontTruncated
+	}
+
+	var bmp, full []byte
+	for i := 0; i < int(binary.BigEndian.Uint16(cmap[2:])); i++ {
+		record := 4 + 8*i
+		if record+8 > len(cmap) {
+			return errFontTruncated
+		}
+		platform := binary.BigEndian.Uint16(cmap[record:])
+		encoding := binary.BigEndian.Uint16(cmap[record+2:])
+		offset := int(binary.BigEndian.Uint32(cmap[record+4:]))
+		if offset+2 > len(cmap) {
+			return errFontTruncated
+		}
+		subtable := cmap[offset:]
+
+		unicode := platform == 0 || (platform == 3 && (encoding == 1 || encoding == 10))
+		switch format := binary.BigEndian.Uint16(subtable); {
+		case unicode && format == 12:
+			full = subtable
+		case unicode && format == 4:
+			bmp = subtable
+		}
+	}
+
+	tt.cmap = map[rune]uint16{}
+	switch {
+	case full != nil:
+		return tt.parseCmap12(full)
+	case bmp != nil:
+		return tt.parseCmap4(bmp)
+	}
+	return errors.New("no Unicode cmap subtable")
+}
+
+func (tt *trueType) parseCmap4(table []byte) error {
+	if len(table) < 14 {
+		return errFontTruncated
+	}
+	segments := int(binary.BigEndian.Uint16(table[6:])) / 2
+	endCodes := 14
+	startCodes := endCodes + 2*segments + 2
+	deltas := startCodes + 2*segments
+	rangeOffsets := deltas + 2*segments
+	if rangeOffsets+2*segments > len(table) {
+		return errFontTruncated
+	}
+
+	for i := 0; i < segments; i++ {
+		end := int(binary.BigEndian.Uint16(table[endCodes+2*i:]))
+		start := int(binary.BigEndian.Uint16(table[startCodes+2*i:]))
+		delta := int(binary.BigEndian.Uint16(table[deltas+2*i:]))
+		rangeOffset := int(binary.BigEndian.Uint16(table[rangeOffsets+2*i:]))
+
+		for c := start; c <= end && c != 0xFFFF; c++ {
+			gid := (c + delta) & 0xFFFF
+			if rangeOffset != 0 {
+				// idRangeOffset відраховується від власної позиції в масиві
+				address := rangeOffsets + 2*i + rangeOffset + 2*(c-start)
+				if address+2 > len(table) {
+					return errFontTruncated
+				}
+				gid = int(binary.BigEndian.Uint16(table[address:]))
+				if gid != 0 {
+					gid = (gid + delta) & 0xFFFF
+				}
+			}
+			if gid != 0 && gid < tt.numGlyphs {
+				tt.cmap[rune(c)] = uint16(gid)
+			}
+		}
+	}
+	return nil
+}
+
+func (tt *trueType) parseCmap12(table []byte) error {
+	if len(table) < 16 {
+		return errFontTruncated
+	}
+	groups := int(binary.BigEndian.Uint32(table[12:]))
+	if 16+12*groups > len(table) {
+		return errFontTruncated
+	}
+
+	for i := 0; i < groups; i++ {
+		group := table[16+12*i:]
+		start := int(binary.BigEndian.Uint32(group))
+		end := int(binary.BigEndian.Uint32(group[4:]))
+		startGlyph := int(binary.BigEndian.Uint32(group[8:]))
+		for c := start; c <= end && c <= 0x10FFFF; c++ {
+			if gid := startGlyph + c - start; gid < tt.numGlyphs {
+				tt.cmap[rune(c)] = uint16(gid)
+			}
+		}
+	}
+	return nil
+}
+
+// glyph повертає опис гліфа з таблиці glyf (порожній для гліфів без контурів, як пробіл)
+func (tt *trueType) glyph(gid uint16) []byte {
+	if int(gid) >= tt.numGlyphs {
+		return nil
+	}
+	return tt.tables["glyf"][tt.loca[gid]:tt.loca[gid+1]]
+}
+
+// glyphIndex повертає гліф символу і символ, який він зображує: символи, яких немає у шрифті,
+// виводяться як "?"
+func (tt *trueType) glyphIndex(r rune) (uint16, rune) {
+	if gid, ok := tt.cmap[r]; ok {
+		return gid, r
+	}
+	return tt.cmap['?'], '?'
+}
+
+// width - ширина гліфа в тисячних кегля, як їх задає PDF
+func (tt *trueType) width(gid uint16) float64 {
+	return float64(tt.advances[gid]) * 1000 / float64(tt.unitsPerEm)
+}
+
+// scale переводить одиниці шрифту в тисячні кегля
+func (tt *trueType) scale(value int) int {
+	return value * 1000 / tt.unitsPerEm
+}
+
+// Прапорці складеного гліфа, що визначають довжину опису компонента
+const (
+	compositeArgWords   = 0x0001
+	compositeScale      = 0x0008
+	compositeMore       = 0x0020
+	compositeXYScale    = 0x0040
+	compositeTwoByTwo   = 0x0080
+	compositeHeaderSize = 10
+)
+
+// withComponents доповнює множину гліфів компонентами складених гліфів (наприклад, "й" = "и" + бреве)
+func (tt *trueType) withComponents(used map[uint16]bool) {
+	pending := make([]uint16, 0, len(used))
+	for gid := range used {
+		pending = append(pending, gid)
+	}
+
+	for len(pending) > 0 {
+		gid := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+
+		glyph := tt.glyph(gid)
+		if len(glyph) < compositeHeaderSize || int16(binary.BigEndian.Uint16(glyph)) >= 0 {
+			continue
+		}
+
+		for offset := compositeHeaderSize; offset+4 <= len(glyph); {
+			flags := binary.BigEndian.Uint16(glyph[offset:])
+			component := binary.BigEndian.Uint16(glyph[offset+2:])
+			if !used[component] {
+				used[component] = true
+				pending = append(pending, component)
+			}
+
+			offset += 4
+			if flags&compositeArgWords != 0 {
+				offset += 4
+			} else {
+				offset += 2
+			}
+			switch {
+			case flags&compositeScale != 0:
+				offset += 2
+			case flags&compositeXYScale != 0:
+				offset += 4
+			case flags&compositeTwoByTwo != 0:
+				offset += 8
+			}
+			if flags&compositeMore == 0 {
+				break
+			}
+		}
+	}
+}
+
+// subsetTables - таблиці, потрібні вбудованому шрифту CIDFontType2 (cmap не потрібна:
+// текст кодується номерами гліфів, а символи відновлюються через ToUnicode)
+var subsetTables = []string{"cvt ", "fpgm", "glyf", "head", "hhea", "hmtx", "loca", "maxp", "prep"}
+
+// subset будує шрифт, у якому лишаються контури тільки використаних гліфів. Номери гліфів
+// не змінюються, тож вони ж є CID у документі (CIDToGIDMap /Identity)
+func (tt *trueType) subset(used map[uint16]bool) []byte {
+	used[0] = true // .notdef обов'язковий
+	tt.withComponents(used)
+
+	var glyf bytes.Buffer
+	loca := make([]byte, 4*(tt.numGlyphs+1))
+	for gid := 0; gid < tt.numGlyphs; gid++ {
+		binary.BigEndian.PutUint32(loca[4*gid:], uint32(glyf.Len()))
+		if used[uint16(gid)] {
+			glyf.Write(tt.glyph(uint16(gid)))
+			for glyf.Len()%4 != 0 {
+				glyf.WriteByte(0)
+			}
+		}
+	}
+	binary.BigEndian.PutUint32(loca[4*tt.numGlyphs:], uint32(glyf.Len()))
+
+	// Зміщення в loca тепер 32-бітні; контрольна сума шрифту перераховується нижче
+	head := bytes.Clone(tt.tables["head"])
+	binary.BigEndian.PutUint32(head[8:], 0)
+	binary.BigEndian.PutUint16(head[50:], 1)
+
+	tables := map[string][]byte{"glyf": glyf.Bytes(), "loca": loca, "head": head}
+	var tags []string
+	for _, tag := range subsetTables {
+		if _, ok := tables[tag]; !ok {
+			table, ok := tt.tables[tag]
+			if !ok {
+				continue
+			}
+			tables[tag] = table
+		}
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+
+	return writeTrueType(tags, tables)
+}
+
+// writeTrueType збирає файл шрифту: каталог таблиць і самі таблиці, вирівняні по 4 байти
+func writeTrueType(tags []string, tables map[string][]byte) []byte {
+	searchRange, entrySelector := 1, 0
+	for searchRange*2 <= len(tags) {
+		searchRange *= 2
+		entrySelector++
+	}
+
+	var out bytes.Buffer
+	header := make([]byte, 12)
+	binary.BigEndian.PutUint32(header, 0x00010000)
+	binary.BigEndian.PutUint16(header[4:], uint16(len(tags)))
+	binary.BigEndian.PutUint16(header[6:], uint16(searchRange*16))
+	binary.BigEndian.PutUint16(header[8:], uint16(entrySelector))
+	binary.BigEndian.PutUint16(header[10:], uint16((len(tags)-searchRange)*16))
+	out.Write(header)
+
+	offset := 12 + 16*len(tags)
+	headOffset := 0
+	for _, tag := range tags {
+		table := tables[tag]
+		record := make([]byte, 16)
+		copy(record, tag)
+		binary.BigEndian.PutUint32(record[4:], checksum(table))
+		binary.BigEndian.PutUint32(record[8:], uint32(offset))
+		binary.BigEndian.PutUint32(record[12:], uint32(len(table)))
+		out.Write(record)
+		if tag == "head" {
+			headOffset = offset
+		}
+		offset += (len(table) + 3) &^ 3
+	}
+	for _, tag := range tags {
+		out.Write(tables[tag])
+		for out.Len()%4 != 0 {
+			out.WriteByte(0)
+		}
+	}
+
+	font := out.Bytes()
+	binary.BigEndian.PutUint32(font[headOffset+8:], 0xB1B0AFBA-checksum(font))
+	return font
+}
+
+// checksum - сума 32-бітних слів таблиці (неповне останнє слово доповнюється нулями)
+func checksum(data []byte) uint32 {
+	var sum uint32
+	for i := 0; i < len(data); i += 4 {
+		var word [4]byte
+		copy(word[:], data[i:])
+		sum += binary.BigEndian.Uint32(word[:])
+	}
+	return sum
+}
@@ -0,0 +1,293 @@
+// Package pdf - мінімальний генератор PDF 1.4 на чистому Go: сторінки A4, текст шрифтами
+// DejaVu Sans та DejaVu Sans Bold, лінії й залиті прямокутники. Шрифти вбудовуються в документ
+// підмножиною використаних гліфів разом з ToUnicode CMap, тож кирилиця та інші символи Unicode
+// відображаються, копіюються і шукаються у переглядачах як звичайний текст
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"sort"
+	"strings"
+	"unicode/utf16"
+)
+
+// Розмір сторінки A4 у пунктах
+const (
+	PageWidth  = 595.28
+	PageHeight = 841.89
+)
+
+// Font - назва ресурсу шрифту на сторінці
+type Font string
+
+const (
+	Regular Font = "F1"
+	Bold    Font = "F2"
+)
+
+type Document struct {
+	title string
+	pages []*Page
+
+	// Використані гліфи кожного шрифту і символи, які вони зображують (для підмножини і ToUnicode)
+	glyphs map[Font]map[uint16]rune
+}
+
+type Page struct {
+	doc     *Document
+	content bytes.Buffer
+}
+
+func New(title string) *Document {
+	return &Document{title: title, glyphs: map[Font]map[uint16]rune{}}
+}
+
+func (d *Document) AddPage() *Page {
+	page := &Page{doc: d}
+	d.pages = append(d.pages, page)
+	return page
+}
+
+// Text виводить рядок так, що (x, y) - початок базової лінії; y відраховується від низу сторінки
+func (p *Page) Text(x, y float64, font Font, size float64, text string) {
+	fmt.Fprintf(&p.content, "BT /%s %.2f Tf %.2f %.2f Td <%s> Tj ET\n", font, size, x, y, p.doc.encode(font, text))
+}
+
+// TextRight вирівнює рядок по правому краю right
+func (p *Page) TextRight(right, y float64, font Font, size float64, text string) {
+	p.Text(right-TextWidth(font, text, size), y, font, size, text)
+}
+
+func (p *Page) Line(x1, y1, x2, y2 float64) {
+	fmt.Fprintf(&p.content, "%.2f %.2f m %.2f %.2f l S\n", x1, y1, x2, y2)
+}
+
+// Rect заливає прямокутник відтінком сірого (0 - чорний, 1 - білий)
+func (p *Page) Rect(x, y, width, height, gray float64) {
+	fmt.Fprintf(&p.content, "q %.2f g %.2f %.2f %.2f %.2f re f Q\n", gray, x, y, width, height)
+}
+
+// TextWidth повертає ширину рядка за ширинами гліфів шрифту
+func TextWidth(font Font, text string, size float64) float64 {
+	tt := fontFor(font)
+	width := 0.0
+	for _, r := range text {
+		gid, _ := tt.glyphIndex(r)
+		width += tt.width(gid)
+	}
+	return width * size / 1000
+}
+
+// encode кодує рядок номерами гліфів (Identity-H, по два байти на гліф) і запам'ятовує їх
+func (d *Document) encode(font Font, text string) string {
+	tt := fontFor(font)
+	used := d.glyphs[font]
+	if used == nil {
+		used = map[uint16]rune{}
+		d.glyphs[font] = used
+	}
+
+	var encoded strings.Builder
+	for _, r := range text {
+		gid, drawn := tt.glyphIndex(r)
+		if _, ok := used[gid]; !ok {
+			used[gid] = drawn
+		}
+		fmt.Fprintf(&encoded, "%04X", gid)
+	}
+	return encoded.String()
+}
+
+// WriteTo записує документ: каталог, дерево сторінок, відомості, використані шрифти, сторінки
+// з їх вмістом і таблицю xref
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	pages := d.pages
+	if len(pages) == 0 {
+		pages = []*Page{{}}
+	}
+
+	var buf bytes.Buffer
+	var offsets []int
+	object := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	// Об'єкти 1-3 фіксовані, далі по fontObjects на кожен використаний шрифт,
+	// а після них для кожної сторінки - сама сторінка та її вміст
+	used := make([]Font, 0, len(d.glyphs))
+	for font := range d.glyphs {
+		used = append(used, font)
+	}
+	sort.Slice(used, func(i, j int) bool { return used[i] < used[j] })
+
+	resources := make([]string, len(used))
+	for i, font := range used {
+		resources[i] = fmt.Sprintf("/%s %d 0 R", font, 4+fontObjects*i)
+	}
+	firstPage := 4 + fontObjects*len(used)
+	kids := make([]string, len(pages))
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", firstPage+2*i)
+	}
+
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)))
+	object(fmt.Sprintf("<< /Title %s /Producer (uni-golang-labs) >>", textString(d.title)))
+
+	for _, font := range used {
+		err := writeFont(object, len(offsets)+1, fontFor(font), d.glyphs[font])
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	for i, page := range pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] /Resources << /Font << %s >> >> /Contents %d 0 R >>",
+			PageWidth, PageHeight, strings.Join(resources, " "), firstPage+2*i+1))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", page.content.Len(), page.content.String()))
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R /Info 3 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	return buf.WriteTo(w)
+}
+
+// fontObjects - кожен шрифт займає п'ять об'єктів: Type0, CIDFontType2, FontDescriptor,
+// файл шрифту та ToUnicode CMap
+const fontObjects = 5
+
+// writeFont записує шрифт з підмножиною гліфів used, починаючи з об'єкта number
+func writeFont(object func(string), number int, tt *trueType, used map[uint16]rune) error {
+	gids := make(map[uint16]bool, len(used))
+	for gid := range used {
+		gids[gid] = true
+	}
+	subset := tt.subset(gids)
+
+	var compressed bytes.Buffer
+	zw := zlib.NewWriter(&compressed)
+	_, err := zw.Write(subset)
+	if err != nil {
+		return err
+	}
+	err = zw.Close()
+	if err != nil {
+		return err
+	}
+
+	// Підмножина позначається шістьма великими літерами перед назвою шрифту
+	name := subsetTag(used) + "+" + tt.name
+
+	object(fmt.Sprintf("<< /Type /Font /Subtype /Type0 /BaseFont /%s /Encoding /Identity-H /DescendantFonts [%d 0 R] /ToUnicode %d 0 R >>",
+		name, number+1, number+4))
+	object(fmt.Sprintf("<< /Type /Font /Subtype /CIDFontType2 /BaseFont /%s /CIDSystemInfo << /Registry (Adobe) /Ordering (Identity) /Supplement 0 >> "+
+		"/FontDescriptor %d 0 R /W [%s] /CIDToGIDMap /Identity >>", name, number+2, widths(tt, used)))
+	object(fmt.Sprintf("<< /Type /FontDescriptor /FontName /%s /Flags 32 /FontBBox [%d %d %d %d] /ItalicAngle 0 "+
+		"/Ascent %d /Descent %d /CapHeight %d /StemV 80 /FontFile2 %d 0 R >>", name,
+		tt.scale(tt.bbox[0]), tt.scale(tt.bbox[1]), tt.scale(tt.bbox[2]), tt.scale(tt.bbox[3]),
+		tt.scale(tt.ascent), tt.scale(tt.descent), tt.scale(tt.capHeight), number+3))
+	object(fmt.Sprintf("<< /Length %d /Length1 %d /Filter /FlateDecode >>\nstream\n%s\nendstream",
+		compressed.Len(), len(subset), compressed.String()))
+
+	cmap := toUnicode(used)
+	object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", len(cmap), cmap))
+
+	return nil
+}
+
+func sortedGlyphs(used map[uint16]rune) []uint16 {
+	gids := make([]uint16, 0, len(used))
+	for gid := range used {
+		gids = append(gids, gid)
+	}
+	sort.Slice(gids, func(i, j int) bool { return gids[i] < gids[j] })
+	return gids
+}
+
+// widths - масив /W: ширина кожного використаного гліфа
+func widths(tt *trueType, used map[uint16]rune) string {
+	var w strings.Builder
+	for i, gid := range sortedGlyphs(used) {
+		if i > 0 {
+			w.WriteByte(' ')
+		}
+		fmt.Fprintf(&w, "%d [%.0f]", gid, tt.width(gid))
+	}
+	return w.String()
+}
+
+// subsetTag виводить позначку підмножини з її складу, тож однаковий текст дає однаковий документ
+func subsetTag(used map[uint16]rune) string {
+	h := fnv.New32a()
+	for _, gid := range sortedGlyphs(used) {
+		h.Write([]byte{byte(gid >> 8), byte(gid)})
+	}
+
+	sum := h.Sum32()
+	tag := make([]byte, 6)
+	for i := range tag {
+		tag[i] = 'A' + byte(sum%26)
+		sum /= 26
+	}
+	return string(tag)
+}
+
+// toUnicode будує CMap, за якою переглядач перетворює номери гліфів назад на символи
+func toUnicode(used map[uint16]rune) string {
+	var cmap strings.Builder
+	cmap.WriteString("/CIDInit /ProcSet findresource begin\n12 dict begin\nbegincmap\n" +
+		"/CIDSystemInfo << /Registry (Adobe) /Ordering (UCS) /Supplement 0 >> def\n" +
+		"/CMapName /Adobe-Identity-UCS def\n/CMapType 2 def\n" +
+		"1 begincodespacerange\n<0000> <FFFF>\nendcodespacerange\n")
+
+	// Блок bfchar містить не більше 100 записів
+	gids := sortedGlyphs(used)
+	for start := 0; start < len(gids); start += 100 {
+		block := gids[start:min(start+100, len(gids))]
+		fmt.Fprintf(&cmap, "%d beginbfchar\n", len(block))
+		for _, gid := range block {
+			fmt.Fprintf(&cmap, "<%04X> <%s>\n", gid, utf16Hex(string(used[gid])))
+		}
+		cmap.WriteString("endbfchar\n")
+	}
+
+	cmap.WriteString("endcmap\nCMapName currentdict /CMap defineresource pop\nend\nend\n")
+	return cmap.String()
+}
+
+// utf16Hex - символи у UTF-16BE шістнадцятковими цифрами
+func utf16Hex(text string) string {
+	var encoded strings.Builder
+	for _, unit := range utf16.Encode([]rune(text)) {
+		fmt.Fprintf(&encoded, "%04X", unit)
+	}
+	return encoded.String()
+}
+
+// textString - текстовий рядок PDF (назва документа): ASCII - рядковим літералом,
+// інакше - у UTF-16BE з позначкою порядку байтів
+func textString(text string) string {
+	for _, r := range text {
+		if r < 0x20 || r >= 0x7F {
+			return "<FEFF" + utf16Hex(text) + ">"
+		}
+	}
+	return "(" + escape(text) + ")"
+}
+
+// escape екранує символи, що мають особливе значення в рядкових літералах PDF
+func escape(text string) string {
+	return strings.NewReplacer(`\`, `\\`, `(`, `\(`, `)`, `\)`).Replace(text)
+}
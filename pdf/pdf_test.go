@@ -0,0 +1,118 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestDocument_WriteTo(t *testing.T) {
+	// Arrange
+	doc := New("Statement (March)")
+	first := doc.AddPage()
+	first.Text(50, 800, Bold, 16, "Hello (world) \\ €")
+	first.Line(50, 790, 545, 790)
+	doc.AddPage().TextRight(545, 800, Regular, 10, "1234")
+
+	// Act
+	var buf bytes.Buffer
+	_, err := doc.WriteTo(&buf)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Received an error: received %v, expected %v", err, nil)
+	}
+	out := buf.String()
+
+	if !strings.HasPrefix(out, "%PDF-1.4") || !strings.HasSuffix(out, "%%EOF\n") {
+		t.Errorf("Document must start with a PDF header and end with EOF marker")
+	}
+	if !strings.Contains(out, "/Count 2") {
+		t.Errorf("Expected two pages in %q", out)
+	}
+	if !strings.Contains(out, "/Title (Statement \\(March\\))") || strings.Count(out, "/Subtype /Type0") != 2 {
+		t.Errorf("Expected the title and two embedded fonts in %q", out)
+	}
+	if texts := ExtractText(buf.Bytes()); !reflect.DeepEqual(texts, []string{"Hello (world) \\ €", "1234"}) {
+		t.Errorf("Received incorrect text: received %q", texts)
+	}
+
+	// Кожен запис xref має вказувати точно на початок свого об'єкта
+	startxref := regexp.MustCompile(`startxref\n(\d+)`).FindStringSubmatch(out)
+	xrefOffset, _ := strconv.Atoi(startxref[1])
+	if !strings.HasPrefix(out[xrefOffset:], "xref") {
+		t.Fatalf("startxref points to %q", out[xrefOffset:xrefOffset+10])
+	}
+	entries := regexp.MustCompile(`(\d{10}) 00000 n`).FindAllStringSubmatch(out[xrefOffset:], -1)
+	for i, entry := range entries {
+		offset, _ := strconv.Atoi(entry[1])
+		if !strings.HasPrefix(out[offset:], fmt.Sprintf("%d 0 obj", i+1)) {
+			t.Errorf("xref entry %d points to %q", i+1, out[offset:offset+10])
+		}
+	}
+}
+
+func TestDocument_CyrillicRoundTrip(t *testing.T) {
+	// Arrange
+	doc := New("Виписка за березень")
+	page := doc.AddPage()
+	page.Text(50, 800, Regular, 10, "Їжа та кафе: йогурт, ґрунт, café")
+	page.Text(50, 780, Bold, 10, "Користувач Оксана")
+	page.Text(50, 760, Regular, 10, "Емодзі 🙂")
+
+	// Act
+	var buf bytes.Buffer
+	_, err := doc.WriteTo(&buf)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Received an error: received %v, expected %v", err, nil)
+	}
+	expected := []string{"Їжа та кафе: йогурт, ґрунт, café", "Користувач Оксана", "Емодзі ?"}
+	if texts := ExtractText(buf.Bytes()); !reflect.DeepEqual(texts, expected) {
+		t.Errorf("Received incorrect text: received %q, expected %q", texts, expected)
+	}
+	if !strings.Contains(buf.String(), "/Title <FEFF04120438") {
+		t.Errorf("Expected the title in UTF-16BE")
+	}
+}
+
+func TestTrueType_Subset(t *testing.T) {
+	// Arrange: "й" у DejaVu складається з "и" і бреве
+	tt := fontFor(Regular)
+	used := map[uint16]bool{}
+	for _, r := range "Їжа й" {
+		gid, _ := tt.glyphIndex(r)
+		used[gid] = true
+	}
+	requested := len(used)
+
+	// Act
+	subset := tt.subset(used)
+
+	// Assert
+	if len(used) <= requested {
+		t.Errorf("Components of composite glyphs must be kept: received %d glyphs, expected more than %d", len(used), requested)
+	}
+	if checksum(subset) != 0xB1B0AFBA {
+		t.Errorf("Received incorrect font checksum: %#x", checksum(subset))
+	}
+	if len(subset) >= len(regularTTF)/4 {
+		t.Errorf("Subset must keep only used glyphs: received %d bytes of %d", len(subset), len(regularTTF))
+	}
+}
+
+func TestTextWidth_GlyphWidths(t *testing.T) {
+	// Цифри DejaVu Sans мають ширину 1303 з 2048 одиниць em
+	if width, expected := TextWidth(Regular, "100", 10), 3*1303*10/2048.0; math.Abs(width-expected) > 1e-9 {
+		t.Errorf("Received incorrect width: received %v, expected %v", width, expected)
+	}
+	if TextWidth(Regular, "Ш", 10) <= TextWidth(Regular, "і", 10) || TextWidth(Bold, "100", 10) <= TextWidth(Regular, "100", 10) {
+		t.Errorf("Widths must follow the glyphs of each font")
+	}
+}
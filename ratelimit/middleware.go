@@ -0,0 +1,136 @@
+package ratelimit
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ChomuCake/uni-golang-labs/logging"
+)
+
+// maxKeyBody обмежує, скільки тіла запиту читає KeyFunc, що шукає поле в JSON
+const maxKeyBody = 64 << 10
+
+// KeyFunc визначає, чий ліміт витрачає запит; порожній рядок - правило до запиту не застосовується
+type KeyFunc func(r *http.Request) string
+
+// Rule обмежує запити Method Path окремим відром для кожного ключа
+type Rule struct {
+	Name   string // префікс ключа у сховищі, має бути унікальним
+	Method string
+	Path   string
+	Limit  Limit
+	Key    KeyFunc
+}
+
+type Limiter struct {
+	store Store
+	rules []Rule
+}
+
+// New створює обмежувач; правила з нульовим Burst або Every пропускаються
+func New(store Store, rules ...Rule) *Limiter {
+	l := &Limiter{store: store}
+	for _, rule := range rules {
+		if rule.Limit.Burst > 0 && rule.Limit.Every > 0 {
+			l.rules = append(l.rules, rule)
+		}
+	}
+	return l
+}
+
+// Middleware відповідає 429 з заголовком Retry-After, щойно запит перевищує будь-яке з правил.
+// Якщо сховище недоступне, запит пропускається: збій Redis не повинен зупиняти вхід у систему
+func (l *Limiter) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for _, rule := range l.rules {
+			if r.Method != rule.Method || r.URL.Path != rule.Path {
+				continue
+			}
+
+			key := rule.Key(r)
+			if key == "" {
+				continue
+			}
+
+			result, err := l.store.Take(r.Context(), rule.Name+":"+key, rule.Limit)
+			if err != nil {
+				logging.FromContext(r.Context()).Warn("rate limit store unavailable", "rule", rule.Name, "error", err.Error())
+				continue
+			}
+
+			if !result.Allowed {
+				logging.FromContext(r.Context()).Info("rate limit exceeded", "rule", rule.Name, "retry_after", result.RetryAfter)
+				writeTooManyRequests(w, r, result.RetryAfter)
+				return
+			}
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// RetryAfterSeconds переводить паузу в значення заголовка Retry-After: цілі секунди з округленням
+// вгору, щоб клієнт, який чекає рівно стільки, не отримав повторну відмову
+func RetryAfterSeconds(d time.Duration) string {
+	seconds := int(math.Ceil(d.Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+	return strconv.Itoa(seconds)
+}
+
+// writeTooManyRequests відповідає у форматі problem+json, як і обробники API
+func writeTooManyRequests(w http.ResponseWriter, r *http.Request, retryAfter time.Duration) {
+	w.Header().Set("Retry-After", RetryAfterSeconds(retryAfter))
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(http.StatusTooManyRequests)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"type":     "about:blank",
+		"title":    http.StatusText(http.StatusTooManyRequests),
+		"status":   http.StatusTooManyRequests,
+		"detail":   "too many requests, try again later",
+		"instance": r.URL.Path,
+		"code":     "rate_limited",
+	})
+}
+
+// ByIP - ключ за адресою клієнта. Заголовки проксі (X-Forwarded-For) не враховуються,
+// бо їх може підробити сам клієнт
+func ByIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// ByJSONField - ключ за рядковим полем JSON-тіла (наприклад, username). Тіло повертається
+// в запит, щоб обробник прочитав його повністю; регістр не враховується, як і в базі даних
+func ByJSONField(field string) KeyFunc {
+	return func(r *http.Request) string {
+		if r.Body == nil {
+			return ""
+		}
+
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxKeyBody))
+		if err != nil {
+			return ""
+		}
+		r.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), r.Body))
+
+		var fields map[string]interface{}
+		if json.Unmarshal(body, &fields) != nil {
+			return ""
+		}
+
+		value, _ := fields[field].(string)
+		return strings.ToLower(value)
+	}
+}
@@ -0,0 +1,44 @@
+// Package ratelimit обмежує частоту запитів алгоритмом token bucket: кожен ключ (IP-адреса,
+// ім'я користувача) має відро з Burst токенами, що поповнюється на один токен кожні Every.
+// Стан відер зберігається в пам'яті процесу (MemoryStore) або в Redis (RedisStore),
+// щоб кілька екземплярів сервера мали спільні ліміти
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+// Limit - місткість відра і час поповнення одного токена; нульовий Burst вимикає обмеження
+type Limit struct {
+	Burst int
+	Every time.Duration
+}
+
+// Result - рішення щодо одного запиту; RetryAfter - коли з'явиться наступний токен, якщо запит відхилено
+type Result struct {
+	Allowed    bool
+	RetryAfter time.Duration
+}
+
+// Store забирає токен з відра key, створюючи повне відро для нового ключа
+type Store interface {
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+// take - спільна для сховищ арифметика відра: tokens - залишок після останнього запиту,
+// elapsed - час від нього. Повертає новий залишок і рішення
+func take(tokens float64, elapsed time.Duration, limit Limit) (float64, Result) {
+	if elapsed > 0 {
+		tokens += float64(elapsed) / float64(limit.Every)
+	}
+	if burst := float64(limit.Burst); tokens > burst {
+		tokens = burst
+	}
+
+	if tokens >= 1 {
+		return tokens - 1, Result{Allowed: true}
+	}
+
+	return tokens, Result{RetryAfter: time.Duration((1 - tokens) * float64(limit.Every))}
+}
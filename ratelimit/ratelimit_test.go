@@ -0,0 +1,218 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+var testLimit = Limit{Burst: 2, Every: 10 * time.Second}
+
+type fakeClock struct {
+	t time.Time
+}
+
+func (c *fakeClock) now() time.Time { return c.t }
+
+// assertBucket перевіряє поведінку відра однаково для обох сховищ
+func assertBucket(t *testing.T, store Store, clock *fakeClock) {
+	t.Helper()
+	ctx := context.Background()
+
+	// Повне відро пропускає Burst запитів поспіль
+	for i := 0; i < testLimit.Burst; i++ {
+		result, err := store.Take(ctx, "ip:10.0.0.1", testLimit)
+		if err != nil || !result.Allowed {
+			t.Fatalf("Received incorrect result for request %d: received %+v, %v, expected allowed", i+1, result, err)
+		}
+	}
+
+	// Наступний запит відхиляється до появи нового токена
+	result, err := store.Take(ctx, "ip:10.0.0.1", testLimit)
+	if err != nil || result.Allowed || result.RetryAfter != testLimit.Every {
+		t.Fatalf("Received incorrect result: received %+v, %v, expected retry after %v", result, err, testLimit.Every)
+	}
+
+	// Інший ключ має власне відро
+	result, err = store.Take(ctx, "ip:10.0.0.2", testLimit)
+	if err != nil || !result.Allowed {
+		t.Errorf("Received incorrect result for another key: received %+v, %v, expected allowed", result, err)
+	}
+
+	// Через половину інтервалу токена ще немає, після повного - є рівно один
+	clock.t = clock.t.Add(testLimit.Every / 2)
+	result, _ = store.Take(ctx, "ip:10.0.0.1", testLimit)
+	if result.Allowed || result.RetryAfter != testLimit.Every/2 {
+		t.Errorf("Received incorrect result: received %+v, expected retry after %v", result, testLimit.Every/2)
+	}
+
+	clock.t = clock.t.Add(testLimit.Every / 2)
+	first, _ := store.Take(ctx, "ip:10.0.0.1", testLimit)
+	second, _ := store.Take(ctx, "ip:10.0.0.1", testLimit)
+	if !first.Allowed || second.Allowed {
+		t.Errorf("Received incorrect refill: received %+v, %+v, expected exactly one token", first, second)
+	}
+}
+
+func TestMemoryStore_Take(t *testing.T) {
+	// Arrange
+	clock := &fakeClock{t: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)}
+	store := NewMemoryStore()
+	store.now = clock.now
+
+	// Act & Assert
+	assertBucket(t, store, clock)
+}
+
+func TestMemoryStore_SweepsFullBuckets(t *testing.T) {
+	// Arrange
+	clock := &fakeClock{t: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)}
+	store := NewMemoryStore()
+	store.now = clock.now
+	_, _ = store.Take(context.Background(), "user:alice", testLimit)
+	_, _ = store.Take(context.Background(), "user:bob", Limit{Burst: 2, Every: time.Hour})
+
+	// Act
+	clock.t = clock.t.Add(2 * sweepInterval)
+	_, _ = store.Take(context.Background(), "user:carol", testLimit)
+
+	// Assert
+	if store.Len() != 2 {
+		t.Errorf("Received incorrect bucket count: received %v, expected %v", store.Len(), 2)
+	}
+}
+
+func TestRedisStore_Take(t *testing.T) {
+	// Arrange
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	defer client.Close()
+
+	clock := &fakeClock{t: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)}
+	store := NewRedisStore(client)
+	store.now = clock.now
+
+	// Act & Assert
+	assertBucket(t, store, clock)
+
+	if ttl := server.TTL(keyPrefix + "ip:10.0.0.2"); ttl <= 0 || ttl > testLimit.Every*time.Duration(testLimit.Burst)+time.Second {
+		t.Errorf("Received incorrect key TTL: received %v", ttl)
+	}
+}
+
+func TestRedisStore_Unavailable(t *testing.T) {
+	// Arrange
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr(), MaxRetries: -1})
+	defer client.Close()
+	server.Close()
+
+	// Act
+	_, err := NewRedisStore(client).Take(context.Background(), "ip:10.0.0.1", testLimit)
+
+	// Assert
+	if err == nil {
+		t.Errorf("Received an error: received %v, expected connection error", err)
+	}
+}
+
+type failingStore struct{}
+
+func (failingStore) Take(context.Context, string, Limit) (Result, error) {
+	return Result{}, errors.New("connection refused")
+}
+
+func loginRequest(remoteAddr, body string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(body))
+	req.RemoteAddr = remoteAddr
+	return req
+}
+
+func TestLimiter_Middleware(t *testing.T) {
+	// Arrange
+	var bodies []string
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+	})
+	limiter := New(NewMemoryStore(),
+		Rule{Name: "login_ip", Method: http.MethodPost, Path: "/login", Limit: Limit{Burst: 3, Every: time.Minute}, Key: ByIP},
+		Rule{Name: "login_user", Method: http.MethodPost, Path: "/login", Limit: Limit{Burst: 1, Every: 90 * time.Second}, Key: ByJSONField("username")},
+	)
+	handler := limiter.Middleware(next)
+
+	// Act
+	codes := []int{}
+	for _, req := range []*http.Request{
+		loginRequest("10.0.0.1:5000", `{"username":"alice","password":"a"}`),
+		loginRequest("10.0.0.1:5001", `{"username":"Alice","password":"b"}`), // те саме ім'я в іншому регістрі
+		loginRequest("10.0.0.1:5002", `{"username":"bob","password":"c"}`),
+		loginRequest("10.0.0.1:5003", `{"username":"carol","password":"d"}`), // вичерпано ліміт IP
+		loginRequest("10.0.0.2:5000", `{"username":"dave","password":"e"}`),
+	} {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		codes = append(codes, rec.Code)
+
+		if rec.Code == http.StatusTooManyRequests {
+			if rec.Header().Get("Retry-After") == "" || !strings.Contains(rec.Body.String(), `"rate_limited"`) {
+				t.Errorf("Received incorrect 429 response: headers %v, body %s", rec.Header(), rec.Body.String())
+			}
+		}
+	}
+
+	// Assert
+	expected := []int{200, 429, 200, 429, 200}
+	for i := range expected {
+		if codes[i] != expected[i] {
+			t.Fatalf("Received incorrect statuses: received %v, expected %v", codes, expected)
+		}
+	}
+	if len(bodies) != 3 || bodies[0] != `{"username":"alice","password":"a"}` {
+		t.Errorf("Received incorrect request bodies: received %v", bodies)
+	}
+}
+
+func TestLimiter_Middleware_OtherRoutesAndStoreFailure(t *testing.T) {
+	// Arrange
+	calls := 0
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { calls++ })
+	rule := Rule{Name: "login_ip", Method: http.MethodPost, Path: "/login", Limit: Limit{Burst: 1, Every: time.Minute}, Key: ByIP}
+	limited := New(NewMemoryStore(), rule).Middleware(next)
+	failing := New(failingStore{}, rule).Middleware(next)
+
+	// Act
+	for i := 0; i < 3; i++ {
+		limited.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/register", nil))
+		failing.ServeHTTP(httptest.NewRecorder(), loginRequest("10.0.0.1:5000", `{}`))
+	}
+
+	// Assert
+	if calls != 6 {
+		t.Errorf("Received incorrect handler calls: received %v, expected %v", calls, 6)
+	}
+}
+
+func TestRetryAfterSeconds(t *testing.T) {
+	cases := map[time.Duration]string{
+		0:                       "1",
+		300 * time.Millisecond:  "1",
+		time.Second:             "1",
+		1500 * time.Millisecond: "2",
+		time.Minute:             "60",
+	}
+
+	for d, expected := range cases {
+		if received := RetryAfterSeconds(d); received != expected {
+			t.Errorf("Received incorrect Retry-After for %v: received %v, expected %v", d, received, expected)
+		}
+	}
+}
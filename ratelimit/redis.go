@@ -0,0 +1,68 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// keyPrefix відокремлює ключі лімітів від інших даних у тій самій базі Redis
+const keyPrefix = "fintrack:ratelimit:"
+
+// takeScript виконує ту саму арифметику, що й take, атомарно на боці Redis, тож кілька
+// екземплярів сервера не можуть одночасно забрати останній токен. Відро - хеш з полями
+// tokens і ts (мс), поточний час передається сервером, а ключ зникає, коли відро знову повне
+var takeScript = redis.NewScript(`
+local burst = tonumber(ARGV[1])
+local every = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(state[1])
+local ts = tonumber(state[2])
+if tokens == nil or ts == nil then
+	tokens = burst
+	ts = now
+end
+
+if now > ts then
+	tokens = tokens + (now - ts) / every
+end
+if tokens > burst then
+	tokens = burst
+end
+
+local allowed = 0
+local retry = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+else
+	retry = math.ceil((1 - tokens) * every)
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', tostring(now))
+redis.call('PEXPIRE', KEYS[1], math.ceil((burst - tokens) * every) + 1000)
+return {allowed, retry}
+`)
+
+// RedisStore зберігає відра в Redis (або сумісному сервері з підтримкою Lua-скриптів)
+type RedisStore struct {
+	client redis.Scripter
+	now    func() time.Time
+}
+
+func NewRedisStore(client redis.Scripter) *RedisStore {
+	return &RedisStore{client: client, now: time.Now}
+}
+
+func (s *RedisStore) Take(ctx context.Context, key string, limit Limit) (Result, error) {
+	reply, err := takeScript.Run(ctx, s.client, []string{keyPrefix + key},
+		limit.Burst, limit.Every.Milliseconds(), s.now().UnixMilli()).Int64Slice()
+	if err != nil {
+		return Result{}, err
+	}
+
+	return Result{Allowed: reply[0] == 1, RetryAfter: time.Duration(reply[1]) * time.Millisecond}, nil
+}
@@ -0,0 +1,188 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/ChomuCake/uni-golang-labs/models"
+)
+
+type AccessTokenDB interface {
+	AddAccessToken(ctx context.Context, token models.AccessToken, tokenHash string) (int, error)
+	GetUserAccessTokens(ctx context.Context, userID int) ([]models.AccessToken, error)
+	GetAccessTokenByHash(ctx context.Context, tokenHash string) (models.AccessToken, error)
+	TouchAccessToken(ctx context.Context, tokenID int, usedAt time.Time) error
+	DeleteAccessToken(ctx context.Context, userID, tokenID int) (bool, error)
+	DeleteUserAccessTokens(ctx context.Context, userID int) error
+}
+
+// Обмеження для персональних токенів
+const (
+	maxAccessTokenName   = 64
+	maxAccessTokensCount = 50
+	// lastUsedPrecision - час використання оновлюється не частіше, щоб кожен запит скрипта не писав у базу
+	lastUsedPrecision = time.Minute
+)
+
+type AccessTokenService struct {
+	tokenDB AccessTokenDB
+}
+
+func NewAccessTokenService(tokenDB AccessTokenDB) *AccessTokenService {
+	return &AccessTokenService{tokenDB: tokenDB}
+}
+
+// hashAccessToken - у базі зберігається лише хеш, тож витік таблиці не розкриває токени
+func hashAccessToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func validateAccessToken(request models.AccessTokenRequest, now time.Time) error {
+	v := &validator{}
+	v.length("name", request.Name, 1, maxAccessTokenName)
+	v.check(len(request.Scopes) > 0, "scopes", "required", "at least one scope is required")
+
+	seen := map[string]bool{}
+	for _, scope := range request.Scopes {
+		known := false
+		for _, s := range models.Scopes {
+			known = known || s == scope
+		}
+		v.check(known, "scopes", "unknown_scope", "unknown scope "+scope)
+		v.check(!seen[scope], "scopes", "duplicate_scope", "duplicate scope "+scope)
+		seen[scope] = true
+	}
+
+	if request.ExpiresAt != nil {
+		v.check(request.ExpiresAt.After(now), "expires_at", "in_past", "must be in the future")
+	}
+
+	return v.err()
+}
+
+// CreateToken видає новий токен; повернений токен більше ніде не зберігається і показується лише раз
+func (s *AccessTokenService) CreateToken(ctx context.Context, userID int, request models.AccessTokenRequest) (_ models.CreatedAccessToken, err error) {
+	ctx, end := startSpan(ctx, "AccessTokenService.CreateToken")
+	defer end(&err)
+
+	now := time.Now()
+	err = validateAccessToken(request, now)
+	if err != nil {
+		return models.CreatedAccessToken{}, err
+	}
+
+	existing, err := s.tokenDB.GetUserAccessTokens(ctx, userID)
+	if err != nil {
+		return models.CreatedAccessToken{}, internalError("access_token_create_failed", "failed to create access token", err)
+	}
+	if len(existing) >= maxAccessTokensCount {
+		return models.CreatedAccessToken{}, newError(ErrConflict, "too_many_access_tokens", "too many access tokens, revoke unused ones")
+	}
+
+	raw := make([]byte, 32)
+	_, err = rand.Read(raw)
+	if err != nil {
+		return models.CreatedAccessToken{}, internalError("access_token_create_failed", "failed to create access token", err)
+	}
+	secret := models.AccessTokenPrefix + base64.RawURLEncoding.EncodeToString(raw)
+
+	token := models.AccessToken{
+		UserID:    userID,
+		Name:      request.Name,
+		Scopes:    request.Scopes,
+		CreatedAt: now.UTC().Truncate(time.Second),
+		ExpiresAt: request.ExpiresAt,
+	}
+	token.ID, err = s.tokenDB.AddAccessToken(ctx, token, hashAccessToken(secret))
+	if err != nil {
+		return models.CreatedAccessToken{}, internalError("access_token_create_failed", "failed to create access token", err)
+	}
+
+	return models.CreatedAccessToken{AccessToken: token, Token: secret}, nil
+}
+
+func (s *AccessTokenService) ListTokens(ctx context.Context, userID int) (_ []models.AccessToken, err error) {
+	ctx, end := startSpan(ctx, "AccessTokenService.ListTokens")
+	defer end(&err)
+
+	tokens, err := s.tokenDB.GetUserAccessTokens(ctx, userID)
+	if err != nil {
+		return nil, internalError("access_tokens_fetch_failed", "failed to get access tokens", err)
+	}
+
+	if tokens == nil {
+		tokens = []models.AccessToken{}
+	}
+
+	return tokens, nil
+}
+
+func (s *AccessTokenService) RevokeToken(ctx context.Context, userID, tokenID int) (err error) {
+	ctx, end := startSpan(ctx, "AccessTokenService.RevokeToken")
+	defer end(&err)
+
+	deleted, err := s.tokenDB.DeleteAccessToken(ctx, userID, tokenID)
+	if err != nil {
+		return internalError("access_token_revoke_failed", "failed to revoke access token", err)
+	}
+	if !deleted {
+		return errAccessTokenNotFound
+	}
+
+	return nil
+}
+
+// RevokeAllTokens відкликає всі токени користувача (зміна чи відновлення пароля)
+func (s *AccessTokenService) RevokeAllTokens(ctx context.Context, userID int) (err error) {
+	ctx, end := startSpan(ctx, "AccessTokenService.RevokeAllTokens")
+	defer end(&err)
+
+	err = s.tokenDB.DeleteUserAccessTokens(ctx, userID)
+	if err != nil {
+		return internalError("access_token_revoke_failed", "failed to revoke access tokens", err)
+	}
+
+	return nil
+}
+
+// AuthenticateAccessToken перевіряє персональний токен для операції scope і повертає айді власника.
+// Порожній scope означає маршрут, недоступний для персональних токенів (керування обліковим записом)
+func (s *AccessTokenService) AuthenticateAccessToken(ctx context.Context, secret, scope string) (_ int, err error) {
+	ctx, end := startSpan(ctx, "AccessTokenService.AuthenticateAccessToken")
+	defer end(&err)
+
+	token, err := s.tokenDB.GetAccessTokenByHash(ctx, hashAccessToken(secret))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, errInvalidAccessToken
+		}
+		return 0, internalError("access_token_check_failed", "failed to check access token", err)
+	}
+
+	now := time.Now()
+	if token.ExpiresAt != nil && !now.Before(*token.ExpiresAt) {
+		return 0, errInvalidAccessToken
+	}
+	if scope == "" {
+		return 0, errAccessTokenNotAllowed
+	}
+	if !token.HasScope(scope) {
+		return 0, newError(ErrForbidden, "insufficient_scope", "access token lacks the "+scope+" scope")
+	}
+
+	if token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) >= lastUsedPrecision {
+		err = s.tokenDB.TouchAccessToken(ctx, token.ID, now)
+		if err != nil {
+			return 0, internalError("access_token_check_failed", "failed to check access token", err)
+		}
+	}
+
+	return token.UserID, nil
+}
@@ -0,0 +1,170 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ChomuCake/uni-golang-labs/models"
+)
+
+// MockAccessTokenDB зберігає токени за хешем, як таблиця access_tokens
+type MockAccessTokenDB struct {
+	tokens  map[string]models.AccessToken
+	touches int
+}
+
+func (db *MockAccessTokenDB) AddAccessToken(ctx context.Context, token models.AccessToken, tokenHash string) (int, error) {
+	if db.tokens == nil {
+		db.tokens = map[string]models.AccessToken{}
+	}
+	token.ID = len(db.tokens) + 1
+	db.tokens[tokenHash] = token
+	return token.ID, nil
+}
+
+func (db *MockAccessTokenDB) GetUserAccessTokens(ctx context.Context, userID int) ([]models.AccessToken, error) {
+	var tokens []models.AccessToken
+	for _, token := range db.tokens {
+		if token.UserID == userID {
+			tokens = append(tokens, token)
+		}
+	}
+	return tokens, nil
+}
+
+func (db *MockAccessTokenDB) GetAccessTokenByHash(ctx context.Context, tokenHash string) (models.AccessToken, error) {
+	token, ok := db.tokens[tokenHash]
+	if !ok {
+		return models.AccessToken{}, sql.ErrNoRows
+	}
+	return token, nil
+}
+
+func (db *MockAccessTokenDB) TouchAccessToken(ctx context.Context, tokenID int, usedAt time.Time) error {
+	for hash, token := range db.tokens {
+		if token.ID == tokenID {
+			token.LastUsedAt = &usedAt
+			db.tokens[hash] = token
+		}
+	}
+	db.touches++
+	return nil
+}
+
+func (db *MockAccessTokenDB) DeleteAccessToken(ctx context.Context, userID, tokenID int) (bool, error) {
+	for hash, token := range db.tokens {
+		if token.ID == tokenID && token.UserID == userID {
+			delete(db.tokens, hash)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (db *MockAccessTokenDB) DeleteUserAccessTokens(ctx context.Context, userID int) error {
+	for hash, token := range db.tokens {
+		if token.UserID == userID {
+			delete(db.tokens, hash)
+		}
+	}
+	return nil
+}
+
+func TestAccessTokenService_CreateToken_Validation(t *testing.T) {
+	// Arrange
+	s := NewAccessTokenService(&MockAccessTokenDB{})
+	past := time.Now().Add(-time.Hour)
+	requests := []models.AccessTokenRequest{
+		{Name: "", Scopes: []string{models.ScopeExpensesRead}},
+		{Name: "script", Scopes: nil},
+		{Name: "script", Scopes: []string{"everything"}},
+		{Name: "script", Scopes: []string{models.ScopeExpensesRead, models.ScopeExpensesRead}},
+		{Name: "script", Scopes: []string{models.ScopeExpensesRead}, ExpiresAt: &past},
+	}
+
+	for _, request := range requests {
+		// Act
+		_, err := s.CreateToken(context.Background(), testUser.ID, request)
+
+		// Assert
+		if !errors.Is(err, ErrInvalid) {
+			t.Errorf("Received an error for %+v: received %v, expected %v", request, err, ErrInvalid)
+		}
+	}
+}
+
+func TestAccessTokenService_AuthenticateAccessToken(t *testing.T) {
+	// Arrange
+	db := &MockAccessTokenDB{}
+	s := NewAccessTokenService(db)
+	created, err := s.CreateToken(context.Background(), testUser.ID, models.AccessTokenRequest{
+		Name:   "import script",
+		Scopes: []string{models.ScopeExpensesRead},
+	})
+	if err != nil {
+		t.Fatalf("Received an error: received %v, expected %v", err, nil)
+	}
+
+	// Act
+	userID, readErr := s.AuthenticateAccessToken(context.Background(), created.Token, models.ScopeExpensesRead)
+	_, againErr := s.AuthenticateAccessToken(context.Background(), created.Token, models.ScopeExpensesRead)
+	_, writeErr := s.AuthenticateAccessToken(context.Background(), created.Token, models.ScopeExpensesWrite)
+	_, accountErr := s.AuthenticateAccessToken(context.Background(), created.Token, "")
+	_, unknownErr := s.AuthenticateAccessToken(context.Background(), created.Token+"x", models.ScopeExpensesRead)
+
+	// Assert
+	if !strings.HasPrefix(created.Token, models.AccessTokenPrefix) {
+		t.Errorf("Received incorrect token: %q, expected the %q prefix", created.Token, models.AccessTokenPrefix)
+	}
+	for hash := range db.tokens {
+		if strings.Contains(created.Token, hash) || hash != hashAccessToken(created.Token) {
+			t.Errorf("Access tokens must be stored only as hashes")
+		}
+	}
+	if readErr != nil || againErr != nil || userID != testUser.ID {
+		t.Errorf("Received incorrect result: received %d, %v, %v, expected %d", userID, readErr, againErr, testUser.ID)
+	}
+	if db.touches != 1 {
+		t.Errorf("Received incorrect number of last_used_at updates: received %d, expected %d", db.touches, 1)
+	}
+	if !errors.Is(writeErr, ErrForbidden) || !errors.Is(accountErr, ErrForbidden) {
+		t.Errorf("Received an error: received %v, %v, expected %v", writeErr, accountErr, ErrForbidden)
+	}
+	if !errors.Is(unknownErr, ErrUnauthorized) {
+		t.Errorf("Received an error: received %v, expected %v", unknownErr, errInvalidAccessToken)
+	}
+}
+
+func TestAccessTokenService_ExpiredAndRevoked(t *testing.T) {
+	// Arrange
+	db := &MockAccessTokenDB{}
+	s := NewAccessTokenService(db)
+	soon := time.Now().Add(time.Hour)
+	expiring, _ := s.CreateToken(context.Background(), testUser.ID, models.AccessTokenRequest{Name: "temp", Scopes: []string{models.ScopeReportsRead}, ExpiresAt: &soon})
+	revoked, _ := s.CreateToken(context.Background(), testUser.ID, models.AccessTokenRequest{Name: "old", Scopes: []string{models.ScopeReportsRead}})
+	stored := db.tokens[hashAccessToken(expiring.Token)]
+	expired := time.Now().Add(-time.Second)
+	stored.ExpiresAt = &expired
+	db.tokens[hashAccessToken(expiring.Token)] = stored
+
+	// Act
+	_, expiredErr := s.AuthenticateAccessToken(context.Background(), expiring.Token, models.ScopeReportsRead)
+	otherUserErr := s.RevokeToken(context.Background(), testUser.ID+1, revoked.ID)
+	revokeErr := s.RevokeToken(context.Background(), testUser.ID, revoked.ID)
+	_, revokedErr := s.AuthenticateAccessToken(context.Background(), revoked.Token, models.ScopeReportsRead)
+
+	// Assert
+	if !errors.Is(expiredErr, ErrUnauthorized) {
+		t.Errorf("Received an error: received %v, expected %v", expiredErr, errInvalidAccessToken)
+	}
+	if !errors.Is(otherUserErr, ErrNotFound) || revokeErr != nil {
+		t.Errorf("Received incorrect result: received %v, %v, expected %v, %v", otherUserErr, revokeErr, errAccessTokenNotFound, nil)
+	}
+	if !errors.Is(revokedErr, ErrUnauthorized) {
+		t.Errorf("Received an error: received %v, expected %v", revokedErr, errInvalidAccessToken)
+	}
+}
@@ -0,0 +1,218 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/ChomuCake/uni-golang-labs/models"
+)
+
+type AccountDB interface {
+	AddAccount(ctx context.Context, account models.Account) (int, error)
+	GetUserAccounts(ctx context.Context, userID int) ([]models.Account, error)
+	GetAccountByID(ctx context.Context, accountID int) (models.Account, error)
+}
+
+type detailAccountDB interface {
+	AccountDB
+	GetUserAccountBalances(ctx context.Context, userID int) ([]models.Account, error)
+	GetAccountEntries(ctx context.Context, accountID int) ([]models.AccountEntry, error)
+	AddTransfer(ctx context.Context, transfer models.Transfer) (int, error)
+	GetUserTransfers(ctx context.Context, userID int) ([]models.Transfer, error)
+}
+
+type AccountService struct {
+	accountDB detailAccountDB
+	userDB    UserDB
+}
+
+func NewAccountService(accountDB detailAccountDB, userDB UserDB) *AccountService {
+	return &AccountService{accountDB, userDB}
+}
+
+func (s *AccountService) CreateAccount(ctx context.Context, userID int, account models.Account) (models.Account, error) {
+	account.Name = strings.TrimSpace(account.Name)
+	if account.Name == "" {
+		return models.Account{}, newError(ErrInvalid, "account_name_required", "account name is required")
+	}
+
+	switch account.Type {
+	case models.AccountCash, models.AccountCard, models.AccountBank, models.AccountSavings:
+	default:
+		return models.Account{}, newError(ErrInvalid, "invalid_account_type", "invalid account type")
+	}
+
+	account.UserID = userID
+	accountID, err := s.accountDB.AddAccount(ctx, account)
+	if err != nil {
+		return models.Account{}, internalError("account_create_failed", "failed to create account", err)
+	}
+
+	account.ID = accountID
+	account.Balance = account.InitialBalance
+
+	return account, nil
+}
+
+// GetAccounts повертає рахунки користувача з їх поточними балансами
+func (s *AccountService) GetAccounts(ctx context.Context, userID int) ([]models.Account, error) {
+	accounts, err := s.accountDB.GetUserAccountBalances(ctx, userID)
+	if err != nil {
+		return nil, internalError("accounts_fetch_failed", "failed to get accounts", err)
+	}
+
+	if accounts == nil {
+		accounts = []models.Account{}
+	}
+
+	return accounts, nil
+}
+
+// GetBalance повертає рахунок з балансом на початок календарного дня at у часовому поясі
+// користувача (нульовий час - поточний баланс)
+func (s *AccountService) GetBalance(ctx context.Context, userID, accountID int, at time.Time) (models.Account, error) {
+	account, err := s.ownedAccount(ctx, userID, accountID)
+	if err != nil {
+		return models.Account{}, err
+	}
+
+	loc := s.location(ctx, userID)
+	if !at.IsZero() {
+		at = startOfDay(at, loc)
+	}
+
+	entries, err := s.history(ctx, account)
+	if err != nil {
+		return models.Account{}, err
+	}
+
+	account.Balance = balanceAt(account, entries, at)
+
+	return account, nil
+}
+
+// GetHistory повертає рухи коштів по рахунку з балансом після кожного з них за період
+// між календарними днями [from, to) у часовому поясі користувача
+func (s *AccountService) GetHistory(ctx context.Context, userID, accountID int, from, to time.Time) ([]models.AccountEntry, error) {
+	account, err := s.ownedAccount(ctx, userID, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	loc := s.location(ctx, userID)
+	if !from.IsZero() {
+		from = startOfDay(from, loc)
+	}
+	if !to.IsZero() {
+		to = startOfDay(to, loc)
+	}
+
+	entries, err := s.history(ctx, account)
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := []models.AccountEntry{}
+	for _, entry := range entries {
+		if !from.IsZero() && entry.Date.Before(from) {
+			continue
+		}
+		if !to.IsZero() && !entry.Date.Before(to) {
+			continue
+		}
+		filtered = append(filtered, entry)
+	}
+
+	return filtered, nil
+}
+
+func (s *AccountService) CreateTransfer(ctx context.Context, userID int, transfer models.Transfer) (models.Transfer, error) {
+	if transfer.Amount <= 0 {
+		return models.Transfer{}, newError(ErrInvalid, "invalid_transfer_amount", "transfer amount must be positive")
+	}
+
+	if transfer.FromAccountID == transfer.ToAccountID {
+		return models.Transfer{}, newError(ErrInvalid, "same_transfer_accounts", "transfer accounts must differ")
+	}
+
+	for _, accountID := range []int{transfer.FromAccountID, transfer.ToAccountID} {
+		_, err := s.ownedAccount(ctx, userID, accountID)
+		if err != nil {
+			return models.Transfer{}, err
+		}
+	}
+
+	transfer.UserID = userID
+	if transfer.Date.IsZero() {
+		transfer.Date = time.Now()
+	}
+
+	transferID, err := s.accountDB.AddTransfer(ctx, transfer)
+	if err != nil {
+		return models.Transfer{}, internalError("transfer_create_failed", "failed to create transfer", err)
+	}
+	transfer.ID = transferID
+
+	return transfer, nil
+}
+
+func (s *AccountService) GetTransfers(ctx context.Context, userID int) ([]models.Transfer, error) {
+	transfers, err := s.accountDB.GetUserTransfers(ctx, userID)
+	if err != nil {
+		return nil, internalError("transfers_fetch_failed", "failed to get transfers", err)
+	}
+
+	if transfers == nil {
+		transfers = []models.Transfer{}
+	}
+
+	return transfers, nil
+}
+
+func (s *AccountService) location(ctx context.Context, userID int) *time.Location {
+	user, err := s.userDB.GetUserByID(ctx, userID)
+	if err != nil {
+		return time.UTC
+	}
+
+	return userLocation(user)
+}
+
+func (s *AccountService) ownedAccount(ctx context.Context, userID, accountID int) (models.Account, error) {
+	account, err := s.accountDB.GetAccountByID(ctx, accountID)
+	if err != nil || account.UserID != userID {
+		return models.Account{}, errAccountNotFound
+	}
+
+	return account, nil
+}
+
+// history завантажує рухи коштів по рахунку і обчислює баланс після кожного з них
+func (s *AccountService) history(ctx context.Context, account models.Account) ([]models.AccountEntry, error) {
+	entries, err := s.accountDB.GetAccountEntries(ctx, account.ID)
+	if err != nil {
+		return nil, internalError("account_history_failed", "failed to get account history", err)
+	}
+
+	balance := account.InitialBalance
+	for i := range entries {
+		balance += entries[i].Amount
+		entries[i].Balance = balance
+	}
+
+	return entries, nil
+}
+
+// balanceAt повертає баланс після останнього руху, що відбувся до моменту at
+func balanceAt(account models.Account, entries []models.AccountEntry, at time.Time) int {
+	balance := account.InitialBalance
+	for _, entry := range entries {
+		if !at.IsZero() && !entry.Date.Before(at) {
+			break
+		}
+		balance = entry.Balance
+	}
+
+	return balance
+}
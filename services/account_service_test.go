@@ -0,0 +1,166 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ChomuCake/uni-golang-labs/models"
+)
+
+// MockAccountDBDetail зберігає рахунки та рухи коштів у пам'яті
+type MockAccountDBDetail struct {
+	accounts  map[int]models.Account
+	entries   map[int][]models.AccountEntry
+	transfers []models.Transfer
+}
+
+func newMockAccountDBDetail() *MockAccountDBDetail {
+	day := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	return &MockAccountDBDetail{
+		accounts: map[int]models.Account{
+			1: {ID: 1, UserID: 1, Name: "Cash", Type: models.AccountCash, InitialBalance: 100},
+			2: {ID: 2, UserID: 1, Name: "Savings", Type: models.AccountSavings},
+			3: {ID: 3, UserID: 2, Name: "Card", Type: models.AccountCard},
+		},
+		entries: map[int][]models.AccountEntry{
+			1: {
+				{Date: day, Kind: models.EntryExpense, ReferenceID: 1, Amount: -30},
+				{Date: day.AddDate(0, 0, 1), Kind: models.EntryIncome, ReferenceID: 2, Amount: 50},
+				{Date: day.AddDate(0, 0, 2), Kind: models.EntryTransferOut, ReferenceID: 1, Amount: -20},
+			},
+		},
+	}
+}
+
+func (db *MockAccountDBDetail) AddAccount(ctx context.Context, account models.Account) (int, error) {
+	account.ID = len(db.accounts) + 1
+	db.accounts[account.ID] = account
+	return account.ID, nil
+}
+
+func (db *MockAccountDBDetail) GetUserAccounts(ctx context.Context, userID int) ([]models.Account, error) {
+	var accounts []models.Account
+	for id := 1; id <= len(db.accounts); id++ {
+		if db.accounts[id].UserID == userID {
+			accounts = append(accounts, db.accounts[id])
+		}
+	}
+	return accounts, nil
+}
+
+// GetUserAccountBalances додає до початкового балансу суму рухів, як групування в запиті
+func (db *MockAccountDBDetail) GetUserAccountBalances(ctx context.Context, userID int) ([]models.Account, error) {
+	accounts, _ := db.GetUserAccounts(ctx, userID)
+	for i := range accounts {
+		accounts[i].Balance = accounts[i].InitialBalance
+		for _, entry := range db.entries[accounts[i].ID] {
+			accounts[i].Balance += entry.Amount
+		}
+	}
+	return accounts, nil
+}
+
+func (db *MockAccountDBDetail) GetAccountByID(ctx context.Context, accountID int) (models.Account, error) {
+	account, ok := db.accounts[accountID]
+	if !ok {
+		return models.Account{}, errors.New("not found")
+	}
+	return account, nil
+}
+
+func (db *MockAccountDBDetail) GetAccountEntries(ctx context.Context, accountID int) ([]models.AccountEntry, error) {
+	return append([]models.AccountEntry(nil), db.entries[accountID]...), nil
+}
+
+func (db *MockAccountDBDetail) AddTransfer(ctx context.Context, transfer models.Transfer) (int, error) {
+	db.transfers = append(db.transfers, transfer)
+	return len(db.transfers), nil
+}
+
+func (db *MockAccountDBDetail) GetUserTransfers(ctx context.Context, userID int) ([]models.Transfer, error) {
+	return db.transfers, nil
+}
+
+func TestAccountService_GetAccounts_CurrentBalance(t *testing.T) {
+	// Arrange
+	s := NewAccountService(newMockAccountDBDetail(), &MockUserDB{})
+
+	// Act
+	accounts, err := s.GetAccounts(context.Background(), testUser.ID)
+
+	// Assert
+	if err != nil {
+		t.Errorf("Received an error: received %v, expected %v", err, nil)
+	}
+
+	if len(accounts) != 2 || accounts[0].Balance != 100 {
+		t.Errorf("Received incorrect accounts: received %v", accounts)
+	}
+}
+
+func TestAccountService_GetBalance_Historical(t *testing.T) {
+	// Arrange
+	s := NewAccountService(newMockAccountDBDetail(), &MockUserDB{})
+	at := time.Date(2024, 3, 12, 0, 0, 0, 0, time.UTC)
+
+	// Act
+	account, err := s.GetBalance(context.Background(), testUser.ID, 1, at)
+
+	// Assert
+	if err != nil {
+		t.Errorf("Received an error: received %v, expected %v", err, nil)
+	}
+
+	// 100 - 30 + 50, переказ 12 березня ще не врахований
+	if account.Balance != 120 {
+		t.Errorf("Received incorrect balance: received %v, expected %v", account.Balance, 120)
+	}
+}
+
+func TestAccountService_GetBalance_ForeignAccount(t *testing.T) {
+	// Arrange
+	s := NewAccountService(newMockAccountDBDetail(), &MockUserDB{})
+
+	// Act
+	_, err := s.GetBalance(context.Background(), testUser.ID, 3, time.Time{})
+
+	// Assert
+	expectedError := "account not found"
+	if err == nil || err.Error() != expectedError {
+		t.Errorf("Received incorrect error: received %v, expected %v", err, expectedError)
+	}
+}
+
+func TestAccountService_CreateTransfer(t *testing.T) {
+	// Arrange
+	accountDB := newMockAccountDBDetail()
+	s := NewAccountService(accountDB, &MockUserDB{})
+
+	// Act
+	transfer, err := s.CreateTransfer(context.Background(), testUser.ID, models.Transfer{FromAccountID: 1, ToAccountID: 2, Amount: 20})
+
+	// Assert
+	if err != nil {
+		t.Errorf("Received an error: received %v, expected %v", err, nil)
+	}
+
+	if transfer.UserID != testUser.ID || transfer.Date.IsZero() || len(accountDB.transfers) != 1 {
+		t.Errorf("Received incorrect transfer: received %v", transfer)
+	}
+}
+
+func TestAccountService_CreateTransfer_ForeignAccount(t *testing.T) {
+	// Arrange
+	s := NewAccountService(newMockAccountDBDetail(), &MockUserDB{})
+
+	// Act
+	_, err := s.CreateTransfer(context.Background(), testUser.ID, models.Transfer{FromAccountID: 1, ToAccountID: 3, Amount: 20})
+
+	// Assert
+	expectedError := "account not found"
+	if err == nil || err.Error() != expectedError {
+		t.Errorf("Received incorrect error: received %v, expected %v", err, expectedError)
+	}
+}
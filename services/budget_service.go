@@ -0,0 +1,67 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/ChomuCake/uni-golang-labs/models"
+)
+
+type BudgetDB interface {
+	SetBudget(ctx context.Context, budget models.Budget) error
+	GetUserBudgets(ctx context.Context, userID int) ([]models.Budget, error)
+	DeleteBudget(ctx context.Context, userID int, category string) error
+}
+
+type BudgetService struct {
+	budgetDB BudgetDB
+}
+
+func NewBudgetService(budgetDB BudgetDB) *BudgetService {
+	return &BudgetService{budgetDB}
+}
+
+// SetBudget задає місячний бюджет категорії; повторний виклик для тієї ж категорії змінює суму
+func (s *BudgetService) SetBudget(ctx context.Context, userID int, budget models.Budget) (models.Budget, error) {
+	budget.Category = strings.TrimSpace(budget.Category)
+	budget.UserID = userID
+
+	err := validateBudget(budget)
+	if err != nil {
+		return models.Budget{}, err
+	}
+
+	err = s.budgetDB.SetBudget(ctx, budget)
+	if err != nil {
+		return models.Budget{}, internalError("budget_save_failed", "failed to save budget", err)
+	}
+
+	return budget, nil
+}
+
+func (s *BudgetService) GetBudgets(ctx context.Context, userID int) ([]models.Budget, error) {
+	budgets, err := s.budgetDB.GetUserBudgets(ctx, userID)
+	if err != nil {
+		return nil, internalError("budgets_fetch_failed", "failed to get budgets", err)
+	}
+
+	if budgets == nil {
+		budgets = []models.Budget{}
+	}
+
+	return budgets, nil
+}
+
+func (s *BudgetService) DeleteBudget(ctx context.Context, userID int, category string) error {
+	err := s.budgetDB.DeleteBudget(ctx, userID, category)
+	if errors.Is(err, sql.ErrNoRows) {
+		return errBudgetNotFound
+	}
+	if err != nil {
+		return internalError("budget_delete_failed", "failed to delete budget", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,52 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ChomuCake/uni-golang-labs/models"
+)
+
+func TestBudgetService_SetBudget_Upsert(t *testing.T) {
+	// Arrange
+	budgetDB := &MockBudgetDB{}
+	s := NewBudgetService(budgetDB)
+
+	// Act
+	_, err := s.SetBudget(context.Background(), testUser.ID, models.Budget{Category: " Food ", Amount: 100})
+	if err != nil {
+		t.Fatalf("Received an error: received %v, expected %v", err, nil)
+	}
+	_, err = s.SetBudget(context.Background(), testUser.ID, models.Budget{Category: "Food", Amount: 200})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Received an error: received %v, expected %v", err, nil)
+	}
+	budgets, _ := s.GetBudgets(context.Background(), testUser.ID)
+	if len(budgets) != 1 || budgets[0].Amount != 200 {
+		t.Errorf("Received incorrect budgets: received %+v, expected one Food budget of 200", budgets)
+	}
+}
+
+func TestBudgetService_SetBudget_Validation(t *testing.T) {
+	s := NewBudgetService(&MockBudgetDB{})
+
+	_, err := s.SetBudget(context.Background(), testUser.ID, models.Budget{Category: "", Amount: 0})
+
+	var serviceErr *Error
+	if !errors.As(err, &serviceErr) || len(serviceErr.Fields) != 2 {
+		t.Errorf("Received incorrect error: received %v, expected amount and category violations", err)
+	}
+}
+
+func TestBudgetService_DeleteBudget_NotFound(t *testing.T) {
+	s := NewBudgetService(&MockBudgetDB{})
+
+	err := s.DeleteBudget(context.Background(), testUser.ID, "Food")
+
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("Received incorrect error: received %v, expected %v", err, ErrNotFound)
+	}
+}
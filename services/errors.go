@@ -0,0 +1,83 @@
+package services
+
+import (
+	"errors"
+	"time"
+)
+
+// Категорії помилок сервісного шару. Обробники перевіряють їх через errors.Is
+// і відображають на HTTP-статуси, не розбираючи текст повідомлення
+var (
+	ErrInvalid      = errors.New("invalid input")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrForbidden    = errors.New("forbidden")
+	ErrNotFound     = errors.New("not found")
+	ErrConflict     = errors.New("conflict")
+	ErrTooMany      = errors.New("too many requests")
+	ErrInternal     = errors.New("internal error")
+)
+
+// FieldError описує проблему з конкретним полем вхідних даних
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Error - помилка предметної області з машиночитним кодом (наприклад, "ledger_not_found")
+type Error struct {
+	Kind    error // одна з категорій ErrInvalid, ErrNotFound, ...
+	Code    string
+	Message string
+	Fields  []FieldError
+	Err     error // першопричина (помилка бази даних тощо), не показується клієнту
+
+	RetryAfter time.Duration // для ErrTooMany - через скільки можна повторити запит
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Is(target error) bool {
+	return target == e.Kind
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func newError(kind error, code, message string) *Error {
+	return &Error{Kind: kind, Code: code, Message: message}
+}
+
+func internalError(code, message string, err error) *Error {
+	return &Error{Kind: ErrInternal, Code: code, Message: message, Err: err}
+}
+
+// Помилки, що повторюються в кількох сервісах
+var (
+	errUserNotFound          = newError(ErrNotFound, "user_not_found", "user not found")
+	errLedgerNotFound        = newError(ErrNotFound, "ledger_not_found", "ledger not found")
+	errInsufficientRole      = newError(ErrForbidden, "insufficient_ledger_role", "insufficient ledger role")
+	errExpenseNotFound       = newError(ErrNotFound, "expense_not_found", "expense not found")
+	errAccountNotFound       = newError(ErrNotFound, "account_not_found", "account not found")
+	errInvalidSplitMember    = newError(ErrInvalid, "invalid_split_participant", "invalid split participant")
+	errNegativeSplitValue    = newError(ErrInvalid, "negative_split_value", "split value can't be negative")
+	errInvalidLedgerRole     = newError(ErrInvalid, "invalid_ledger_role", "invalid ledger role")
+	errNotSettlementParty    = newError(ErrForbidden, "not_settlement_party", "only the payer, the payee or the ledger owner can record a settlement")
+	errBudgetNotFound        = newError(ErrNotFound, "budget_not_found", "budget not found")
+	errInvalidCredentials    = newError(ErrUnauthorized, "invalid_credentials", "invalid username or password")
+	errUsernameAlreadyExists = newError(ErrConflict, "username_taken", "user with such name is already exists")
+	errEmailAlreadyExists    = newError(ErrConflict, "email_taken", "user with such email is already exists")
+	errInvalidResetToken     = newError(ErrInvalid, "invalid_reset_token", "password reset token is invalid or expired")
+	errInvalidPassword       = newError(ErrForbidden, "invalid_current_password", "current password is incorrect")
+	errInvalidTwoFactorCode  = newError(ErrUnauthorized, "invalid_2fa_code", "two-factor code is invalid")
+	errIncorrectTwoFactor    = newError(ErrInvalid, "invalid_2fa_code", "two-factor code is invalid")
+	errTwoFactorEnabled      = newError(ErrConflict, "2fa_already_enabled", "two-factor authentication is already enabled")
+	errTwoFactorNotEnabled   = newError(ErrConflict, "2fa_not_enabled", "two-factor authentication isn't enabled")
+	errTwoFactorNotSetUp     = newError(ErrConflict, "2fa_not_set_up", "two-factor authentication isn't set up")
+	errAccessTokenNotFound   = newError(ErrNotFound, "access_token_not_found", "access token not found")
+	errInvalidAccessToken    = newError(ErrUnauthorized, "invalid_token", "access token is invalid or expired")
+	errAccessTokenNotAllowed = newError(ErrForbidden, "access_token_not_allowed", "personal access tokens can't be used for this operation")
+)
@@ -1,8 +1,9 @@
 package services
 
 import (
-	"errors"
+	"context"
 	"sort"
+	"strconv"
 	"time"
 
 	"github.com/ChomuCake/uni-golang-labs/models"
@@ -15,83 +16,267 @@ func (a ByDate) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
 func (a ByDate) Less(i, j int) bool { return a[i].Date.Before(a[j].Date) }
 
 type ExpenseDB interface {
-	GetUserExpenses(userID int) ([]models.Expense, error)
-	AddExpense(expense models.Expense) error
-	DeleteExpense(expenseID string) error
-	UpdateUserExpenses(expense models.Expense) error
+	GetUserExpenses(ctx context.Context, userID int) ([]models.Expense, error)
+	GetLedgerExpenses(ctx context.Context, ledgerID int) ([]models.Expense, error)
+	GetExpenseByID(ctx context.Context, expenseID string) (models.Expense, error)
+	AddExpense(ctx context.Context, expense models.Expense) error
+	DeleteExpense(ctx context.Context, expenseID string) error
+	UpdateUserExpenses(ctx context.Context, expense models.Expense) error
 }
 
 type UserDB interface {
-	GetUserByID(userID int) (models.User, error)
+	GetUserByID(ctx context.Context, userID int) (models.User, error)
+}
+
+type LedgerDB interface {
+	GetMemberRole(ctx context.Context, ledgerID, userID int) (string, error)
+	GetLedgerMembers(ctx context.Context, ledgerID int) ([]models.LedgerMember, error)
+}
+
+// ExpenseMetrics рахує події з витратами (реалізується пакетом metrics)
+type ExpenseMetrics interface {
+	ExpenseCreated()
 }
 
 type ExpenseService struct {
 	expenseDB ExpenseDB
 	userDB    UserDB
+	ledgerDB  LedgerDB
+	accountDB AccountDB
+	metrics   ExpenseMetrics
 }
 
-func NewExpenseService(expenseDB ExpenseDB, userDB UserDB) *ExpenseService {
-	return &ExpenseService{expenseDB, userDB}
+func NewExpenseService(expenseDB ExpenseDB, userDB UserDB, ledgerDB LedgerDB, accountDB AccountDB) *ExpenseService {
+	return &ExpenseService{expenseDB: expenseDB, userDB: userDB, ledgerDB: ledgerDB, accountDB: accountDB}
 }
 
-func (s *ExpenseService) CreateExpense(userID int, expense models.Expense) error {
+// SetMetrics вмикає облік створених витрат; без нього сервіс працює без метрик
+func (s *ExpenseService) SetMetrics(metrics ExpenseMetrics) {
+	s.metrics = metrics
+}
+
+// authorize перевіряє доступ користувача до журналу: ledgerID 0 означає особисті витрати,
+// інакше переглядати можуть усі учасники, а змінювати - лише owner та editor
+func (s *ExpenseService) authorize(ctx context.Context, userID, ledgerID int, write bool) (models.User, error) {
 	// Перевірка, чи користувач існує
-	_, err := s.userDB.GetUserByID(userID)
+	user, err := s.userDB.GetUserByID(ctx, userID)
 	if err != nil {
-		return errors.New("user not found")
+		return models.User{}, errUserNotFound
 	}
 
-	// Парсинг рядкового значення дати
-	expense.Date = time.Now()
-	expense.UserID = userID
+	if ledgerID == 0 {
+		return user, nil
+	}
 
-	// Створення витрати
-	err = s.expenseDB.AddExpense(expense)
+	role, err := s.ledgerDB.GetMemberRole(ctx, ledgerID, userID)
 	if err != nil {
-		return errors.New("failed to create expense")
+		return models.User{}, errLedgerNotFound
+	}
+
+	if write && role == models.RoleViewer {
+		return models.User{}, errInsufficientRole
 	}
 
+	return user, nil
+}
+
+// findExpense повертає витрату, лише якщо вона належить вибраному журналу (або особистим витратам користувача)
+func (s *ExpenseService) findExpense(ctx context.Context, userID, ledgerID int, expenseID string) (models.Expense, error) {
+	expense, err := s.expenseDB.GetExpenseByID(ctx, expenseID)
+	if err != nil {
+		return models.Expense{}, errExpenseNotFound
+	}
+
+	if expense.LedgerID != ledgerID || (ledgerID == 0 && expense.UserID != userID) {
+		return models.Expense{}, errExpenseNotFound
+	}
+
+	return expense, nil
+}
+
+// normalizeKind - запис без типу вважається витратою
+func normalizeKind(expense *models.Expense) error {
+	if expense.Kind == "" {
+		expense.Kind = models.KindExpense
+	}
+	if expense.Kind != models.KindExpense && expense.Kind != models.KindIncome {
+		return newError(ErrInvalid, "invalid_expense_kind", "invalid expense kind")
+	}
 	return nil
 }
 
-func (s *ExpenseService) GetExpenses(userID int, sortExpensesBy string) ([]models.Expense, error) {
-	// Перевірка, чи користувач існує
-	_, err := s.userDB.GetUserByID(userID)
+// accountOwner - спільна витрата списується з рахунку платника, бо саме він заплатив і саме
+// його борг зменшує розподіл; особисті записи і доходи належать автору
+func accountOwner(expense models.Expense) int {
+	if expense.LedgerID != 0 && expense.Kind != models.KindIncome {
+		return expense.PaidBy
+	}
+	return expense.UserID
+}
+
+// resolveAccount прив'язує запис до рахунку власника (див. accountOwner): якщо рахунок не вказано,
+// використовується перший рахунок власника (за відсутності рахунків створюється готівковий "Cash").
+// Вказати рахунок явно може лише сам власник - чужим рахунком розпоряджатися не можна
+func (s *ExpenseService) resolveAccount(ctx context.Context, callerID int, expense *models.Expense) error {
+	err := normalizeKind(expense)
+	if err != nil {
+		return err
+	}
+
+	ownerID := accountOwner(*expense)
+	if expense.AccountID != 0 {
+		if ownerID != callerID {
+			return newError(ErrInvalid, "invalid_account", "account can be set only by the payer")
+		}
+
+		account, err := s.accountDB.GetAccountByID(ctx, expense.AccountID)
+		if err != nil || account.UserID != ownerID {
+			return errAccountNotFound
+		}
+		return nil
+	}
+
+	accounts, err := s.accountDB.GetUserAccounts(ctx, ownerID)
 	if err != nil {
-		return nil, errors.New("user not found")
+		return internalError("accounts_fetch_failed", "failed to get accounts", err)
+	}
+
+	if len(accounts) > 0 {
+		expense.AccountID = accounts[0].ID
+		return nil
 	}
 
-	userExpenses, err := s.expenseDB.GetUserExpenses(userID)
+	expense.AccountID, err = s.accountDB.AddAccount(ctx, models.Account{UserID: ownerID, Name: "Cash", Type: models.AccountCash})
 	if err != nil {
-		return nil, errors.New("failed to get user expenses")
+		return internalError("account_create_failed", "failed to create account", err)
 	}
 
-	switch sortExpensesBy {
-	case "day":
-		today := time.Now().Truncate(24 * time.Hour) // Отримуємо поточну дату без часу
-		var todayExpenses []models.Expense
+	return nil
+}
 
-		// Фільтруємо витрати за сьогоднішній день
-		for _, expense := range userExpenses {
-			if expense.Date.Year() == today.Year() &&
-				expense.Date.Month() == today.Month() &&
-				expense.Date.Day() == today.Day() {
-				todayExpenses = append(todayExpenses, expense)
-			}
+// splitLedgerExpense обчислює частки учасників для витрати у журналі;
+// особисті витрати і доходи не мають ні платника, ні розподілу, тож не впливають на борги
+func (s *ExpenseService) splitLedgerExpense(ctx context.Context, expense *models.Expense) error {
+	if expense.LedgerID == 0 || expense.Kind == models.KindIncome {
+		expense.PaidBy = 0
+		expense.SplitMethod = ""
+		expense.Splits = nil
+		return nil
+	}
+
+	members, err := s.ledgerDB.GetLedgerMembers(ctx, expense.LedgerID)
+	if err != nil {
+		return internalError("ledger_members_fetch_failed", "failed to get ledger members", err)
+	}
+
+	memberIDs := make([]int, 0, len(members))
+	payerIsMember := false
+	for _, member := range members {
+		memberIDs = append(memberIDs, member.UserID)
+		if member.UserID == expense.PaidBy {
+			payerIsMember = true
 		}
-		userExpenses = todayExpenses
+	}
+	if !payerIsMember {
+		return newError(ErrInvalid, "invalid_payer", "payer isn't a ledger member")
+	}
+
+	if expense.SplitMethod == "" {
+		expense.SplitMethod = models.SplitEqual
+	}
+
+	expense.Splits, err = splitExpense(expense.Amount, expense.SplitMethod, expense.Splits, memberIDs)
+	if err != nil {
+		return err
+	}
 
-	case "month":
-		month := time.Now().Month() // Поточний місяць
-		var monthExpenses []models.Expense
+	return nil
+}
 
-		// Фільтруємо витрати за поточний місяць
+func (s *ExpenseService) CreateExpense(ctx context.Context, userID, ledgerID int, expense models.Expense) (err error) {
+	ctx, end := startSpan(ctx, "ExpenseService.CreateExpense")
+	defer end(&err)
+
+	user, err := s.authorize(ctx, userID, ledgerID, true)
+	if err != nil {
+		return err
+	}
+
+	// Якщо дату не вказано, витрата фіксується поточним моментом;
+	// дата без часу означає початок дня в часовому поясі користувача
+	if expense.Date.IsZero() {
+		expense.Date = time.Now()
+	} else if expense.DateOnly {
+		expense.Date = startOfDay(expense.Date, userLocation(user))
+	}
+
+	err = validateExpense(expense)
+	if err != nil {
+		return err
+	}
+
+	expense.UserID = userID
+	expense.LedgerID = ledgerID
+	if expense.PaidBy == 0 {
+		expense.PaidBy = userID
+	}
+
+	err = s.resolveAccount(ctx, userID, &expense)
+	if err != nil {
+		return err
+	}
+
+	// Розподіл спільної витрати між учасниками журналу
+	err = s.splitLedgerExpense(ctx, &expense)
+	if err != nil {
+		return err
+	}
+
+	// Створення витрати
+	err = s.expenseDB.AddExpense(ctx, expense)
+	if err != nil {
+		return internalError("expense_create_failed", "failed to create expense", err)
+	}
+
+	if s.metrics != nil {
+		s.metrics.ExpenseCreated()
+	}
+
+	return nil
+}
+
+func (s *ExpenseService) GetExpenses(ctx context.Context, userID, ledgerID int, sortExpensesBy string) (_ []models.Expense, err error) {
+	ctx, end := startSpan(ctx, "ExpenseService.GetExpenses")
+	defer end(&err)
+
+	user, err := s.authorize(ctx, userID, ledgerID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	var userExpenses []models.Expense
+	if ledgerID == 0 {
+		userExpenses, err = s.expenseDB.GetUserExpenses(ctx, userID)
+	} else {
+		userExpenses, err = s.expenseDB.GetLedgerExpenses(ctx, ledgerID)
+	}
+	if err != nil {
+		return nil, internalError("expenses_fetch_failed", "failed to get user expenses", err)
+	}
+
+	switch sortExpensesBy {
+	case PeriodDay, PeriodWeek, PeriodMonth, PeriodYear:
+		// Межі поточного дня/тижня/місяця/року рахуються в часовому поясі користувача
+		start, end, _ := periodBounds(sortExpensesBy, time.Now().In(userLocation(user)))
+		var periodExpenses []models.Expense
+
+		// Фільтруємо витрати за поточний період
 		for _, expense := range userExpenses {
-			if expense.Date.Month() == month {
-				monthExpenses = append(monthExpenses, expense)
+			if !expense.Date.Before(start) && expense.Date.Before(end) {
+				periodExpenses = append(periodExpenses, expense)
 			}
 		}
-		userExpenses = monthExpenses
+		userExpenses = periodExpenses
 
 	case "all":
 		sort.SliceStable(userExpenses, func(i, j int) bool {
@@ -99,7 +284,7 @@ func (s *ExpenseService) GetExpenses(userID int, sortExpensesBy string) ([]model
 		})
 	default:
 		if sortExpensesBy != "" {
-			return nil, errors.New("not correct sort parameter SortBy")
+			return nil, newError(ErrInvalid, "invalid_sort", "not correct sort parameter SortBy")
 		}
 
 		sort.SliceStable(userExpenses, func(i, j int) bool {
@@ -114,38 +299,89 @@ func (s *ExpenseService) GetExpenses(userID int, sortExpensesBy string) ([]model
 	return userExpenses, nil
 }
 
-func (s *ExpenseService) UpdateExpense(userID int, updatedExpense models.Expense) error {
-	// Перевірка, чи користувач існує
-	_, err := s.userDB.GetUserByID(userID)
+func (s *ExpenseService) UpdateExpense(ctx context.Context, userID, ledgerID int, updatedExpense models.Expense) (err error) {
+	ctx, end := startSpan(ctx, "ExpenseService.UpdateExpense")
+	defer end(&err)
+
+	user, err := s.authorize(ctx, userID, ledgerID, true)
+	if err != nil {
+		return err
+	}
+
+	existingExpense, err := s.findExpense(ctx, userID, ledgerID, strconv.Itoa(updatedExpense.ID))
+	if err != nil {
+		return err
+	}
+
+	// Якщо дату не вказано, вона залишається без змін
+	if updatedExpense.Date.IsZero() {
+		updatedExpense.Date = existingExpense.Date
+	} else if updatedExpense.DateOnly {
+		updatedExpense.Date = startOfDay(updatedExpense.Date, userLocation(user))
+	}
+
+	err = validateExpense(updatedExpense)
+	if err != nil {
+		return err
+	}
+
+	// Не вказані тип запису і платник залишаються без змін
+	updatedExpense.UserID = existingExpense.UserID
+	updatedExpense.LedgerID = ledgerID
+	if updatedExpense.Kind == "" {
+		updatedExpense.Kind = existingExpense.Kind
+	}
+	if updatedExpense.PaidBy == 0 {
+		updatedExpense.PaidBy = existingExpense.PaidBy
+	}
+	if updatedExpense.PaidBy == 0 {
+		updatedExpense.PaidBy = userID
+	}
+
+	// Не вказаний рахунок теж лишається, поки запис списується з рахунку того самого власника;
+	// зі зміною платника витрата переходить на рахунок нового платника
+	if updatedExpense.AccountID == 0 && accountOwner(updatedExpense) == accountOwner(existingExpense) {
+		updatedExpense.AccountID = existingExpense.AccountID
+		err = normalizeKind(&updatedExpense)
+	} else {
+		err = s.resolveAccount(ctx, userID, &updatedExpense)
+	}
 	if err != nil {
-		return errors.New("user not found")
+		return err
 	}
 
-	// Парсинг рядкового значення дати
-	updatedExpense.Date, err = time.Parse("2006-01-02", updatedExpense.RawDate)
+	// Після зміни суми частки перераховуються (за замовчуванням - порівну)
+	err = s.splitLedgerExpense(ctx, &updatedExpense)
 	if err != nil {
-		return errors.New("failed to parse date expense")
+		return err
 	}
 
 	// Оновлення витрати
-	err = s.expenseDB.UpdateUserExpenses(updatedExpense)
+	err = s.expenseDB.UpdateUserExpenses(ctx, updatedExpense)
 	if err != nil {
-		return errors.New("failed to update expense")
+		return internalError("expense_update_failed", "failed to update expense", err)
 	}
 
 	return nil
 }
 
-func (s *ExpenseService) DeleteExpense(userID int, expenseID string) error {
-	// Перевірка, чи користувач існує
-	_, err := s.userDB.GetUserByID(userID)
+func (s *ExpenseService) DeleteExpense(ctx context.Context, userID, ledgerID int, expenseID string) (err error) {
+	ctx, end := startSpan(ctx, "ExpenseService.DeleteExpense")
+	defer end(&err)
+
+	_, err = s.authorize(ctx, userID, ledgerID, true)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.findExpense(ctx, userID, ledgerID, expenseID)
 	if err != nil {
-		return errors.New("user not found")
+		return err
 	}
 
-	err = s.expenseDB.DeleteExpense(expenseID)
+	err = s.expenseDB.DeleteExpense(ctx, expenseID)
 	if err != nil {
-		return errors.New("failed to delete expense")
+		return internalError("expense_delete_failed", "failed to delete expense", err)
 	}
 
 	return nil
@@ -1,8 +1,10 @@
 package services
 
 import (
+	"context"
 	// only for sql.ErrNoRows
 	"errors"
+	"strconv"
 	"testing"
 	"time"
 
@@ -21,19 +23,38 @@ func ResetMockDB() {
 type MockExpenseDB struct {
 }
 
-func (db *MockExpenseDB) AddExpense(expense models.Expense) error {
+func (db *MockExpenseDB) AddExpense(ctx context.Context, expense models.Expense) error {
 	expensesBD = append(expensesBD, expense)
 	return nil
 }
 
-func (db *MockExpenseDB) GetUserExpenses(userID int) ([]models.Expense, error) {
+func (db *MockExpenseDB) GetUserExpenses(ctx context.Context, userID int) ([]models.Expense, error) {
 	if userID == 1 {
 		return expectedExpenses, nil
 	}
 	return []models.Expense{}, nil
 }
 
-func (db *MockExpenseDB) UpdateUserExpenses(expense models.Expense) error {
+func (db *MockExpenseDB) GetLedgerExpenses(ctx context.Context, ledgerID int) ([]models.Expense, error) {
+	var ledgerExpenses []models.Expense
+	for _, expense := range expensesBD {
+		if expense.LedgerID == ledgerID {
+			ledgerExpenses = append(ledgerExpenses, expense)
+		}
+	}
+	return ledgerExpenses, nil
+}
+
+func (db *MockExpenseDB) GetExpenseByID(ctx context.Context, expenseID string) (models.Expense, error) {
+	for _, expense := range append(expensesBD, expectedExpenses...) {
+		if strconv.Itoa(expense.ID) == expenseID {
+			return expense, nil
+		}
+	}
+	return models.Expense{}, errors.New("not found")
+}
+
+func (db *MockExpenseDB) UpdateUserExpenses(ctx context.Context, expense models.Expense) error {
 	if expense.UserID == 2 {
 		return errors.New("server error")
 	}
@@ -45,10 +66,12 @@ func removeElement(slice []models.Expense, index int) []models.Expense {
 	return append(slice[:index], slice[index+1:]...)
 }
 
-func (db *MockExpenseDB) DeleteExpense(expenseID string) error {
-	if expenseID == "0" {
-		expensesBD = removeElement(expensesBD, 0)
-		return nil
+func (db *MockExpenseDB) DeleteExpense(ctx context.Context, expenseID string) error {
+	for i, expense := range expensesBD {
+		if strconv.Itoa(expense.ID) == expenseID {
+			expensesBD = removeElement(expensesBD, i)
+			return nil
+		}
 	}
 
 	return errors.New("server error")
@@ -57,14 +80,62 @@ func (db *MockExpenseDB) DeleteExpense(expenseID string) error {
 // MockUserDB є замінником реалізації UserDB
 type MockUserDB struct{}
 
-func (db *MockUserDB) GetUserByID(userID int) (models.User, error) {
-	if userID == 1 {
+func (db *MockUserDB) GetUserByID(ctx context.Context, userID int) (models.User, error) {
+	switch userID {
+	case 1:
 		return models.User{ID: 1, Username: "John Doe"}, nil
+	case 2:
+		return models.User{ID: 2, Username: "Jane Doe"}, nil
 	}
 	return models.User{}, errors.New("server error")
 
 }
 
+// MockLedgerDB є замінником реалізації LedgerDB: користувач 1 - власник журналу 1,
+// користувач 2 - лише глядач
+type MockLedgerDB struct{}
+
+func (db *MockLedgerDB) GetMemberRole(ctx context.Context, ledgerID, userID int) (string, error) {
+	if ledgerID != 1 {
+		return "", errors.New("not found")
+	}
+	switch userID {
+	case 1:
+		return models.RoleOwner, nil
+	case 2:
+		return models.RoleViewer, nil
+	}
+	return "", errors.New("not found")
+}
+
+func (db *MockLedgerDB) GetLedgerMembers(ctx context.Context, ledgerID int) ([]models.LedgerMember, error) {
+	return []models.LedgerMember{
+		{LedgerID: ledgerID, UserID: 1, Role: models.RoleOwner},
+		{LedgerID: ledgerID, UserID: 2, Role: models.RoleViewer},
+	}, nil
+}
+
+// MockAccountDB є замінником реалізації AccountDB: рахунок 1 належить користувачу 1
+type MockAccountDB struct{}
+
+func (db *MockAccountDB) AddAccount(ctx context.Context, account models.Account) (int, error) {
+	return 2, nil
+}
+
+func (db *MockAccountDB) GetUserAccounts(ctx context.Context, userID int) ([]models.Account, error) {
+	if userID == 1 {
+		return []models.Account{{ID: 1, UserID: 1, Name: "Cash", Type: models.AccountCash}}, nil
+	}
+	return nil, nil
+}
+
+func (db *MockAccountDB) GetAccountByID(ctx context.Context, accountID int) (models.Account, error) {
+	if accountID == 1 {
+		return models.Account{ID: 1, UserID: 1, Name: "Cash", Type: models.AccountCash}, nil
+	}
+	return models.Account{}, errors.New("not found")
+}
+
 var expectedExpenses = []models.Expense{
 	{ID: 1, Amount: 10, Date: time.Now(), Category: "test", UserID: 1},
 	{ID: 2, Amount: 20, Date: time.Now(), Category: "test", UserID: 1},
@@ -75,16 +146,16 @@ var expectedExpenses = []models.Expense{
 var testUser = models.User{
 	ID:       1,
 	Username: "Test",
-	Password: "12345",
+	Password: "secret123",
 }
 
 func TestExpensesHandler_CreateExpense(t *testing.T) {
 	// Arrange
-	s := NewExpenseService(&MockExpenseDB{}, &MockUserDB{})
+	s := NewExpenseService(&MockExpenseDB{}, &MockUserDB{}, &MockLedgerDB{}, &MockAccountDB{})
 	ResetMockDB()
 
 	// Act
-	err := s.CreateExpense(testUser.ID, expectedExpenses[0])
+	err := s.CreateExpense(context.Background(), testUser.ID, 0, expectedExpenses[0])
 
 	// Assert
 	if err != nil {
@@ -103,11 +174,11 @@ func TestExpenseService_CreateExpense(t *testing.T) {
 	// Arrange
 	mockExpenseDB := &MockExpenseDB{}
 	mockUserDB := &MockUserDB{}
-	s := NewExpenseService(mockExpenseDB, mockUserDB)
+	s := NewExpenseService(mockExpenseDB, mockUserDB, &MockLedgerDB{}, &MockAccountDB{})
 	ResetMockDB()
 
 	// Act
-	err := s.CreateExpense(testUser.ID, expectedExpenses[0])
+	err := s.CreateExpense(context.Background(), testUser.ID, 0, expectedExpenses[0])
 
 	// Assert
 	if err != nil {
@@ -123,11 +194,11 @@ func TestExpenseService_GetExpenses_SortByDay(t *testing.T) {
 	// Arrange
 	mockExpenseDB := &MockExpenseDB{}
 	mockUserDB := &MockUserDB{}
-	s := NewExpenseService(mockExpenseDB, mockUserDB)
+	s := NewExpenseService(mockExpenseDB, mockUserDB, &MockLedgerDB{}, &MockAccountDB{})
 	ResetMockDB()
 
 	// Act
-	expenses, err := s.GetExpenses(testUser.ID, "day")
+	expenses, err := s.GetExpenses(context.Background(), testUser.ID, 0, "day")
 
 	// Assert
 	if err != nil {
@@ -144,11 +215,11 @@ func TestExpenseService_GetExpenses_SortByMonth(t *testing.T) {
 	// Arrange
 	mockExpenseDB := &MockExpenseDB{}
 	mockUserDB := &MockUserDB{}
-	s := NewExpenseService(mockExpenseDB, mockUserDB)
+	s := NewExpenseService(mockExpenseDB, mockUserDB, &MockLedgerDB{}, &MockAccountDB{})
 	ResetMockDB()
 
 	// Act
-	expenses, err := s.GetExpenses(testUser.ID, "month")
+	expenses, err := s.GetExpenses(context.Background(), testUser.ID, 0, "month")
 
 	// Assert
 	if err != nil {
@@ -165,11 +236,11 @@ func TestExpenseService_GetExpenses_SortByAll(t *testing.T) {
 	// Arrange
 	mockExpenseDB := &MockExpenseDB{}
 	mockUserDB := &MockUserDB{}
-	s := NewExpenseService(mockExpenseDB, mockUserDB)
+	s := NewExpenseService(mockExpenseDB, mockUserDB, &MockLedgerDB{}, &MockAccountDB{})
 	ResetMockDB()
 
 	// Act
-	expenses, err := s.GetExpenses(testUser.ID, "all")
+	expenses, err := s.GetExpenses(context.Background(), testUser.ID, 0, "all")
 
 	// Assert
 	if err != nil {
@@ -186,11 +257,11 @@ func TestExpenseService_GetExpenses_SortByInvalid(t *testing.T) {
 	// Arrange
 	mockExpenseDB := &MockExpenseDB{}
 	mockUserDB := &MockUserDB{}
-	s := NewExpenseService(mockExpenseDB, mockUserDB)
+	s := NewExpenseService(mockExpenseDB, mockUserDB, &MockLedgerDB{}, &MockAccountDB{})
 	ResetMockDB()
 
 	// Act
-	_, err := s.GetExpenses(testUser.ID, "invalid")
+	_, err := s.GetExpenses(context.Background(), testUser.ID, 0, "invalid")
 
 	// Assert
 	expectedError := "not correct sort parameter SortBy"
@@ -203,30 +274,74 @@ func TestExpenseService_UpdateExpense(t *testing.T) {
 	// Arrange
 	mockExpenseDB := &MockExpenseDB{}
 	mockUserDB := &MockUserDB{}
-	s := NewExpenseService(mockExpenseDB, mockUserDB)
+	s := NewExpenseService(mockExpenseDB, mockUserDB, &MockLedgerDB{}, &MockAccountDB{})
 	ResetMockDB()
 	ExpenseRaw := expectedExpenses[1]
-	ExpenseRaw.RawDate = time.Now().Format("2006-01-02")
+	ExpenseRaw.Date, _ = models.ParseDate(time.Now().Format(models.DateLayout))
 	ExpectedExpense := expectedExpenses[1]
-	ExpectedExpense.Date, _ = time.Parse("2006-01-02", ExpenseRaw.RawDate)
+	ExpectedExpense.Date = ExpenseRaw.Date
+	// Act
+	err := s.UpdateExpense(context.Background(), testUser.ID, 0, ExpenseRaw)
+
+	// Assert
+	if err != nil {
+		t.Errorf("Received an error: received %v, expected %v", err, nil)
+	}
+
+	if !expensesBD[0].Date.Equal(ExpectedExpense.Date) {
+		t.Errorf("Received incorrect date: received %v, expected %v", expensesBD[0].Date, ExpectedExpense.Date)
+	}
+}
+
+func TestExpenseService_UpdateExpense_KeepsDateWhenOmitted(t *testing.T) {
+	// Arrange
+	s := NewExpenseService(&MockExpenseDB{}, &MockUserDB{}, &MockLedgerDB{}, &MockAccountDB{})
+	ResetMockDB()
+	originalDate := expensesBD[0].Date
+	expense := expensesBD[0]
+	expense.Date = time.Time{}
+
+	// Act
+	err := s.UpdateExpense(context.Background(), testUser.ID, 0, expense)
+
+	// Assert
+	if err != nil {
+		t.Errorf("Received an error: received %v, expected %v", err, nil)
+	}
+
+	if !expensesBD[0].Date.Equal(originalDate) {
+		t.Errorf("Received incorrect date: received %v, expected %v", expensesBD[0].Date, originalDate)
+	}
+}
+
+func TestExpenseService_CreateExpense_WithDate(t *testing.T) {
+	// Arrange
+	s := NewExpenseService(&MockExpenseDB{}, &MockUserDB{}, &MockLedgerDB{}, &MockAccountDB{})
+	ResetMockDB()
+	yesterday := time.Now().AddDate(0, 0, -1).Truncate(time.Second)
+
 	// Act
-	err := s.UpdateExpense(testUser.ID, ExpenseRaw)
+	err := s.CreateExpense(context.Background(), testUser.ID, 0, models.Expense{Amount: 5, Category: "coffee", Date: yesterday})
 
 	// Assert
 	if err != nil {
 		t.Errorf("Received an error: received %v, expected %v", err, nil)
 	}
+
+	if created := expensesBD[len(expensesBD)-1]; !created.Date.Equal(yesterday) {
+		t.Errorf("Received incorrect date: received %v, expected %v", created.Date, yesterday)
+	}
 }
 
 func TestExpenseService_DeleteExpense(t *testing.T) {
 	// Arrange
 	mockExpenseDB := &MockExpenseDB{}
 	mockUserDB := &MockUserDB{}
-	s := NewExpenseService(mockExpenseDB, mockUserDB)
+	s := NewExpenseService(mockExpenseDB, mockUserDB, &MockLedgerDB{}, &MockAccountDB{})
 	ResetMockDB()
 
 	// Act
-	err := s.DeleteExpense(testUser.ID, "0")
+	err := s.DeleteExpense(context.Background(), testUser.ID, 0, "1")
 
 	// Assert
 	if err != nil {
@@ -237,3 +352,226 @@ func TestExpenseService_DeleteExpense(t *testing.T) {
 		t.Errorf("Failed to delete expense")
 	}
 }
+
+func TestExpenseService_CreateExpense_Ledger(t *testing.T) {
+	// Arrange
+	s := NewExpenseService(&MockExpenseDB{}, &MockUserDB{}, &MockLedgerDB{}, &MockAccountDB{})
+	ResetMockDB()
+
+	// Act
+	err := s.CreateExpense(context.Background(), testUser.ID, 1, expectedExpenses[0])
+
+	// Assert
+	if err != nil {
+		t.Errorf("Received an error: received %v, expected %v", err, nil)
+	}
+
+	expenses, err := s.GetExpenses(context.Background(), 2, 1, "all")
+	if err != nil {
+		t.Errorf("Received an error: received %v, expected %v", err, nil)
+	}
+
+	if len(expenses) != 1 || expenses[0].UserID != testUser.ID {
+		t.Errorf("Received incorrect ledger expenses: received %v", expenses)
+	}
+}
+
+func TestExpenseService_CreateExpense_LedgerViewer(t *testing.T) {
+	// Arrange
+	s := NewExpenseService(&MockExpenseDB{}, &MockUserDB{}, &MockLedgerDB{}, &MockAccountDB{})
+	ResetMockDB()
+
+	// Act
+	err := s.CreateExpense(context.Background(), 2, 1, expectedExpenses[0])
+
+	// Assert
+	expectedError := "insufficient ledger role"
+	if err == nil || err.Error() != expectedError {
+		t.Errorf("Received incorrect error: received %v, expected %v", err, expectedError)
+	}
+
+	if len(expensesBD) != 1 {
+		t.Errorf("Viewer must not add expenses: received %v, expected %v", len(expensesBD), 1)
+	}
+}
+
+func TestExpenseService_GetExpenses_NotLedgerMember(t *testing.T) {
+	// Arrange
+	s := NewExpenseService(&MockExpenseDB{}, &MockUserDB{}, &MockLedgerDB{}, &MockAccountDB{})
+	ResetMockDB()
+
+	// Act
+	_, err := s.GetExpenses(context.Background(), testUser.ID, 2, "all")
+
+	// Assert
+	expectedError := "ledger not found"
+	if err == nil || err.Error() != expectedError {
+		t.Errorf("Received incorrect error: received %v, expected %v", err, expectedError)
+	}
+}
+
+func TestExpenseService_DeleteExpense_OtherUser(t *testing.T) {
+	// Arrange
+	s := NewExpenseService(&MockExpenseDB{}, &MockUserDB{}, &MockLedgerDB{}, &MockAccountDB{})
+	ResetMockDB()
+
+	// Act
+	err := s.DeleteExpense(context.Background(), 2, 0, "1")
+
+	// Assert
+	expectedError := "expense not found"
+	if err == nil || err.Error() != expectedError {
+		t.Errorf("Received incorrect error: received %v, expected %v", err, expectedError)
+	}
+
+	if len(expensesBD) != 1 {
+		t.Errorf("Expense of another user must not be deleted")
+	}
+}
+
+func TestExpenseService_CreateExpense_LedgerSplit(t *testing.T) {
+	// Arrange
+	s := NewExpenseService(&MockExpenseDB{}, &MockUserDB{}, &MockLedgerDB{}, &MockAccountDB{})
+	ResetMockDB()
+	expense := models.Expense{
+		Amount:      100,
+		Category:    "rent",
+		SplitMethod: models.SplitPercentage,
+		Splits:      []models.ExpenseSplit{{UserID: 1, Value: 70}, {UserID: 2, Value: 30}},
+	}
+
+	// Act
+	err := s.CreateExpense(context.Background(), testUser.ID, 1, expense)
+
+	// Assert
+	if err != nil {
+		t.Errorf("Received an error: received %v, expected %v", err, nil)
+	}
+
+	created := expensesBD[len(expensesBD)-1]
+	if created.PaidBy != testUser.ID {
+		t.Errorf("Received incorrect payer: received %v, expected %v", created.PaidBy, testUser.ID)
+	}
+
+	if len(created.Splits) != 2 || created.Splits[0].Amount != 70 || created.Splits[1].Amount != 30 {
+		t.Errorf("Received incorrect splits: received %v", created.Splits)
+	}
+}
+
+func TestExpenseService_CreateExpense_LedgerSplitNotMember(t *testing.T) {
+	// Arrange
+	s := NewExpenseService(&MockExpenseDB{}, &MockUserDB{}, &MockLedgerDB{}, &MockAccountDB{})
+	ResetMockDB()
+	expense := models.Expense{
+		Amount:      100,
+		Category:    "rent",
+		SplitMethod: models.SplitExact,
+		Splits:      []models.ExpenseSplit{{UserID: 1, Value: 50}, {UserID: 3, Value: 50}},
+	}
+
+	// Act
+	err := s.CreateExpense(context.Background(), testUser.ID, 1, expense)
+
+	// Assert
+	expectedError := "invalid split participant"
+	if err == nil || err.Error() != expectedError {
+		t.Errorf("Received incorrect error: received %v, expected %v", err, expectedError)
+	}
+}
+
+func TestExpenseService_CreateExpense_DefaultAccount(t *testing.T) {
+	// Arrange
+	s := NewExpenseService(&MockExpenseDB{}, &MockUserDB{}, &MockLedgerDB{}, &MockAccountDB{})
+	ResetMockDB()
+
+	// Act
+	err := s.CreateExpense(context.Background(), testUser.ID, 0, models.Expense{Amount: 10, Category: "food"})
+
+	// Assert
+	if err != nil {
+		t.Errorf("Received an error: received %v, expected %v", err, nil)
+	}
+
+	created := expensesBD[len(expensesBD)-1]
+	if created.AccountID != 1 || created.Kind != models.KindExpense {
+		t.Errorf("Expense isn't tied to default account: received %v", created)
+	}
+}
+
+func TestExpenseService_CreateExpense_ForeignAccount(t *testing.T) {
+	// Arrange
+	s := NewExpenseService(&MockExpenseDB{}, &MockUserDB{}, &MockLedgerDB{}, &MockAccountDB{})
+	ResetMockDB()
+
+	// Act
+	err := s.CreateExpense(context.Background(), 2, 0, models.Expense{Amount: 10, Category: "food", AccountID: 1})
+
+	// Assert
+	expectedError := "account not found"
+	if err == nil || err.Error() != expectedError {
+		t.Errorf("Received incorrect error: received %v, expected %v", err, expectedError)
+	}
+}
+
+func TestExpenseService_CreateExpense_LedgerPayerAccount(t *testing.T) {
+	// Arrange: користувач 1 записує спільну витрату, яку оплатив користувач 2 (рахунків у нього ще немає)
+	s := NewExpenseService(&MockExpenseDB{}, &MockUserDB{}, &MockLedgerDB{}, &MockAccountDB{})
+	ResetMockDB()
+
+	// Act
+	err := s.CreateExpense(context.Background(), testUser.ID, 1, models.Expense{Amount: 10, Category: "food", PaidBy: 2})
+	created := expensesBD[len(expensesBD)-1]
+	explicitErr := s.CreateExpense(context.Background(), testUser.ID, 1, models.Expense{Amount: 10, Category: "food", PaidBy: 2, AccountID: 1})
+
+	// Assert
+	if err != nil {
+		t.Errorf("Received an error: received %v, expected %v", err, nil)
+	}
+	if created.PaidBy != 2 || created.AccountID != 2 {
+		t.Errorf("Expense isn't tied to the payer's account: received %v, expected account %v", created, 2)
+	}
+	if !errors.Is(explicitErr, ErrInvalid) || len(expensesBD) != 2 {
+		t.Errorf("Received an error: received %v, expected %v", explicitErr, ErrInvalid)
+	}
+}
+
+func TestExpenseService_CreateExpense_Validation(t *testing.T) {
+	// Arrange
+	s := NewExpenseService(&MockExpenseDB{}, &MockUserDB{}, &MockLedgerDB{}, &MockAccountDB{})
+	ResetMockDB()
+
+	// Act
+	err := s.CreateExpense(context.Background(), testUser.ID, 0, models.Expense{Amount: -5, Category: "<script>"})
+
+	// Assert
+	var serviceErr *Error
+	if !errors.As(err, &serviceErr) || !errors.Is(err, ErrInvalid) {
+		t.Fatalf("Received incorrect error: received %v, expected validation error", err)
+	}
+
+	// Обидва порушення повідомляються разом
+	if len(serviceErr.Fields) != 2 || serviceErr.Fields[0].Field != "amount" || serviceErr.Fields[1].Field != "category" {
+		t.Errorf("Received incorrect field errors: received %v", serviceErr.Fields)
+	}
+
+	if len(expensesBD) != 1 {
+		t.Errorf("Invalid expense must not be saved")
+	}
+}
+
+func TestExpenseService_UpdateExpense_FutureDate(t *testing.T) {
+	// Arrange
+	s := NewExpenseService(&MockExpenseDB{}, &MockUserDB{}, &MockLedgerDB{}, &MockAccountDB{})
+	ResetMockDB()
+	expense := expectedExpenses[1]
+	expense.Date = time.Now().AddDate(5, 0, 0)
+
+	// Act
+	err := s.UpdateExpense(context.Background(), testUser.ID, 0, expense)
+
+	// Assert
+	var serviceErr *Error
+	if !errors.As(err, &serviceErr) || len(serviceErr.Fields) != 1 || serviceErr.Fields[0].Code != "too_far_in_future" {
+		t.Errorf("Received incorrect error: received %v, expected date validation error", err)
+	}
+}
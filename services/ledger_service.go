@@ -0,0 +1,154 @@
+package services
+
+import (
+	"context"
+	"strings"
+
+	"github.com/ChomuCake/uni-golang-labs/models"
+)
+
+type detailLedgerDB interface {
+	AddLedger(ctx context.Context, ledger models.Ledger) (int, error)
+	GetUserLedgers(ctx context.Context, userID int) ([]models.Ledger, error)
+	GetMemberRole(ctx context.Context, ledgerID, userID int) (string, error)
+	GetLedgerMembers(ctx context.Context, ledgerID int) ([]models.LedgerMember, error)
+	AddMember(ctx context.Context, member models.LedgerMember) error
+	RemoveMember(ctx context.Context, ledgerID, userID int) error
+}
+
+type ledgerUserDB interface {
+	GetUserByUsername(ctx context.Context, username string) (models.User, error)
+}
+
+type LedgerService struct {
+	ledgerDB detailLedgerDB
+	userDB   ledgerUserDB
+}
+
+func NewLedgerService(ledgerDB detailLedgerDB, userDB ledgerUserDB) *LedgerService {
+	return &LedgerService{ledgerDB, userDB}
+}
+
+func (s *LedgerService) CreateLedger(ctx context.Context, userID int, ledger models.Ledger) (models.Ledger, error) {
+	ledger.Name = strings.TrimSpace(ledger.Name)
+	if ledger.Name == "" {
+		return models.Ledger{}, newError(ErrInvalid, "ledger_name_required", "ledger name is required")
+	}
+
+	ledger.OwnerID = userID
+	ledgerID, err := s.ledgerDB.AddLedger(ctx, ledger)
+	if err != nil {
+		return models.Ledger{}, internalError("ledger_create_failed", "failed to create ledger", err)
+	}
+
+	ledger.ID = ledgerID
+	ledger.Role = models.RoleOwner
+
+	return ledger, nil
+}
+
+func (s *LedgerService) GetLedgers(ctx context.Context, userID int) ([]models.Ledger, error) {
+	ledgers, err := s.ledgerDB.GetUserLedgers(ctx, userID)
+	if err != nil {
+		return nil, internalError("ledgers_fetch_failed", "failed to get ledgers", err)
+	}
+
+	if ledgers == nil {
+		ledgers = []models.Ledger{}
+	}
+
+	return ledgers, nil
+}
+
+func (s *LedgerService) GetMembers(ctx context.Context, userID, ledgerID int) ([]models.LedgerMember, error) {
+	// Переглядати учасників може будь-який учасник журналу
+	_, err := s.ledgerDB.GetMemberRole(ctx, ledgerID, userID)
+	if err != nil {
+		return nil, errLedgerNotFound
+	}
+
+	members, err := s.ledgerDB.GetLedgerMembers(ctx, ledgerID)
+	if err != nil {
+		return nil, internalError("ledger_members_fetch_failed", "failed to get ledger members", err)
+	}
+
+	if members == nil {
+		members = []models.LedgerMember{}
+	}
+
+	return members, nil
+}
+
+// InviteMember додає користувача з вказаним ім'ям до журналу (або змінює його роль)
+func (s *LedgerService) InviteMember(ctx context.Context, userID, ledgerID int, username, role string) (models.LedgerMember, error) {
+	err := s.requireOwner(ctx, userID, ledgerID)
+	if err != nil {
+		return models.LedgerMember{}, err
+	}
+
+	if role != models.RoleEditor && role != models.RoleViewer {
+		return models.LedgerMember{}, errInvalidLedgerRole
+	}
+
+	invited, err := s.userDB.GetUserByUsername(ctx, username)
+	if err != nil {
+		return models.LedgerMember{}, errUserNotFound
+	}
+
+	if invited.ID == userID {
+		return models.LedgerMember{}, newError(ErrConflict, "owner_role_immutable", "owner role can't be changed")
+	}
+
+	member := models.LedgerMember{
+		LedgerID: ledgerID,
+		UserID:   invited.ID,
+		Username: invited.Username,
+		Role:     role,
+	}
+
+	err = s.ledgerDB.AddMember(ctx, member)
+	if err != nil {
+		return models.LedgerMember{}, internalError("member_invite_failed", "failed to invite member", err)
+	}
+
+	return member, nil
+}
+
+func (s *LedgerService) RemoveMember(ctx context.Context, userID, ledgerID, memberID int) error {
+	// Учасник може сам вийти з журналу, видаляти інших може лише власник
+	if memberID != userID {
+		err := s.requireOwner(ctx, userID, ledgerID)
+		if err != nil {
+			return err
+		}
+	}
+
+	role, err := s.ledgerDB.GetMemberRole(ctx, ledgerID, memberID)
+	if err != nil {
+		return newError(ErrNotFound, "member_not_found", "member not found")
+	}
+
+	if role == models.RoleOwner {
+		return newError(ErrConflict, "owner_not_removable", "owner can't be removed from ledger")
+	}
+
+	err = s.ledgerDB.RemoveMember(ctx, ledgerID, memberID)
+	if err != nil {
+		return internalError("member_remove_failed", "failed to remove member", err)
+	}
+
+	return nil
+}
+
+func (s *LedgerService) requireOwner(ctx context.Context, userID, ledgerID int) error {
+	role, err := s.ledgerDB.GetMemberRole(ctx, ledgerID, userID)
+	if err != nil {
+		return errLedgerNotFound
+	}
+
+	if role != models.RoleOwner {
+		return errInsufficientRole
+	}
+
+	return nil
+}
@@ -0,0 +1,143 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ChomuCake/uni-golang-labs/models"
+)
+
+// MockLedgerDBDetail зберігає учасників журналів у пам'яті
+type MockLedgerDBDetail struct {
+	members map[int]map[int]string
+}
+
+func newMockLedgerDBDetail() *MockLedgerDBDetail {
+	return &MockLedgerDBDetail{members: map[int]map[int]string{
+		1: {1: models.RoleOwner, 2: models.RoleEditor},
+	}}
+}
+
+func (m *MockLedgerDBDetail) AddLedger(ctx context.Context, ledger models.Ledger) (int, error) {
+	ledgerID := len(m.members) + 1
+	m.members[ledgerID] = map[int]string{ledger.OwnerID: models.RoleOwner}
+	return ledgerID, nil
+}
+
+func (m *MockLedgerDBDetail) GetUserLedgers(ctx context.Context, userID int) ([]models.Ledger, error) {
+	var ledgers []models.Ledger
+	for ledgerID, members := range m.members {
+		if role, ok := members[userID]; ok {
+			ledgers = append(ledgers, models.Ledger{ID: ledgerID, Role: role})
+		}
+	}
+	return ledgers, nil
+}
+
+func (m *MockLedgerDBDetail) GetMemberRole(ctx context.Context, ledgerID, userID int) (string, error) {
+	role, ok := m.members[ledgerID][userID]
+	if !ok {
+		return "", errors.New("not found")
+	}
+	return role, nil
+}
+
+func (m *MockLedgerDBDetail) GetLedgerMembers(ctx context.Context, ledgerID int) ([]models.LedgerMember, error) {
+	var members []models.LedgerMember
+	for userID, role := range m.members[ledgerID] {
+		members = append(members, models.LedgerMember{LedgerID: ledgerID, UserID: userID, Role: role})
+	}
+	return members, nil
+}
+
+func (m *MockLedgerDBDetail) AddMember(ctx context.Context, member models.LedgerMember) error {
+	m.members[member.LedgerID][member.UserID] = member.Role
+	return nil
+}
+
+func (m *MockLedgerDBDetail) RemoveMember(ctx context.Context, ledgerID, userID int) error {
+	delete(m.members[ledgerID], userID)
+	return nil
+}
+
+func TestLedgerService_CreateLedger(t *testing.T) {
+	// Arrange
+	s := NewLedgerService(newMockLedgerDBDetail(), &MockUserDBDetail{})
+
+	// Act
+	ledger, err := s.CreateLedger(context.Background(), testUser.ID, models.Ledger{Name: "Household"})
+
+	// Assert
+	if err != nil {
+		t.Errorf("Received an error: received %v, expected %v", err, nil)
+	}
+
+	if ledger.OwnerID != testUser.ID || ledger.Role != models.RoleOwner {
+		t.Errorf("Received incorrect ledger: received %v", ledger)
+	}
+}
+
+func TestLedgerService_InviteMember(t *testing.T) {
+	// Arrange
+	ledgerDB := newMockLedgerDBDetail()
+	s := NewLedgerService(ledgerDB, &MockUserDBDetail{
+		mockGetUserByUsername: func(username string) (models.User, error) {
+			return models.User{ID: 3, Username: username}, nil
+		},
+	})
+
+	// Act
+	member, err := s.InviteMember(context.Background(), testUser.ID, 1, "roommate", models.RoleViewer)
+
+	// Assert
+	if err != nil {
+		t.Errorf("Received an error: received %v, expected %v", err, nil)
+	}
+
+	if member.UserID != 3 || ledgerDB.members[1][3] != models.RoleViewer {
+		t.Errorf("Member wasn't added with viewer role: received %v", member)
+	}
+}
+
+func TestLedgerService_InviteMember_NotOwner(t *testing.T) {
+	// Arrange
+	s := NewLedgerService(newMockLedgerDBDetail(), &MockUserDBDetail{})
+
+	// Act
+	_, err := s.InviteMember(context.Background(), 2, 1, "roommate", models.RoleEditor)
+
+	// Assert
+	expectedError := "insufficient ledger role"
+	if err == nil || err.Error() != expectedError {
+		t.Errorf("Received incorrect error: received %v, expected %v", err, expectedError)
+	}
+}
+
+func TestLedgerService_InviteMember_InvalidRole(t *testing.T) {
+	// Arrange
+	s := NewLedgerService(newMockLedgerDBDetail(), &MockUserDBDetail{})
+
+	// Act
+	_, err := s.InviteMember(context.Background(), testUser.ID, 1, "roommate", models.RoleOwner)
+
+	// Assert
+	expectedError := "invalid ledger role"
+	if err == nil || err.Error() != expectedError {
+		t.Errorf("Received incorrect error: received %v, expected %v", err, expectedError)
+	}
+}
+
+func TestLedgerService_RemoveMember_Owner(t *testing.T) {
+	// Arrange
+	s := NewLedgerService(newMockLedgerDBDetail(), &MockUserDBDetail{})
+
+	// Act
+	err := s.RemoveMember(context.Background(), testUser.ID, 1, testUser.ID)
+
+	// Assert
+	expectedError := "owner can't be removed from ledger"
+	if err == nil || err.Error() != expectedError {
+		t.Errorf("Received incorrect error: received %v, expected %v", err, expectedError)
+	}
+}
@@ -0,0 +1,129 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/mail"
+	"strconv"
+	"strings"
+
+	"github.com/ChomuCake/uni-golang-labs/models"
+)
+
+type IdentityDB interface {
+	GetUserByIdentity(ctx context.Context, issuer, subject string) (models.User, error)
+	AddUserWithIdentity(ctx context.Context, user models.User, issuer, subject string) (int, error)
+}
+
+type OIDCUserDB interface {
+	GetUserByUsername(ctx context.Context, username string) (models.User, error)
+	GetUserByEmail(ctx context.Context, email string) (models.User, error)
+}
+
+// maxUsernameAttempts - скільки варіантів імені (alice, alice2, alice3, ...) перебрати для нового користувача
+const maxUsernameAttempts = 100
+
+type OIDCService struct {
+	identityDB IdentityDB
+	userDB     OIDCUserDB
+}
+
+func NewOIDCService(identityDB IdentityDB, userDB OIDCUserDB) *OIDCService {
+	return &OIDCService{identityDB: identityDB, userDB: userDB}
+}
+
+// LoginOIDC повертає користувача, прив'язаного до зовнішнього облікового запису, а під час першого
+// входу створює нового. Наявний локальний обліковий запис з тією самою адресою автоматично
+// не прив'язується: інакше будь-хто, хто контролює постачальника, міг би заволодіти ним.
+// Другий фактор перевіряє постачальник, тому локальна 2FA тут не вимагається
+func (s *OIDCService) LoginOIDC(ctx context.Context, identity models.OIDCIdentity) (_ models.User, err error) {
+	ctx, end := startSpan(ctx, "OIDCService.LoginOIDC")
+	defer end(&err)
+
+	if identity.Issuer == "" || identity.Subject == "" {
+		return models.User{}, newError(ErrUnauthorized, "oidc_invalid_identity", "identity provider returned no subject")
+	}
+
+	user, err := s.identityDB.GetUserByIdentity(ctx, identity.Issuer, identity.Subject)
+	if err == nil {
+		return user, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return models.User{}, internalError("oidc_login_failed", "failed to log in with identity provider", err)
+	}
+
+	user = models.User{TimeZone: "UTC"}
+
+	// Адреса береться лише підтверджена постачальником і ще не зайнята іншим користувачем
+	if identity.EmailVerified && len(identity.Email) <= maxEmailLength {
+		if _, err := mail.ParseAddress(identity.Email); err == nil {
+			_, err = s.userDB.GetUserByEmail(ctx, identity.Email)
+			if errors.Is(err, sql.ErrNoRows) {
+				user.Email = identity.Email
+			}
+		}
+	}
+
+	user.Username, err = s.freeUsername(ctx, usernameBase(identity))
+	if err != nil {
+		return models.User{}, err
+	}
+
+	user.ID, err = s.identityDB.AddUserWithIdentity(ctx, user, identity.Issuer, identity.Subject)
+	if err != nil {
+		return models.User{}, internalError("oidc_provisioning_failed", "failed to create user", err)
+	}
+
+	return user, nil
+}
+
+// freeUsername додає до імені номер, доки не знайде вільне
+func (s *OIDCService) freeUsername(ctx context.Context, base string) (string, error) {
+	for i := 1; i <= maxUsernameAttempts; i++ {
+		candidate := base
+		if i > 1 {
+			suffix := strconv.Itoa(i)
+			candidate = truncate(base, maxUsernameLength-len(suffix)) + suffix
+		}
+
+		_, err := s.userDB.GetUserByUsername(ctx, candidate)
+		if errors.Is(err, sql.ErrNoRows) {
+			return candidate, nil
+		}
+		if err != nil {
+			return "", internalError("oidc_provisioning_failed", "failed to create user", err)
+		}
+	}
+
+	return "", newError(ErrConflict, "username_taken", "no free username for the external account")
+}
+
+// usernameBase виводить ім'я користувача з preferred_username, адреси чи повного імені,
+// замінюючи недозволені символи на "_"
+func usernameBase(identity models.OIDCIdentity) string {
+	local, _, _ := strings.Cut(identity.Email, "@")
+
+	for _, candidate := range []string{identity.PreferredUsername, local, identity.Name} {
+		name := strings.Map(func(r rune) rune {
+			if r < 128 && usernamePattern.MatchString(string(r)) {
+				return r
+			}
+			return '_'
+		}, strings.TrimSpace(candidate))
+		name = truncate(name, maxUsernameLength)
+
+		if len(name) >= minUsernameLength && strings.Trim(name, "_") != "" {
+			return name
+		}
+	}
+
+	return "user"
+}
+
+func truncate(s string, n int) string {
+	if len(s) > n {
+		return s[:n]
+	}
+	return s
+}
@@ -0,0 +1,132 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/ChomuCake/uni-golang-labs/models"
+)
+
+// MockIdentityDB зберігає користувачів і прив'язки "issuer|subject" -> айді, як таблиці users і user_identities
+type MockIdentityDB struct {
+	users      map[int]models.User
+	identities map[string]int
+}
+
+func newMockIdentityDB(users ...models.User) *MockIdentityDB {
+	db := &MockIdentityDB{users: map[int]models.User{}, identities: map[string]int{}}
+	for _, user := range users {
+		db.users[user.ID] = user
+	}
+	return db
+}
+
+func (db *MockIdentityDB) GetUserByIdentity(ctx context.Context, issuer, subject string) (models.User, error) {
+	id, ok := db.identities[issuer+"|"+subject]
+	if !ok {
+		return models.User{}, sql.ErrNoRows
+	}
+	return db.users[id], nil
+}
+
+func (db *MockIdentityDB) AddUserWithIdentity(ctx context.Context, user models.User, issuer, subject string) (int, error) {
+	user.ID = len(db.users) + 100
+	db.users[user.ID] = user
+	db.identities[issuer+"|"+subject] = user.ID
+	return user.ID, nil
+}
+
+func (db *MockIdentityDB) GetUserByUsername(ctx context.Context, username string) (models.User, error) {
+	for _, user := range db.users {
+		if user.Username == username {
+			return user, nil
+		}
+	}
+	return models.User{}, sql.ErrNoRows
+}
+
+func (db *MockIdentityDB) GetUserByEmail(ctx context.Context, email string) (models.User, error) {
+	for _, user := range db.users {
+		if user.Email == email {
+			return user, nil
+		}
+	}
+	return models.User{}, sql.ErrNoRows
+}
+
+func TestOIDCService_LoginOIDC_ProvisionsOnce(t *testing.T) {
+	// Arrange
+	db := newMockIdentityDB()
+	s := NewOIDCService(db, db)
+	identity := models.OIDCIdentity{Issuer: "https://idp.example.com", Subject: "ext-42", Email: "alice@example.com", EmailVerified: true, PreferredUsername: "alice"}
+
+	// Act
+	first, firstErr := s.LoginOIDC(context.Background(), identity)
+	second, secondErr := s.LoginOIDC(context.Background(), identity)
+
+	// Assert
+	if firstErr != nil || secondErr != nil {
+		t.Fatalf("Received an error: received %v, %v, expected %v", firstErr, secondErr, nil)
+	}
+	if first.Username != "alice" || first.Email != "alice@example.com" || first.TimeZone != "UTC" {
+		t.Errorf("Received incorrect provisioned user: %+v", first)
+	}
+	if second.ID != first.ID || len(db.users) != 1 {
+		t.Errorf("Received incorrect user for the second login: received %+v, expected %+v", second, first)
+	}
+}
+
+func TestOIDCService_LoginOIDC_DoesNotTakeOverLocalAccount(t *testing.T) {
+	// Arrange: локальний користувач з тим самим ім'ям і адресою
+	local := models.User{ID: 1, Username: "alice", Email: "alice@example.com"}
+	db := newMockIdentityDB(local)
+	s := NewOIDCService(db, db)
+	identity := models.OIDCIdentity{Issuer: "https://idp.example.com", Subject: "ext-42", Email: "alice@example.com", EmailVerified: true, PreferredUsername: "alice"}
+
+	// Act
+	user, err := s.LoginOIDC(context.Background(), identity)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Received an error: received %v, expected %v", err, nil)
+	}
+	if user.ID == local.ID || user.Username != "alice2" || user.Email != "" {
+		t.Errorf("Received incorrect provisioned user: %+v, expected a separate alice2 without email", user)
+	}
+}
+
+func TestUsernameBase(t *testing.T) {
+	cases := []struct {
+		identity models.OIDCIdentity
+		expected string
+	}{
+		{models.OIDCIdentity{PreferredUsername: "bob.smith"}, "bob.smith"},
+		{models.OIDCIdentity{PreferredUsername: "x", Email: "carol+tag@example.com"}, "carol_tag"},
+		{models.OIDCIdentity{Name: "Олена Коваль"}, "user"},
+		{models.OIDCIdentity{Name: "Dan O'Neil"}, "Dan_O_Neil"},
+	}
+
+	for _, c := range cases {
+		if received := usernameBase(c.identity); received != c.expected {
+			t.Errorf("Received incorrect username for %+v: received %q, expected %q", c.identity, received, c.expected)
+		}
+	}
+}
+
+func TestUserService_LoginUser_RejectsEmptyPassword(t *testing.T) {
+	// Arrange: у базі користувач OIDC з порожнім паролем
+	s := NewUserService(&MockUserDBDetail{
+		mockGetUserByUsernameAndPassword: func(username, password string) (models.User, error) {
+			return testUser, nil
+		},
+	})
+
+	// Act
+	_, err := s.LoginUser(context.Background(), models.User{Username: testUser.Username})
+
+	// Assert
+	if err != errInvalidCredentials {
+		t.Errorf("Received incorrect error: received %v, expected %v", err, errInvalidCredentials)
+	}
+}
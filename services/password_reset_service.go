@@ -0,0 +1,153 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/ChomuCake/uni-golang-labs/logging"
+	"github.com/ChomuCake/uni-golang-labs/models"
+)
+
+// Mailer надсилає лист одному адресату (реалізується пакетом mailer)
+type Mailer interface {
+	Send(ctx context.Context, to, subject, body string) error
+}
+
+type PasswordResetUserDB interface {
+	GetUserByEmail(ctx context.Context, email string) (models.User, error)
+}
+
+type PasswordResetDB interface {
+	AddResetToken(ctx context.Context, userID int, tokenHash string, expiresAt time.Time) error
+	GetResetToken(ctx context.Context, tokenHash string) (models.PasswordResetToken, error)
+	UseResetToken(ctx context.Context, token models.PasswordResetToken, password string) error
+}
+
+type PasswordResetService struct {
+	userDB   PasswordResetUserDB
+	resetDB  PasswordResetDB
+	mailer   Mailer
+	resetURL string        // сторінка, на яку веде посилання з листа; токен додається параметром ?token=
+	tokenTTL time.Duration // скільки діє посилання
+
+	accessTokens AccessTokenRevoker
+}
+
+func NewPasswordResetService(userDB PasswordResetUserDB, resetDB PasswordResetDB, mailer Mailer, resetURL string, tokenTTL time.Duration) *PasswordResetService {
+	return &PasswordResetService{userDB: userDB, resetDB: resetDB, mailer: mailer, resetURL: resetURL, tokenTTL: tokenTTL}
+}
+
+// SetAccessTokens вмикає відкликання персональних токенів доступу під час відновлення пароля
+func (s *PasswordResetService) SetAccessTokens(accessTokens AccessTokenRevoker) {
+	s.accessTokens = accessTokens
+}
+
+// hashResetToken - у базі зберігається лише хеш, тож витік таблиці не дає змоги скинути чужий пароль
+func hashResetToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// ForgotPassword надсилає на адресу посилання для відновлення пароля. Для невідомої адреси
+// нічого не надсилається, але й помилка не повертається, щоб за відповіддю не можна було
+// перевірити, чи зареєстрована адреса. З тієї ж причини не повертається і помилка надсилання листа
+func (s *PasswordResetService) ForgotPassword(ctx context.Context, email string) (err error) {
+	ctx, end := startSpan(ctx, "PasswordResetService.ForgotPassword")
+	defer end(&err)
+
+	v := &validator{}
+	v.check(email != "", "email", "required", "must not be empty")
+	v.email("email", email)
+	err = v.err()
+	if err != nil {
+		return err
+	}
+
+	user, err := s.userDB.GetUserByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		return internalError("password_reset_failed", "failed to start password reset", err)
+	}
+
+	raw := make([]byte, 32)
+	_, err = rand.Read(raw)
+	if err != nil {
+		return internalError("password_reset_failed", "failed to start password reset", err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+
+	expiresAt := time.Now().Add(s.tokenTTL)
+	err = s.resetDB.AddResetToken(ctx, user.ID, hashResetToken(token), expiresAt)
+	if err != nil {
+		return internalError("password_reset_failed", "failed to start password reset", err)
+	}
+
+	link := s.resetURL + "?" + url.Values{"token": {token}}.Encode()
+	body := fmt.Sprintf("Hello, %s!\n\n"+
+		"To set a new password for your Finance Tracker account, open this link:\n%s\n\n"+
+		"The link can be used once and expires in %s. If you didn't ask to reset the password, ignore this email.\n",
+		user.Username, link, s.tokenTTL)
+
+	// Збій пошти лише журналюється: помилка лише для зареєстрованих адрес видала б, що адреса існує
+	sendErr := s.mailer.Send(ctx, user.Email, "Finance Tracker password reset", body)
+	if sendErr != nil {
+		logging.FromContext(ctx).ErrorContext(ctx, "send password reset email", "user_id", user.ID, "error", sendErr)
+	}
+
+	return nil
+}
+
+// ResetPassword встановлює новий пароль за токеном з листа; токен діє один раз, а всі
+// видані раніше JWT користувача відкликаються
+func (s *PasswordResetService) ResetPassword(ctx context.Context, reset models.PasswordReset) (err error) {
+	ctx, end := startSpan(ctx, "PasswordResetService.ResetPassword")
+	defer end(&err)
+
+	v := &validator{}
+	v.password("new_password", reset.NewPassword)
+	err = v.err()
+	if err != nil {
+		return err
+	}
+
+	token, err := s.resetDB.GetResetToken(ctx, hashResetToken(reset.Token))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errInvalidResetToken
+		}
+		return internalError("password_reset_failed", "failed to reset password", err)
+	}
+
+	if token.Used || !time.Now().Before(token.ExpiresAt) {
+		return errInvalidResetToken
+	}
+
+	// Як і зміна пароля, відновлення відкликає персональні токени доступу
+	if s.accessTokens != nil {
+		err = s.accessTokens.RevokeAllTokens(ctx, token.UserID)
+		if err != nil {
+			return internalError("password_reset_failed", "failed to reset password", err)
+		}
+	}
+
+	err = s.resetDB.UseResetToken(ctx, token, reset.NewPassword)
+	if err != nil {
+		// Паралельний запит з тим самим токеном встиг першим
+		if errors.Is(err, sql.ErrNoRows) {
+			return errInvalidResetToken
+		}
+		return internalError("password_reset_failed", "failed to reset password", err)
+	}
+
+	return nil
+}
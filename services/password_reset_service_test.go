@@ -0,0 +1,166 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/url"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/ChomuCake/uni-golang-labs/models"
+)
+
+type sentMail struct {
+	to, subject, body string
+}
+
+type recordingMailer struct {
+	sent []sentMail
+}
+
+func (m *recordingMailer) Send(ctx context.Context, to, subject, body string) error {
+	m.sent = append(m.sent, sentMail{to: to, subject: subject, body: body})
+	return nil
+}
+
+type failingMailer struct{}
+
+func (failingMailer) Send(ctx context.Context, to, subject, body string) error {
+	return errors.New("smtp: connection refused")
+}
+
+type resetUserDB map[string]models.User
+
+func (db resetUserDB) GetUserByEmail(ctx context.Context, email string) (models.User, error) {
+	user, ok := db[email]
+	if !ok {
+		return models.User{}, sql.ErrNoRows
+	}
+	return user, nil
+}
+
+// MockPasswordResetDB зберігає токени за хешем, як таблиця password_reset_tokens
+type MockPasswordResetDB struct {
+	tokens    map[string]*models.PasswordResetToken
+	passwords map[int]string
+}
+
+func newMockPasswordResetDB() *MockPasswordResetDB {
+	return &MockPasswordResetDB{tokens: map[string]*models.PasswordResetToken{}, passwords: map[int]string{}}
+}
+
+func (db *MockPasswordResetDB) AddResetToken(ctx context.Context, userID int, tokenHash string, expiresAt time.Time) error {
+	db.tokens[tokenHash] = &models.PasswordResetToken{ID: len(db.tokens) + 1, UserID: userID, ExpiresAt: expiresAt}
+	return nil
+}
+
+func (db *MockPasswordResetDB) GetResetToken(ctx context.Context, tokenHash string) (models.PasswordResetToken, error) {
+	token, ok := db.tokens[tokenHash]
+	if !ok {
+		return models.PasswordResetToken{}, sql.ErrNoRows
+	}
+	return *token, nil
+}
+
+func (db *MockPasswordResetDB) UseResetToken(ctx context.Context, token models.PasswordResetToken, password string) error {
+	for _, stored := range db.tokens {
+		if stored.ID == token.ID {
+			if stored.Used {
+				return sql.ErrNoRows
+			}
+			stored.Used = true
+		}
+	}
+	db.passwords[token.UserID] = password
+	return nil
+}
+
+var resetLinkPattern = regexp.MustCompile(`https://fintrack\.test/reset\.html\?token=[A-Za-z0-9_-]+`)
+
+func TestPasswordResetService_ForgotAndReset(t *testing.T) {
+	// Arrange
+	mailer := &recordingMailer{}
+	resetDB := newMockPasswordResetDB()
+	users := resetUserDB{"alice@example.com": {ID: 7, Username: "alice", Email: "alice@example.com"}}
+	s := NewPasswordResetService(users, resetDB, mailer, "https://fintrack.test/reset.html", time.Hour)
+	accessTokens := NewAccessTokenService(&MockAccessTokenDB{})
+	pat, _ := accessTokens.CreateToken(context.Background(), 7, models.AccessTokenRequest{Name: "script", Scopes: []string{models.ScopeExpensesRead}})
+	s.SetAccessTokens(accessTokens)
+
+	// Act
+	err := s.ForgotPassword(context.Background(), "alice@example.com")
+	if err != nil || len(mailer.sent) != 1 {
+		t.Fatalf("Received incorrect result: received %v, %d emails, expected one email", err, len(mailer.sent))
+	}
+	link, _ := url.Parse(resetLinkPattern.FindString(mailer.sent[0].body))
+	token := link.Query().Get("token")
+
+	reset := models.PasswordReset{Token: token, NewPassword: "n3w-password"}
+	firstErr := s.ResetPassword(context.Background(), reset)
+	secondErr := s.ResetPassword(context.Background(), reset)
+	_, patErr := accessTokens.AuthenticateAccessToken(context.Background(), pat.Token, models.ScopeExpensesRead)
+
+	// Assert
+	if mailer.sent[0].to != "alice@example.com" || token == "" {
+		t.Errorf("Received incorrect email: %+v", mailer.sent[0])
+	}
+	if _, stored := resetDB.tokens[token]; stored || len(resetDB.tokens) != 1 {
+		t.Errorf("Reset token must be stored only as a hash: %v", resetDB.tokens)
+	}
+	if firstErr != nil || resetDB.passwords[7] != "n3w-password" {
+		t.Errorf("Received an error: received %v, expected %v", firstErr, nil)
+	}
+	if !errors.Is(secondErr, ErrInvalid) {
+		t.Errorf("Reused token must be rejected: received %v, expected %v", secondErr, errInvalidResetToken)
+	}
+	if !errors.Is(patErr, ErrUnauthorized) {
+		t.Errorf("Access token must be revoked by the reset: received %v, expected %v", patErr, errInvalidAccessToken)
+	}
+}
+
+func TestPasswordResetService_ForgotPassword_UnknownEmail(t *testing.T) {
+	// Arrange
+	mailer := &recordingMailer{}
+	s := NewPasswordResetService(resetUserDB{}, newMockPasswordResetDB(), mailer, "https://fintrack.test/reset.html", time.Hour)
+
+	// Act
+	err := s.ForgotPassword(context.Background(), "nobody@example.com")
+
+	// Assert
+	if err != nil || len(mailer.sent) != 0 {
+		t.Errorf("Received incorrect result: received %v, %d emails, expected no error and no email", err, len(mailer.sent))
+	}
+}
+
+func TestPasswordResetService_ForgotPassword_MailFailure(t *testing.T) {
+	// Arrange
+	resetDB := newMockPasswordResetDB()
+	users := resetUserDB{"alice@example.com": {ID: 7, Username: "alice", Email: "alice@example.com"}}
+	s := NewPasswordResetService(users, resetDB, failingMailer{}, "https://fintrack.test/reset.html", time.Hour)
+
+	// Act
+	registeredErr := s.ForgotPassword(context.Background(), "alice@example.com")
+	unknownErr := s.ForgotPassword(context.Background(), "nobody@example.com")
+
+	// Assert
+	if registeredErr != nil || unknownErr != nil {
+		t.Errorf("Mail failure must not reveal a registered address: received %v and %v, expected no errors", registeredErr, unknownErr)
+	}
+}
+
+func TestPasswordResetService_ResetPassword_Expired(t *testing.T) {
+	// Arrange
+	resetDB := newMockPasswordResetDB()
+	_ = resetDB.AddResetToken(context.Background(), 7, hashResetToken("expired"), time.Now().Add(-time.Minute))
+	s := NewPasswordResetService(resetUserDB{}, resetDB, &recordingMailer{}, "https://fintrack.test/reset.html", time.Hour)
+
+	// Act
+	err := s.ResetPassword(context.Background(), models.PasswordReset{Token: "expired", NewPassword: "n3w-password"})
+
+	// Assert
+	if !errors.Is(err, ErrInvalid) || len(resetDB.passwords) != 0 {
+		t.Errorf("Received incorrect error: received %v, expected %v", err, errInvalidResetToken)
+	}
+}
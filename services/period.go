@@ -0,0 +1,63 @@
+package services
+
+import (
+	"time"
+
+	"github.com/ChomuCake/uni-golang-labs/models"
+)
+
+// Періоди для фільтрів і звітів
+const (
+	PeriodDay   = "day"
+	PeriodWeek  = "week"
+	PeriodMonth = "month"
+	PeriodYear  = "year"
+)
+
+// userLocation повертає часовий пояс користувача; невідомий або порожній пояс вважається UTC
+func userLocation(user models.User) *time.Location {
+	if user.TimeZone == "" {
+		return time.UTC
+	}
+
+	loc, err := time.LoadLocation(user.TimeZone)
+	if err != nil {
+		return time.UTC
+	}
+
+	return loc
+}
+
+// periodBounds повертає межі [start, end) періоду, що містить момент now, у часовому поясі now.
+// Тиждень починається з понеділка
+func periodBounds(period string, now time.Time) (time.Time, time.Time, bool) {
+	loc := now.Location()
+	year, month, day := now.Date()
+
+	switch period {
+	case PeriodDay:
+		start := time.Date(year, month, day, 0, 0, 0, 0, loc)
+		return start, start.AddDate(0, 0, 1), true
+
+	case PeriodWeek:
+		daysSinceMonday := (int(now.Weekday()) + 6) % 7
+		start := time.Date(year, month, day-daysSinceMonday, 0, 0, 0, 0, loc)
+		return start, start.AddDate(0, 0, 7), true
+
+	case PeriodMonth:
+		start := time.Date(year, month, 1, 0, 0, 0, 0, loc)
+		return start, start.AddDate(0, 1, 0), true
+
+	case PeriodYear:
+		start := time.Date(year, time.January, 1, 0, 0, 0, 0, loc)
+		return start, start.AddDate(1, 0, 0), true
+	}
+
+	return time.Time{}, time.Time{}, false
+}
+
+// startOfDay переносить календарну дату (розібрану в UTC) на північ того ж дня у часовому поясі loc
+func startOfDay(date time.Time, loc *time.Location) time.Time {
+	year, month, day := date.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, loc)
+}
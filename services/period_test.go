@@ -0,0 +1,76 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ChomuCake/uni-golang-labs/models"
+)
+
+// tzUserDB повертає користувача з заданим часовим поясом
+type tzUserDB struct {
+	timeZone string
+}
+
+func (db *tzUserDB) GetUserByID(ctx context.Context, userID int) (models.User, error) {
+	return models.User{ID: userID, Username: "Kyiv user", TimeZone: db.timeZone}, nil
+}
+
+func TestPeriodBounds_Kyiv(t *testing.T) {
+	kyiv, err := time.LoadLocation("Europe/Kyiv")
+	if err != nil {
+		t.Skipf("time zone database is unavailable: %v", err)
+	}
+
+	// 01:00 у Києві 1 березня - це ще 29 лютого за UTC
+	now := time.Date(2024, 3, 1, 1, 0, 0, 0, kyiv)
+
+	tests := []struct {
+		period        string
+		expectedStart time.Time
+		expectedEnd   time.Time
+	}{
+		{PeriodDay, time.Date(2024, 3, 1, 0, 0, 0, 0, kyiv), time.Date(2024, 3, 2, 0, 0, 0, 0, kyiv)},
+		{PeriodWeek, time.Date(2024, 2, 26, 0, 0, 0, 0, kyiv), time.Date(2024, 3, 4, 0, 0, 0, 0, kyiv)},
+		{PeriodMonth, time.Date(2024, 3, 1, 0, 0, 0, 0, kyiv), time.Date(2024, 4, 1, 0, 0, 0, 0, kyiv)},
+		{PeriodYear, time.Date(2024, 1, 1, 0, 0, 0, 0, kyiv), time.Date(2025, 1, 1, 0, 0, 0, 0, kyiv)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.period, func(t *testing.T) {
+			// Act
+			start, end, ok := periodBounds(tt.period, now)
+
+			// Assert
+			if !ok || !start.Equal(tt.expectedStart) || !end.Equal(tt.expectedEnd) {
+				t.Errorf("Received incorrect bounds: received [%v, %v), expected [%v, %v)", start, end, tt.expectedStart, tt.expectedEnd)
+			}
+		})
+	}
+}
+
+func TestExpenseService_CreateExpense_DateOnlyInUserTimeZone(t *testing.T) {
+	kyiv, err := time.LoadLocation("Europe/Kyiv")
+	if err != nil {
+		t.Skipf("time zone database is unavailable: %v", err)
+	}
+
+	// Arrange
+	s := NewExpenseService(&MockExpenseDB{}, &tzUserDB{timeZone: "Europe/Kyiv"}, &MockLedgerDB{}, &MockAccountDB{})
+	ResetMockDB()
+	date, _ := models.ParseDate("2024-03-01")
+
+	// Act
+	err = s.CreateExpense(context.Background(), testUser.ID, 0, models.Expense{Amount: 5, Category: "coffee", Date: date, DateOnly: true})
+
+	// Assert
+	if err != nil {
+		t.Errorf("Received an error: received %v, expected %v", err, nil)
+	}
+
+	expected := time.Date(2024, 3, 1, 0, 0, 0, 0, kyiv)
+	if created := expensesBD[len(expensesBD)-1]; !created.Date.Equal(expected) {
+		t.Errorf("Received incorrect date: received %v, expected %v", created.Date, expected)
+	}
+}
@@ -0,0 +1,345 @@
+package services
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/ChomuCake/uni-golang-labs/models"
+)
+
+const (
+	defaultTrendWindow = 3
+	maxTrendWindow     = 12
+	defaultTrendPoints = 12
+	maxTrendBuckets    = 1000
+)
+
+type ReportDB interface {
+	GetSpendingByRanges(ctx context.Context, userID int, category string, ranges []models.DateRange) ([]int, error)
+	GetCategoryTotals(ctx context.Context, userID int, period models.DateRange) ([]models.CategoryTotal, error)
+	GetExpensesInRange(ctx context.Context, userID int, period models.DateRange) ([]models.Expense, error)
+}
+
+type reportBudgetDB interface {
+	GetUserBudgets(ctx context.Context, userID int) ([]models.Budget, error)
+}
+
+type ReportService struct {
+	reportDB ReportDB
+	budgetDB reportBudgetDB
+	userDB   UserDB
+}
+
+func NewReportService(reportDB ReportDB, budgetDB reportBudgetDB, userDB UserDB) *ReportService {
+	return &ReportService{reportDB, budgetDB, userDB}
+}
+
+func (s *ReportService) location(ctx context.Context, userID int) (*time.Location, error) {
+	user, err := s.userDB.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, errUserNotFound
+	}
+
+	return userLocation(user), nil
+}
+
+// GetTimeSeries повертає витрати за інтервалами (day/week/month) між календарними днями [from, to)
+// у часовому поясі користувача. Інтервали без витрат мають нульову суму; для кожного інтервалу
+// рахується зміна відносно попереднього та ковзне середнє за window інтервалів, а для місяця,
+// в який припадає кінець періоду, - порівняння з попереднім місяцем і тим самим місяцем минулого року
+func (s *ReportService) GetTimeSeries(ctx context.Context, userID int, interval string, from, to time.Time, category string, window int) (models.TimeSeries, error) {
+	loc, err := s.location(ctx, userID)
+	if err != nil {
+		return models.TimeSeries{}, err
+	}
+
+	if interval == "" {
+		interval = PeriodMonth
+	}
+	if interval != PeriodDay && interval != PeriodWeek && interval != PeriodMonth {
+		return models.TimeSeries{}, newError(ErrInvalid, "invalid_interval", "interval must be day, week or month")
+	}
+
+	if window == 0 {
+		window = defaultTrendWindow
+	}
+	if window < 1 || window > maxTrendWindow {
+		return models.TimeSeries{}, newError(ErrInvalid, "invalid_window", "window must be between 1 and 12")
+	}
+
+	// За замовчуванням - останні 12 інтервалів до кінця сьогоднішнього дня
+	if to.IsZero() {
+		_, to, _ = periodBounds(PeriodDay, time.Now().In(loc))
+	} else {
+		to = startOfDay(to, loc)
+	}
+	if from.IsZero() {
+		lastStart, _, _ := periodBounds(interval, to.Add(-time.Nanosecond))
+		from = stepPeriod(interval, lastStart, -(defaultTrendPoints - 1))
+	} else {
+		from = startOfDay(from, loc)
+	}
+	if !from.Before(to) {
+		return models.TimeSeries{}, newError(ErrInvalid, "invalid_range", "from must be before to")
+	}
+
+	// Попередні інтервали потрібні лише для зміни й ковзного середнього першого видимого інтервалу
+	lead := window - 1
+	if lead < 1 {
+		lead = 1
+	}
+	first, _, _ := periodBounds(interval, from)
+	var ranges []models.DateRange
+	for start := stepPeriod(interval, first, -lead); start.Before(to); start = stepPeriod(interval, start, 1) {
+		ranges = append(ranges, models.DateRange{Start: start, End: stepPeriod(interval, start, 1)})
+		if len(ranges) > maxTrendBuckets+lead {
+			return models.TimeSeries{}, newError(ErrInvalid, "range_too_large", "too many intervals in the requested range")
+		}
+	}
+
+	// Видимі інтервали обрізаються межами запиту
+	ranges[lead].Start = from
+	if ranges[len(ranges)-1].End.After(to) {
+		ranges[len(ranges)-1].End = to
+	}
+
+	// Порівняння місяця, в який припадає кінець періоду
+	monthStart, monthEnd, _ := periodBounds(PeriodMonth, to.Add(-time.Nanosecond))
+	ranges = append(ranges,
+		models.DateRange{Start: monthStart.AddDate(0, -1, 0), End: monthStart},
+		models.DateRange{Start: monthStart.AddDate(-1, 0, 0), End: monthEnd.AddDate(-1, 0, 0)},
+		models.DateRange{Start: monthStart, End: monthEnd},
+	)
+
+	totals, err := s.reportDB.GetSpendingByRanges(ctx, userID, category, ranges)
+	if err != nil {
+		return models.TimeSeries{}, internalError("report_failed", "failed to build report", err)
+	}
+
+	series := models.TimeSeries{
+		Interval: interval,
+		Category: category,
+		TimeZone: loc.String(),
+		From:     from,
+		To:       to,
+		Window:   window,
+		Buckets:  []models.TimeSeriesBucket{},
+	}
+
+	bucketCount := len(ranges) - 3
+	for i := lead; i < bucketCount; i++ {
+		windowSum := 0
+		for j := i - window + 1; j <= i; j++ {
+			windowSum += totals[j]
+		}
+
+		series.Buckets = append(series.Buckets, models.TimeSeriesBucket{
+			Start:         ranges[i].Start,
+			End:           ranges[i].End,
+			Total:         totals[i],
+			Change:        totals[i] - totals[i-1],
+			ChangePct:     percentChange(totals[i], totals[i-1]),
+			MovingAverage: round2(float64(windowSum) / float64(window)),
+		})
+		series.Total += totals[i]
+	}
+
+	previousMonth, lastYearMonth, currentMonth := totals[bucketCount], totals[bucketCount+1], totals[bucketCount+2]
+	series.MonthOverMonth = comparePeriods(currentMonth, previousMonth)
+	series.YearOverYear = comparePeriods(currentMonth, lastYearMonth)
+
+	return series, nil
+}
+
+// GetCategoryTotals повертає витрати за категоріями між календарними днями [from, to) у часовому
+// поясі користувача; за замовчуванням - поточний місяць
+func (s *ReportService) GetCategoryTotals(ctx context.Context, userID int, from, to time.Time) (models.CategoryReport, error) {
+	loc, err := s.location(ctx, userID)
+	if err != nil {
+		return models.CategoryReport{}, err
+	}
+
+	period, err := dayRange(from, to, loc)
+	if err != nil {
+		return models.CategoryReport{}, err
+	}
+
+	return s.categoryReport(ctx, userID, period, loc)
+}
+
+// GetBudgetProgress порівнює бюджети користувача з витратами за календарний місяць month
+// у його часовому поясі (нульовий month - поточний місяць)
+func (s *ReportService) GetBudgetProgress(ctx context.Context, userID int, month time.Time) ([]models.BudgetProgress, error) {
+	loc, err := s.location(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	totals, err := s.reportDB.GetCategoryTotals(ctx, userID, monthRange(month, loc))
+	if err != nil {
+		return nil, internalError("report_failed", "failed to build report", err)
+	}
+
+	return s.budgetProgress(ctx, userID, totals)
+}
+
+// GetStatement збирає місячну виписку з тих самих підсумків, що й звіти за категоріями та бюджетами
+func (s *ReportService) GetStatement(ctx context.Context, userID int, month time.Time) (models.Statement, error) {
+	user, err := s.userDB.GetUserByID(ctx, userID)
+	if err != nil {
+		return models.Statement{}, errUserNotFound
+	}
+	loc := userLocation(user)
+	period := monthRange(month, loc)
+
+	report, err := s.categoryReport(ctx, userID, period, loc)
+	if err != nil {
+		return models.Statement{}, err
+	}
+
+	budgets, err := s.budgetProgress(ctx, userID, report.Categories)
+	if err != nil {
+		return models.Statement{}, err
+	}
+
+	expenses, err := s.reportDB.GetExpensesInRange(ctx, userID, period)
+	if err != nil {
+		return models.Statement{}, internalError("report_failed", "failed to build report", err)
+	}
+	if expenses == nil {
+		expenses = []models.Expense{}
+	}
+
+	return models.Statement{
+		Username:   user.Username,
+		TimeZone:   report.TimeZone,
+		Month:      period.Start.Format("2006-01"),
+		From:       period.Start,
+		To:         period.End,
+		Total:      report.Total,
+		Categories: report.Categories,
+		Expenses:   expenses,
+		Budgets:    budgets,
+	}, nil
+}
+
+func (s *ReportService) categoryReport(ctx context.Context, userID int, period models.DateRange, loc *time.Location) (models.CategoryReport, error) {
+	totals, err := s.reportDB.GetCategoryTotals(ctx, userID, period)
+	if err != nil {
+		return models.CategoryReport{}, internalError("report_failed", "failed to build report", err)
+	}
+
+	report := models.CategoryReport{
+		TimeZone:   loc.String(),
+		From:       period.Start,
+		To:         period.End,
+		Categories: []models.CategoryTotal{},
+	}
+	for _, total := range totals {
+		report.Total += total.Total
+	}
+	for _, total := range totals {
+		if report.Total != 0 {
+			total.Share = round2(float64(total.Total) * 100 / float64(report.Total))
+		}
+		report.Categories = append(report.Categories, total)
+	}
+
+	return report, nil
+}
+
+// budgetProgress зіставляє бюджети користувача з уже порахованими витратами за категоріями
+func (s *ReportService) budgetProgress(ctx context.Context, userID int, totals []models.CategoryTotal) ([]models.BudgetProgress, error) {
+	budgets, err := s.budgetDB.GetUserBudgets(ctx, userID)
+	if err != nil {
+		return nil, internalError("budgets_fetch_failed", "failed to get budgets", err)
+	}
+
+	spent := make(map[string]int, len(totals))
+	for _, total := range totals {
+		spent[total.Category] = total.Total
+	}
+
+	progress := []models.BudgetProgress{}
+	for _, budget := range budgets {
+		item := models.BudgetProgress{
+			Category:  budget.Category,
+			Budget:    budget.Amount,
+			Spent:     spent[budget.Category],
+			Remaining: budget.Amount - spent[budget.Category],
+		}
+		if budget.Amount > 0 {
+			item.Percent = round2(float64(item.Spent) * 100 / float64(budget.Amount))
+		}
+		progress = append(progress, item)
+	}
+
+	return progress, nil
+}
+
+// monthRange повертає межі календарного місяця month у поясі loc (нульовий month - поточний місяць)
+func monthRange(month time.Time, loc *time.Location) models.DateRange {
+	if month.IsZero() {
+		month = time.Now().In(loc)
+	} else {
+		month = startOfDay(month, loc)
+	}
+
+	start, end, _ := periodBounds(PeriodMonth, month)
+	return models.DateRange{Start: start, End: end}
+}
+
+// dayRange переводить календарні дні [from, to) у проміжок часу в поясі loc;
+// не вказані межі доповнюються поточним місяцем
+func dayRange(from, to time.Time, loc *time.Location) (models.DateRange, error) {
+	monthStart, monthEnd, _ := periodBounds(PeriodMonth, time.Now().In(loc))
+
+	period := models.DateRange{Start: monthStart, End: monthEnd}
+	if !from.IsZero() {
+		period.Start = startOfDay(from, loc)
+	}
+	if !to.IsZero() {
+		period.End = startOfDay(to, loc)
+	}
+	if !period.Start.Before(period.End) {
+		return models.DateRange{}, newError(ErrInvalid, "invalid_range", "from must be before to")
+	}
+
+	return period, nil
+}
+
+// stepPeriod зсуває початок інтервалу на n інтервалів вперед (або назад для від'ємного n)
+func stepPeriod(interval string, start time.Time, n int) time.Time {
+	switch interval {
+	case PeriodDay:
+		return start.AddDate(0, 0, n)
+	case PeriodWeek:
+		return start.AddDate(0, 0, 7*n)
+	case PeriodYear:
+		return start.AddDate(n, 0, 0)
+	}
+	return start.AddDate(0, n, 0)
+}
+
+func comparePeriods(current, previous int) models.PeriodComparison {
+	return models.PeriodComparison{
+		Current:  current,
+		Previous: previous,
+		Delta:    current - previous,
+		DeltaPct: percentChange(current, previous),
+	}
+}
+
+// percentChange повертає зміну у відсотках; для нульового попереднього значення вона невизначена
+func percentChange(current, previous int) *float64 {
+	if previous == 0 {
+		return nil
+	}
+	pct := round2(float64(current-previous) * 100 / float64(previous))
+	return &pct
+}
+
+func round2(value float64) float64 {
+	return math.Round(value*100) / 100
+}
@@ -0,0 +1,321 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ChomuCake/uni-golang-labs/models"
+)
+
+// MockReportDB рахує суми витрат за проміжками так само, як SQL-запит репозиторію
+type MockReportDB struct {
+	expenses []models.Expense
+	ranges   []models.DateRange
+}
+
+func (db *MockReportDB) GetSpendingByRanges(ctx context.Context, userID int, category string, ranges []models.DateRange) ([]int, error) {
+	db.ranges = ranges
+	totals := make([]int, len(ranges))
+	for i, r := range ranges {
+		for _, expense := range db.expenses {
+			if expense.UserID != userID || (category != "" && expense.Category != category) {
+				continue
+			}
+			if !expense.Date.Before(r.Start) && expense.Date.Before(r.End) {
+				totals[i] += expense.Amount
+			}
+		}
+	}
+	return totals, nil
+}
+
+func (db *MockReportDB) GetCategoryTotals(ctx context.Context, userID int, period models.DateRange) ([]models.CategoryTotal, error) {
+	var totals []models.CategoryTotal
+	index := map[string]int{}
+	for _, expense := range db.expenses {
+		if expense.UserID != userID || expense.Date.Before(period.Start) || !expense.Date.Before(period.End) {
+			continue
+		}
+		i, ok := index[expense.Category]
+		if !ok {
+			i = len(totals)
+			index[expense.Category] = i
+			totals = append(totals, models.CategoryTotal{Category: expense.Category})
+		}
+		totals[i].Total += expense.Amount
+	}
+	return totals, nil
+}
+
+func (db *MockReportDB) GetExpensesInRange(ctx context.Context, userID int, period models.DateRange) ([]models.Expense, error) {
+	var expenses []models.Expense
+	for _, expense := range db.expenses {
+		if expense.UserID == userID && !expense.Date.Before(period.Start) && expense.Date.Before(period.End) {
+			expenses = append(expenses, expense)
+		}
+	}
+	return expenses, nil
+}
+
+// MockBudgetDB зберігає бюджети у пам'яті
+type MockBudgetDB struct {
+	budgets []models.Budget
+}
+
+func (db *MockBudgetDB) SetBudget(ctx context.Context, budget models.Budget) error {
+	for i := range db.budgets {
+		if db.budgets[i].UserID == budget.UserID && db.budgets[i].Category == budget.Category {
+			db.budgets[i].Amount = budget.Amount
+			return nil
+		}
+	}
+	db.budgets = append(db.budgets, budget)
+	return nil
+}
+
+func (db *MockBudgetDB) GetUserBudgets(ctx context.Context, userID int) ([]models.Budget, error) {
+	var budgets []models.Budget
+	for _, budget := range db.budgets {
+		if budget.UserID == userID {
+			budgets = append(budgets, budget)
+		}
+	}
+	return budgets, nil
+}
+
+func (db *MockBudgetDB) DeleteBudget(ctx context.Context, userID int, category string) error {
+	for i, budget := range db.budgets {
+		if budget.UserID == userID && budget.Category == category {
+			db.budgets = append(db.budgets[:i], db.budgets[i+1:]...)
+			return nil
+		}
+	}
+	return sql.ErrNoRows
+}
+
+func TestReportService_GetTimeSeries_Monthly(t *testing.T) {
+	// Arrange
+	day := func(year int, month time.Month, d int) time.Time {
+		return time.Date(year, month, d, 12, 0, 0, 0, time.UTC)
+	}
+	reportDB := &MockReportDB{expenses: []models.Expense{
+		{UserID: 1, Category: "Food", Amount: 50, Date: day(2023, 3, 5)},
+		{UserID: 1, Category: "Food", Amount: 100, Date: day(2024, 1, 10)},
+		{UserID: 1, Category: "Rent", Amount: 200, Date: day(2024, 1, 20)},
+		{UserID: 1, Category: "Food", Amount: 300, Date: day(2024, 3, 1)},
+		{UserID: 2, Category: "Food", Amount: 999, Date: day(2024, 3, 1)},
+	}}
+	s := NewReportService(reportDB, &MockBudgetDB{}, &MockUserDB{})
+
+	// Act
+	series, err := s.GetTimeSeries(context.Background(), testUser.ID, PeriodMonth, day(2024, 1, 1), day(2024, 4, 1), "", 2)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Received an error: received %v, expected %v", err, nil)
+	}
+
+	expectedTotals := []int{300, 0, 300}
+	if len(series.Buckets) != len(expectedTotals) {
+		t.Fatalf("Received incorrect buckets: received %v, expected %v totals", series.Buckets, expectedTotals)
+	}
+	for i, bucket := range series.Buckets {
+		if bucket.Total != expectedTotals[i] {
+			t.Errorf("Received incorrect total in bucket %d: received %v, expected %v", i, bucket.Total, expectedTotals[i])
+		}
+	}
+
+	if series.Buckets[1].Change != -300 || *series.Buckets[1].ChangePct != -100 || series.Buckets[1].MovingAverage != 150 {
+		t.Errorf("Received incorrect February bucket: %+v", series.Buckets[1])
+	}
+	if series.Buckets[2].ChangePct != nil {
+		t.Errorf("Change from an empty month must be undefined, received %v", *series.Buckets[2].ChangePct)
+	}
+	if series.Total != 600 {
+		t.Errorf("Received incorrect total: received %v, expected %v", series.Total, 600)
+	}
+
+	// Березень 2024 порівнюється з лютим 2024 та березнем 2023
+	if series.MonthOverMonth.Current != 300 || series.MonthOverMonth.Previous != 0 {
+		t.Errorf("Received incorrect month over month: %+v", series.MonthOverMonth)
+	}
+	if series.YearOverYear.Previous != 50 || series.YearOverYear.Delta != 250 || *series.YearOverYear.DeltaPct != 500 {
+		t.Errorf("Received incorrect year over year: %+v", series.YearOverYear)
+	}
+}
+
+func TestReportService_GetTimeSeries_CategoryAndZeroFill(t *testing.T) {
+	// Arrange
+	reportDB := &MockReportDB{expenses: []models.Expense{
+		{UserID: 1, Category: "Food", Amount: 10, Date: time.Date(2024, 3, 11, 9, 0, 0, 0, time.UTC)},
+		{UserID: 1, Category: "Rent", Amount: 500, Date: time.Date(2024, 3, 11, 9, 0, 0, 0, time.UTC)},
+	}}
+	s := NewReportService(reportDB, &MockBudgetDB{}, &MockUserDB{})
+
+	// Act
+	series, err := s.GetTimeSeries(context.Background(), testUser.ID, PeriodDay, time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 3, 13, 0, 0, 0, 0, time.UTC), "Food", 1)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Received an error: received %v, expected %v", err, nil)
+	}
+
+	expectedTotals := []int{0, 10, 0}
+	if len(series.Buckets) != len(expectedTotals) {
+		t.Fatalf("Received incorrect buckets: received %v, expected %v totals", series.Buckets, expectedTotals)
+	}
+	for i, bucket := range series.Buckets {
+		if bucket.Total != expectedTotals[i] || bucket.MovingAverage != float64(expectedTotals[i]) {
+			t.Errorf("Received incorrect bucket %d: %+v", i, bucket)
+		}
+	}
+}
+
+func TestReportService_GetTimeSeries_UserTimeZone(t *testing.T) {
+	kyiv, err := time.LoadLocation("Europe/Kyiv")
+	if err != nil {
+		t.Skipf("time zone database is unavailable: %v", err)
+	}
+
+	// Arrange: 23:30 UTC 29 лютого - це вже 1 березня в Києві
+	reportDB := &MockReportDB{expenses: []models.Expense{
+		{UserID: 1, Category: "Food", Amount: 40, Date: time.Date(2024, 2, 29, 23, 30, 0, 0, time.UTC)},
+	}}
+	s := NewReportService(reportDB, &MockBudgetDB{}, &tzUserDB{timeZone: "Europe/Kyiv"})
+
+	// Act
+	series, err := s.GetTimeSeries(context.Background(), 1, PeriodMonth, time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC), "", 1)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Received an error: received %v, expected %v", err, nil)
+	}
+	if len(series.Buckets) != 2 || series.Buckets[0].Total != 0 || series.Buckets[1].Total != 40 {
+		t.Errorf("Received incorrect buckets: %+v", series.Buckets)
+	}
+	if !series.Buckets[1].Start.Equal(time.Date(2024, 3, 1, 0, 0, 0, 0, kyiv)) {
+		t.Errorf("Received incorrect bucket start: received %v, expected midnight in Kyiv", series.Buckets[1].Start)
+	}
+}
+
+func TestReportService_GetTimeSeries_InvalidParams(t *testing.T) {
+	s := NewReportService(&MockReportDB{}, &MockBudgetDB{}, &MockUserDB{})
+	from := time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		interval string
+		from, to time.Time
+		window   int
+	}{
+		{"interval", "hour", time.Time{}, time.Time{}, 0},
+		{"window", PeriodDay, time.Time{}, time.Time{}, 13},
+		{"range", PeriodDay, from, from, 0},
+		{"too many buckets", PeriodDay, from.AddDate(-5, 0, 0), from, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			_, err := s.GetTimeSeries(context.Background(), testUser.ID, tt.interval, tt.from, tt.to, "", tt.window)
+
+			// Assert
+			if !errors.Is(err, ErrInvalid) {
+				t.Errorf("Received incorrect error: received %v, expected %v", err, ErrInvalid)
+			}
+		})
+	}
+}
+
+func TestReportService_GetCategoryTotals(t *testing.T) {
+	// Arrange
+	reportDB := &MockReportDB{expenses: []models.Expense{
+		{UserID: 1, Category: "Food", Amount: 75, Date: time.Date(2024, 3, 5, 12, 0, 0, 0, time.UTC)},
+		{UserID: 1, Category: "Rent", Amount: 25, Date: time.Date(2024, 3, 6, 12, 0, 0, 0, time.UTC)},
+		{UserID: 1, Category: "Rent", Amount: 500, Date: time.Date(2024, 4, 1, 12, 0, 0, 0, time.UTC)},
+	}}
+	s := NewReportService(reportDB, &MockBudgetDB{}, &MockUserDB{})
+
+	// Act
+	report, err := s.GetCategoryTotals(context.Background(), testUser.ID, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC))
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Received an error: received %v, expected %v", err, nil)
+	}
+	if report.Total != 100 || len(report.Categories) != 2 {
+		t.Fatalf("Received incorrect report: %+v", report)
+	}
+	if report.Categories[0].Share != 75 || report.Categories[1].Share != 25 {
+		t.Errorf("Received incorrect shares: %+v", report.Categories)
+	}
+}
+
+func TestReportService_GetBudgetProgress(t *testing.T) {
+	// Arrange
+	reportDB := &MockReportDB{expenses: []models.Expense{
+		{UserID: 1, Category: "Food", Amount: 150, Date: time.Date(2024, 3, 5, 12, 0, 0, 0, time.UTC)},
+		{UserID: 1, Category: "Food", Amount: 70, Date: time.Date(2024, 2, 5, 12, 0, 0, 0, time.UTC)},
+	}}
+	budgetDB := &MockBudgetDB{budgets: []models.Budget{
+		{UserID: 1, Category: "Food", Amount: 100},
+		{UserID: 1, Category: "Rent", Amount: 400},
+		{UserID: 2, Category: "Food", Amount: 1},
+	}}
+	s := NewReportService(reportDB, budgetDB, &MockUserDB{})
+
+	// Act
+	progress, err := s.GetBudgetProgress(context.Background(), testUser.ID, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC))
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Received an error: received %v, expected %v", err, nil)
+	}
+
+	expected := []models.BudgetProgress{
+		{Category: "Food", Budget: 100, Spent: 150, Remaining: -50, Percent: 150},
+		{Category: "Rent", Budget: 400, Spent: 0, Remaining: 400, Percent: 0},
+	}
+	if len(progress) != len(expected) {
+		t.Fatalf("Received incorrect progress: received %+v, expected %+v", progress, expected)
+	}
+	for i := range expected {
+		if progress[i] != expected[i] {
+			t.Errorf("Received incorrect progress: received %+v, expected %+v", progress[i], expected[i])
+		}
+	}
+}
+
+func TestReportService_GetStatement(t *testing.T) {
+	// Arrange
+	reportDB := &MockReportDB{expenses: []models.Expense{
+		{ID: 1, UserID: 1, Category: "Food", Amount: 30, Date: time.Date(2024, 3, 5, 12, 0, 0, 0, time.UTC)},
+		{ID: 2, UserID: 1, Category: "Food", Amount: 90, Date: time.Date(2024, 3, 6, 12, 0, 0, 0, time.UTC)},
+		{ID: 3, UserID: 1, Category: "Rent", Amount: 80, Date: time.Date(2024, 3, 7, 12, 0, 0, 0, time.UTC)},
+		{ID: 4, UserID: 1, Category: "Rent", Amount: 80, Date: time.Date(2024, 4, 7, 12, 0, 0, 0, time.UTC)},
+	}}
+	budgetDB := &MockBudgetDB{budgets: []models.Budget{{UserID: 1, Category: "Food", Amount: 100}}}
+	s := NewReportService(reportDB, budgetDB, &MockUserDB{})
+
+	// Act
+	statement, err := s.GetStatement(context.Background(), testUser.ID, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC))
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Received an error: received %v, expected %v", err, nil)
+	}
+	if statement.Username != "John Doe" || statement.Month != "2024-03" || statement.Total != 200 {
+		t.Errorf("Received incorrect statement header: %+v", statement)
+	}
+	if len(statement.Expenses) != 3 || len(statement.Categories) != 2 {
+		t.Errorf("Received incorrect statement items: %+v", statement)
+	}
+	if len(statement.Budgets) != 1 || statement.Budgets[0].Spent != 120 || statement.Budgets[0].Remaining != -20 {
+		t.Errorf("Received incorrect budget comparison: %+v", statement.Budgets)
+	}
+}
@@ -0,0 +1,129 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/ChomuCake/uni-golang-labs/models"
+)
+
+type SettlementDB interface {
+	GetLedgerDebts(ctx context.Context, ledgerID int) ([]models.Balance, error)
+	GetLedgerSettlements(ctx context.Context, ledgerID int) ([]models.Settlement, error)
+	AddSettlement(ctx context.Context, settlement models.Settlement) (int, error)
+}
+
+type SettlementService struct {
+	settlementDB SettlementDB
+	ledgerDB     LedgerDB
+}
+
+func NewSettlementService(settlementDB SettlementDB, ledgerDB LedgerDB) *SettlementService {
+	return &SettlementService{settlementDB, ledgerDB}
+}
+
+// GetBalances повертає чисті борги між парами учасників журналу з урахуванням уже здійснених розрахунків
+func (s *SettlementService) GetBalances(ctx context.Context, userID, ledgerID int) ([]models.Balance, error) {
+	_, err := s.ledgerDB.GetMemberRole(ctx, ledgerID, userID)
+	if err != nil {
+		return nil, errLedgerNotFound
+	}
+
+	debts, err := s.settlementDB.GetLedgerDebts(ctx, ledgerID)
+	if err != nil {
+		return nil, internalError("balances_fetch_failed", "failed to get ledger balances", err)
+	}
+
+	settlements, err := s.settlementDB.GetLedgerSettlements(ctx, ledgerID)
+	if err != nil {
+		return nil, internalError("settlements_fetch_failed", "failed to get ledger settlements", err)
+	}
+
+	// Переказ від A до B зменшує борг A перед B, тобто діє як зустрічний борг B перед A
+	for _, settlement := range settlements {
+		debts = append(debts, models.Balance{
+			FromUserID: settlement.ToUserID,
+			ToUserID:   settlement.FromUserID,
+			Amount:     settlement.Amount,
+		})
+	}
+
+	return netBalances(debts), nil
+}
+
+// SuggestSettlements пропонує мінімальний набір переказів, що закриває всі борги журналу
+func (s *SettlementService) SuggestSettlements(ctx context.Context, userID, ledgerID int) ([]models.Balance, error) {
+	balances, err := s.GetBalances(ctx, userID, ledgerID)
+	if err != nil {
+		return nil, err
+	}
+
+	return suggestSettlements(balances), nil
+}
+
+func (s *SettlementService) GetSettlements(ctx context.Context, userID, ledgerID int) ([]models.Settlement, error) {
+	_, err := s.ledgerDB.GetMemberRole(ctx, ledgerID, userID)
+	if err != nil {
+		return nil, errLedgerNotFound
+	}
+
+	settlements, err := s.settlementDB.GetLedgerSettlements(ctx, ledgerID)
+	if err != nil {
+		return nil, internalError("settlements_fetch_failed", "failed to get ledger settlements", err)
+	}
+
+	if settlements == nil {
+		settlements = []models.Settlement{}
+	}
+
+	return settlements, nil
+}
+
+// RecordSettlement фіксує переказ між учасниками журналу; за замовчуванням платником є поточний користувач.
+// Записати розрахунок може його платник, отримувач або власник журналу
+func (s *SettlementService) RecordSettlement(ctx context.Context, userID, ledgerID int, settlement models.Settlement) (models.Settlement, error) {
+	role, err := s.ledgerDB.GetMemberRole(ctx, ledgerID, userID)
+	if err != nil {
+		return models.Settlement{}, errLedgerNotFound
+	}
+
+	if role == models.RoleViewer {
+		return models.Settlement{}, errInsufficientRole
+	}
+
+	if settlement.FromUserID == 0 {
+		settlement.FromUserID = userID
+	}
+
+	// Розрахунок між двома іншими учасниками може зафіксувати лише власник журналу
+	if role != models.RoleOwner && userID != settlement.FromUserID && userID != settlement.ToUserID {
+		return models.Settlement{}, errNotSettlementParty
+	}
+
+	if settlement.Amount <= 0 {
+		return models.Settlement{}, newError(ErrInvalid, "invalid_settlement_amount", "settlement amount must be positive")
+	}
+
+	if settlement.FromUserID == settlement.ToUserID {
+		return models.Settlement{}, newError(ErrInvalid, "same_settlement_participants", "settlement participants must differ")
+	}
+
+	for _, participantID := range []int{settlement.FromUserID, settlement.ToUserID} {
+		_, err = s.ledgerDB.GetMemberRole(ctx, ledgerID, participantID)
+		if err != nil {
+			return models.Settlement{}, newError(ErrInvalid, "invalid_settlement_participant", "invalid settlement participant")
+		}
+	}
+
+	settlement.LedgerID = ledgerID
+	if settlement.Date.IsZero() {
+		settlement.Date = time.Now()
+	}
+
+	settlement.ID, err = s.settlementDB.AddSettlement(ctx, settlement)
+	if err != nil {
+		return models.Settlement{}, internalError("settlement_record_failed", "failed to record settlement", err)
+	}
+
+	return settlement, nil
+}
@@ -0,0 +1,232 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ChomuCake/uni-golang-labs/models"
+)
+
+// MockSettlementDB зберігає борги та розрахунки в пам'яті
+type MockSettlementDB struct {
+	debts       []models.Balance
+	settlements []models.Settlement
+}
+
+func (db *MockSettlementDB) GetLedgerDebts(ctx context.Context, ledgerID int) ([]models.Balance, error) {
+	return db.debts, nil
+}
+
+func (db *MockSettlementDB) GetLedgerSettlements(ctx context.Context, ledgerID int) ([]models.Settlement, error) {
+	return db.settlements, nil
+}
+
+func (db *MockSettlementDB) AddSettlement(ctx context.Context, settlement models.Settlement) (int, error) {
+	db.settlements = append(db.settlements, settlement)
+	return len(db.settlements), nil
+}
+
+func TestSplitExpense_Methods(t *testing.T) {
+	members := []int{1, 2, 3}
+
+	tests := []struct {
+		name     string
+		method   string
+		splits   []models.ExpenseSplit
+		expected []int
+	}{
+		{"equal between all members", models.SplitEqual, nil, []int{34, 33, 33}},
+		{"exact", models.SplitExact, []models.ExpenseSplit{{UserID: 1, Value: 60}, {UserID: 3, Value: 40}}, []int{60, 40}},
+		{"percentage", models.SplitPercentage, []models.ExpenseSplit{{UserID: 2, Value: 25}, {UserID: 3, Value: 75}}, []int{25, 75}},
+		{"shares", models.SplitShares, []models.ExpenseSplit{{UserID: 1, Value: 1}, {UserID: 2, Value: 2}}, []int{33, 67}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			splits, err := splitExpense(100, tt.method, tt.splits, members)
+
+			// Assert
+			if err != nil {
+				t.Fatalf("Received an error: received %v, expected %v", err, nil)
+			}
+
+			var amounts []int
+			for _, split := range splits {
+				amounts = append(amounts, split.Amount)
+			}
+			if !reflect.DeepEqual(amounts, tt.expected) {
+				t.Errorf("Received incorrect split: received %v, expected %v", amounts, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSplitExpense_ExactMismatch(t *testing.T) {
+	// Act
+	_, err := splitExpense(100, models.SplitExact, []models.ExpenseSplit{{UserID: 1, Value: 60}}, []int{1, 2})
+
+	// Assert
+	expectedError := "exact split doesn't add up to expense amount"
+	if err == nil || err.Error() != expectedError {
+		t.Errorf("Received incorrect error: received %v, expected %v", err, expectedError)
+	}
+}
+
+func TestSuggestSettlements_MinimalTransfers(t *testing.T) {
+	// Arrange: 2 винен 1 і 3, а 3 винен 1 - борг 3 перекривається, достатньо одного переказу
+	balances := []models.Balance{
+		{FromUserID: 2, ToUserID: 1, Amount: 30},
+		{FromUserID: 2, ToUserID: 3, Amount: 20},
+		{FromUserID: 3, ToUserID: 1, Amount: 20},
+	}
+
+	// Act
+	transfers := suggestSettlements(balances)
+
+	// Assert
+	expected := []models.Balance{{FromUserID: 2, ToUserID: 1, Amount: 50}}
+	if !reflect.DeepEqual(transfers, expected) {
+		t.Errorf("Received incorrect transfers: received %v, expected %v", transfers, expected)
+	}
+}
+
+func TestSettlementService_GetBalances_WithSettlement(t *testing.T) {
+	// Arrange
+	settlementDB := &MockSettlementDB{
+		debts: []models.Balance{
+			{FromUserID: 2, ToUserID: 1, Amount: 50},
+			{FromUserID: 1, ToUserID: 2, Amount: 10},
+		},
+		settlements: []models.Settlement{
+			{LedgerID: 1, FromUserID: 2, ToUserID: 1, Amount: 15, Date: time.Now()},
+		},
+	}
+	s := NewSettlementService(settlementDB, &MockLedgerDB{})
+
+	// Act
+	balances, err := s.GetBalances(context.Background(), testUser.ID, 1)
+
+	// Assert
+	if err != nil {
+		t.Errorf("Received an error: received %v, expected %v", err, nil)
+	}
+
+	expected := []models.Balance{{FromUserID: 2, ToUserID: 1, Amount: 25}}
+	if !reflect.DeepEqual(balances, expected) {
+		t.Errorf("Received incorrect balances: received %v, expected %v", balances, expected)
+	}
+}
+
+func TestSettlementService_RecordSettlement_Viewer(t *testing.T) {
+	// Arrange
+	s := NewSettlementService(&MockSettlementDB{}, &MockLedgerDB{})
+
+	// Act
+	_, err := s.RecordSettlement(context.Background(), 2, 1, models.Settlement{ToUserID: 1, Amount: 10})
+
+	// Assert
+	expectedError := "insufficient ledger role"
+	if err == nil || err.Error() != expectedError {
+		t.Errorf("Received incorrect error: received %v, expected %v", err, expectedError)
+	}
+}
+
+func TestSettlementService_RecordSettlement(t *testing.T) {
+	// Arrange
+	settlementDB := &MockSettlementDB{}
+	s := NewSettlementService(settlementDB, &MockLedgerDB{})
+
+	// Act
+	settlement, err := s.RecordSettlement(context.Background(), testUser.ID, 1, models.Settlement{ToUserID: 2, Amount: 10})
+
+	// Assert
+	if err != nil {
+		t.Errorf("Received an error: received %v, expected %v", err, nil)
+	}
+
+	if settlement.FromUserID != testUser.ID || settlement.Date.IsZero() || len(settlementDB.settlements) != 1 {
+		t.Errorf("Received incorrect settlement: received %v", settlement)
+	}
+}
+
+func TestSettlementService_RecordSettlement_ThirdParty(t *testing.T) {
+	// Arrange: учасники 2 і 3 - редактори, 4 - ще один учасник журналу
+	ledgerDB := newMockLedgerDBDetail()
+	ledgerDB.members[1][3] = models.RoleEditor
+	ledgerDB.members[1][4] = models.RoleViewer
+	settlementDB := &MockSettlementDB{}
+	s := NewSettlementService(settlementDB, ledgerDB)
+	between := models.Settlement{FromUserID: 3, ToUserID: 4, Amount: 10}
+
+	// Act
+	_, editorErr := s.RecordSettlement(context.Background(), 2, 1, between)
+	_, payerErr := s.RecordSettlement(context.Background(), 3, 1, between)
+	_, ownerErr := s.RecordSettlement(context.Background(), 1, 1, between)
+
+	// Assert
+	if !errors.Is(editorErr, ErrForbidden) {
+		t.Errorf("Received incorrect error: received %v, expected %v", editorErr, errNotSettlementParty)
+	}
+	if payerErr != nil || ownerErr != nil || len(settlementDB.settlements) != 2 {
+		t.Errorf("Received incorrect result: received %v and %v, %d settlements, expected 2 recorded", payerErr, ownerErr, len(settlementDB.settlements))
+	}
+}
+
+// splitDebtsDB виводить борги з часток збережених записів журналу, як запит GetLedgerDebts
+type splitDebtsDB struct {
+	MockSettlementDB
+}
+
+func (db *splitDebtsDB) GetLedgerDebts(ctx context.Context, ledgerID int) ([]models.Balance, error) {
+	var debts []models.Balance
+	for _, expense := range expensesBD {
+		for _, split := range expense.Splits {
+			if expense.LedgerID == ledgerID && split.UserID != expense.PaidBy {
+				debts = append(debts, models.Balance{FromUserID: split.UserID, ToUserID: expense.PaidBy, Amount: split.Amount})
+			}
+		}
+	}
+	return debts, nil
+}
+
+func TestSettlementService_LedgerIncomeDoesNotChangeBalances(t *testing.T) {
+	// Arrange
+	ResetMockDB()
+	expenses := NewExpenseService(&MockExpenseDB{}, &MockUserDB{}, &MockLedgerDB{}, &MockAccountDB{})
+	s := NewSettlementService(&splitDebtsDB{}, &MockLedgerDB{})
+	err := expenses.CreateExpense(context.Background(), testUser.ID, 1, models.Expense{Amount: 100, Category: "rent"})
+	if err != nil {
+		t.Fatalf("Received an error: received %v, expected %v", err, nil)
+	}
+	before, _ := s.GetBalances(context.Background(), testUser.ID, 1)
+
+	// Act
+	err = expenses.CreateExpense(context.Background(), testUser.ID, 1, models.Expense{
+		Amount:      300,
+		Category:    "refund",
+		Kind:        models.KindIncome,
+		SplitMethod: models.SplitExact,
+		Splits:      []models.ExpenseSplit{{UserID: 2, Value: 300}},
+	})
+	after, _ := s.GetBalances(context.Background(), testUser.ID, 1)
+	transfers, _ := s.SuggestSettlements(context.Background(), testUser.ID, 1)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Received an error: received %v, expected %v", err, nil)
+	}
+
+	income := expensesBD[len(expensesBD)-1]
+	if income.PaidBy != 0 || income.SplitMethod != "" || income.Splits != nil {
+		t.Errorf("Received incorrect income: received payer %v, method %q, splits %v, expected none", income.PaidBy, income.SplitMethod, income.Splits)
+	}
+
+	expected := []models.Balance{{FromUserID: 2, ToUserID: 1, Amount: 50}}
+	if !reflect.DeepEqual(before, expected) || !reflect.DeepEqual(after, expected) || !reflect.DeepEqual(transfers, expected) {
+		t.Errorf("Received incorrect balances: received %v, then %v and transfers %v, expected %v", before, after, transfers, expected)
+	}
+}
@@ -0,0 +1,225 @@
+package services
+
+import (
+	"sort"
+
+	"github.com/ChomuCake/uni-golang-labs/models"
+)
+
+// splitExpense обчислює суму кожного учасника спільної витрати. Якщо учасників не вказано,
+// витрата ділиться порівну між усіма учасниками журналу (members)
+func splitExpense(amount int, method string, splits []models.ExpenseSplit, members []int) ([]models.ExpenseSplit, error) {
+	if amount <= 0 {
+		return nil, newError(ErrInvalid, "invalid_split_amount", "split amount must be positive")
+	}
+
+	if len(splits) == 0 {
+		if method != "" && method != models.SplitEqual {
+			return nil, newError(ErrInvalid, "split_participants_required", "split participants are required")
+		}
+		for _, userID := range members {
+			splits = append(splits, models.ExpenseSplit{UserID: userID})
+		}
+	}
+
+	// Кожен учасник має бути в журналі і зустрічатись лише один раз
+	isMember := make(map[int]bool, len(members))
+	for _, userID := range members {
+		isMember[userID] = true
+	}
+	seen := make(map[int]bool, len(splits))
+	for _, split := range splits {
+		if !isMember[split.UserID] || seen[split.UserID] {
+			return nil, errInvalidSplitMember
+		}
+		seen[split.UserID] = true
+	}
+
+	weights := make([]int, len(splits))
+	switch method {
+	case "", models.SplitEqual:
+		for i := range weights {
+			weights[i] = 1
+		}
+
+	case models.SplitExact:
+		total := 0
+		for i, split := range splits {
+			if split.Value < 0 {
+				return nil, errNegativeSplitValue
+			}
+			weights[i] = split.Value
+			total += split.Value
+		}
+		if total != amount {
+			return nil, newError(ErrInvalid, "split_sum_mismatch", "exact split doesn't add up to expense amount")
+		}
+
+	case models.SplitPercentage:
+		total := 0
+		for i, split := range splits {
+			if split.Value < 0 {
+				return nil, errNegativeSplitValue
+			}
+			weights[i] = split.Value
+			total += split.Value
+		}
+		if total != 100 {
+			return nil, newError(ErrInvalid, "split_percentage_mismatch", "percentage split doesn't add up to 100")
+		}
+
+	case models.SplitShares:
+		for i, split := range splits {
+			if split.Value <= 0 {
+				return nil, newError(ErrInvalid, "invalid_split_shares", "split shares must be positive")
+			}
+			weights[i] = split.Value
+		}
+
+	default:
+		return nil, newError(ErrInvalid, "unknown_split_method", "unknown split method")
+	}
+
+	amounts := allocate(amount, weights)
+	result := make([]models.ExpenseSplit, len(splits))
+	for i, split := range splits {
+		result[i] = models.ExpenseSplit{UserID: split.UserID, Value: split.Value, Amount: amounts[i]}
+	}
+
+	return result, nil
+}
+
+// allocate ділить amount пропорційно вагам так, щоб сума частин точно дорівнювала amount:
+// спочатку кожен отримує округлену вниз частку, а залишок роздається по одиниці
+// учасникам з найбільшою дробовою частиною
+func allocate(amount int, weights []int) []int {
+	totalWeight := 0
+	for _, weight := range weights {
+		totalWeight += weight
+	}
+
+	parts := make([]int, len(weights))
+	if totalWeight == 0 {
+		return parts
+	}
+
+	remainders := make([]int, len(weights))
+	allocated := 0
+	for i, weight := range weights {
+		parts[i] = amount * weight / totalWeight
+		remainders[i] = amount * weight % totalWeight
+		allocated += parts[i]
+	}
+
+	order := make([]int, len(weights))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return remainders[order[i]] > remainders[order[j]]
+	})
+
+	for i := 0; allocated < amount; i++ {
+		parts[order[i%len(order)]]++
+		allocated++
+	}
+
+	return parts
+}
+
+// netBalances згортає усі борги між парами учасників у один борг на пару
+func netBalances(debts []models.Balance) []models.Balance {
+	type pair struct{ low, high int }
+
+	// Додатне значення - low винен high, від'ємне - навпаки
+	net := make(map[pair]int)
+	for _, debt := range debts {
+		if debt.FromUserID == debt.ToUserID {
+			continue
+		}
+		if debt.FromUserID < debt.ToUserID {
+			net[pair{debt.FromUserID, debt.ToUserID}] += debt.Amount
+		} else {
+			net[pair{debt.ToUserID, debt.FromUserID}] -= debt.Amount
+		}
+	}
+
+	balances := []models.Balance{}
+	for p, amount := range net {
+		switch {
+		case amount > 0:
+			balances = append(balances, models.Balance{FromUserID: p.low, ToUserID: p.high, Amount: amount})
+		case amount < 0:
+			balances = append(balances, models.Balance{FromUserID: p.high, ToUserID: p.low, Amount: -amount})
+		}
+	}
+
+	sort.Slice(balances, func(i, j int) bool {
+		if balances[i].FromUserID != balances[j].FromUserID {
+			return balances[i].FromUserID < balances[j].FromUserID
+		}
+		return balances[i].ToUserID < balances[j].ToUserID
+	})
+
+	return balances
+}
+
+// suggestSettlements пропонує перекази, що закривають усі борги: найбільший боржник
+// платить найбільшому кредитору, доки всі сальдо не стануть нульовими.
+// Жадібний підхід дає не більше n-1 переказів для n учасників
+func suggestSettlements(balances []models.Balance) []models.Balance {
+	position := make(map[int]int)
+	for _, balance := range balances {
+		position[balance.FromUserID] -= balance.Amount
+		position[balance.ToUserID] += balance.Amount
+	}
+
+	type party struct{ userID, amount int }
+	var debtors, creditors []party
+	for userID, amount := range position {
+		switch {
+		case amount < 0:
+			debtors = append(debtors, party{userID, -amount})
+		case amount > 0:
+			creditors = append(creditors, party{userID, amount})
+		}
+	}
+
+	byAmount := func(parties []party) {
+		sort.Slice(parties, func(i, j int) bool {
+			if parties[i].amount != parties[j].amount {
+				return parties[i].amount > parties[j].amount
+			}
+			return parties[i].userID < parties[j].userID
+		})
+	}
+	byAmount(debtors)
+	byAmount(creditors)
+
+	transfers := []models.Balance{}
+	for len(debtors) > 0 && len(creditors) > 0 {
+		amount := debtors[0].amount
+		if creditors[0].amount < amount {
+			amount = creditors[0].amount
+		}
+
+		transfers = append(transfers, models.Balance{
+			FromUserID: debtors[0].userID,
+			ToUserID:   creditors[0].userID,
+			Amount:     amount,
+		})
+
+		debtors[0].amount -= amount
+		creditors[0].amount -= amount
+		if debtors[0].amount == 0 {
+			debtors = debtors[1:]
+		}
+		if creditors[0].amount == 0 {
+			creditors = creditors[1:]
+		}
+		byAmount(debtors)
+		byAmount(creditors)
+	}
+
+	return transfers
+}
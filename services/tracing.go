@@ -0,0 +1,29 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+)
+
+var tracer = otel.Tracer("github.com/ChomuCake/uni-golang-labs/services")
+
+// startSpan починає спан методу сервісу; повернену функцію слід викликати через defer
+// з адресою іменованого результату err. Помилкою спану вважаються лише внутрішні збої,
+// відмови через некоректні дані чи права доступу лише записуються як подія
+func startSpan(ctx context.Context, name string) (context.Context, func(err *error)) {
+	ctx, span := tracer.Start(ctx, name)
+
+	return ctx, func(err *error) {
+		if *err != nil {
+			span.RecordError(*err)
+			var serviceErr *Error
+			if !errors.As(*err, &serviceErr) || errors.Is(*err, ErrInternal) {
+				span.SetStatus(codes.Error, (*err).Error())
+			}
+		}
+		span.End()
+	}
+}
@@ -0,0 +1,245 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"image/png"
+	"strings"
+	"time"
+
+	"github.com/ChomuCake/uni-golang-labs/models"
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/totp"
+)
+
+type TwoFactorDB interface {
+	GetTOTP(ctx context.Context, userID int) (models.TOTPState, error)
+	SetTOTPSecret(ctx context.Context, userID int, secret string) error
+	EnableTOTP(ctx context.Context, userID int, step int64, recoveryHashes []string) error
+	DisableTOTP(ctx context.Context, userID int) error
+	UseTOTPStep(ctx context.Context, userID int, step int64) (bool, error)
+	UseRecoveryCode(ctx context.Context, userID int, codeHash string) (bool, error)
+}
+
+// Параметри TOTP (RFC 6238), які підтримують усі поширені автентифікатори: 6 цифр, 30 секунд, SHA-1;
+// приймається також код сусіднього інтервалу, щоб пережити розбіжність годинників
+const (
+	totpIssuer        = "Finance Tracker"
+	totpPeriod        = 30
+	totpSkew          = 1
+	recoveryCodeCount = 10
+	qrCodeSize        = 256
+)
+
+var totpOpts = totp.ValidateOpts{Period: totpPeriod, Digits: otp.DigitsSix, Algorithm: otp.AlgorithmSHA1}
+
+type TwoFactorService struct {
+	twoFactorDB TwoFactorDB
+	userDB      UserDB
+}
+
+func NewTwoFactorService(twoFactorDB TwoFactorDB, userDB UserDB) *TwoFactorService {
+	return &TwoFactorService{twoFactorDB: twoFactorDB, userDB: userDB}
+}
+
+// Setup створює новий секрет TOTP. 2FA вмикається лише після підтвердження кодом (Enable),
+// тож повторний Setup до підтвердження просто замінює секрет
+func (s *TwoFactorService) Setup(ctx context.Context, userID int) (_ models.TOTPSetup, err error) {
+	ctx, end := startSpan(ctx, "TwoFactorService.Setup")
+	defer end(&err)
+
+	user, err := s.userDB.GetUserByID(ctx, userID)
+	if err != nil {
+		return models.TOTPSetup{}, errUserNotFound
+	}
+	if user.TwoFactorEnabled {
+		return models.TOTPSetup{}, errTwoFactorEnabled
+	}
+
+	key, err := totp.Generate(totp.GenerateOpts{Issuer: totpIssuer, AccountName: user.Username, Period: totpPeriod})
+	if err != nil {
+		return models.TOTPSetup{}, internalError("2fa_setup_failed", "failed to set up two-factor authentication", err)
+	}
+
+	img, err := key.Image(qrCodeSize, qrCodeSize)
+	if err != nil {
+		return models.TOTPSetup{}, internalError("2fa_setup_failed", "failed to set up two-factor authentication", err)
+	}
+	var qr bytes.Buffer
+	err = png.Encode(&qr, img)
+	if err != nil {
+		return models.TOTPSetup{}, internalError("2fa_setup_failed", "failed to set up two-factor authentication", err)
+	}
+
+	err = s.twoFactorDB.SetTOTPSecret(ctx, userID, key.Secret())
+	if err != nil {
+		return models.TOTPSetup{}, internalError("2fa_setup_failed", "failed to set up two-factor authentication", err)
+	}
+
+	return models.TOTPSetup{
+		Secret: key.Secret(),
+		URI:    key.URL(),
+		QRCode: "data:image/png;base64," + base64.StdEncoding.EncodeToString(qr.Bytes()),
+	}, nil
+}
+
+// Enable підтверджує налаштований секрет першим кодом з автентифікатора, вмикає 2FA
+// і повертає коди відновлення (у базі зберігаються лише їхні хеші)
+func (s *TwoFactorService) Enable(ctx context.Context, userID int, code string) (_ models.RecoveryCodes, err error) {
+	ctx, end := startSpan(ctx, "TwoFactorService.Enable")
+	defer end(&err)
+
+	state, err := s.getTOTP(ctx, userID)
+	if err != nil {
+		return models.RecoveryCodes{}, err
+	}
+	if state.Enabled {
+		return models.RecoveryCodes{}, errTwoFactorEnabled
+	}
+	if state.Secret == "" {
+		return models.RecoveryCodes{}, errTwoFactorNotSetUp
+	}
+
+	step, ok := matchTOTP(state.Secret, code, time.Now())
+	if !ok {
+		return models.RecoveryCodes{}, errIncorrectTwoFactor
+	}
+
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		codes[i], err = newRecoveryCode()
+		if err != nil {
+			return models.RecoveryCodes{}, internalError("2fa_enable_failed", "failed to enable two-factor authentication", err)
+		}
+		hashes[i] = hashRecoveryCode(codes[i])
+	}
+
+	err = s.twoFactorDB.EnableTOTP(ctx, userID, step, hashes)
+	if err != nil {
+		return models.RecoveryCodes{}, internalError("2fa_enable_failed", "failed to enable two-factor authentication", err)
+	}
+
+	return models.RecoveryCodes{Codes: codes}, nil
+}
+
+// Disable вимикає 2FA; щоб викрадений токен сесії не дозволив цього зробити, потрібен чинний код
+func (s *TwoFactorService) Disable(ctx context.Context, userID int, code string) (err error) {
+	ctx, end := startSpan(ctx, "TwoFactorService.Disable")
+	defer end(&err)
+
+	state, err := s.getTOTP(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !state.Enabled {
+		return errTwoFactorNotEnabled
+	}
+
+	ok, err := s.verify(ctx, userID, state, code)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errIncorrectTwoFactor
+	}
+
+	err = s.twoFactorDB.DisableTOTP(ctx, userID)
+	if err != nil {
+		return internalError("2fa_disable_failed", "failed to disable two-factor authentication", err)
+	}
+
+	return nil
+}
+
+// VerifyCode перевіряє другий фактор під час входу: код TOTP (кожен приймається лише раз)
+// або невикористаний код відновлення. Для користувача без 2FA завжди повертає false
+func (s *TwoFactorService) VerifyCode(ctx context.Context, userID int, code string) (_ bool, err error) {
+	ctx, end := startSpan(ctx, "TwoFactorService.VerifyCode")
+	defer end(&err)
+
+	state, err := s.getTOTP(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	if !state.Enabled {
+		return false, nil
+	}
+
+	return s.verify(ctx, userID, state, code)
+}
+
+func (s *TwoFactorService) getTOTP(ctx context.Context, userID int) (models.TOTPState, error) {
+	state, err := s.twoFactorDB.GetTOTP(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.TOTPState{}, errUserNotFound
+		}
+		return models.TOTPState{}, internalError("2fa_fetch_failed", "failed to get two-factor settings", err)
+	}
+	return state, nil
+}
+
+func (s *TwoFactorService) verify(ctx context.Context, userID int, state models.TOTPState, code string) (bool, error) {
+	code = strings.TrimSpace(code)
+
+	if len(code) == int(otp.DigitsSix) {
+		step, ok := matchTOTP(state.Secret, code, time.Now())
+		if !ok || step <= state.LastStep {
+			return false, nil
+		}
+
+		// Умовне оновлення в базі відсікає паралельне повторне використання того самого коду
+		ok, err := s.twoFactorDB.UseTOTPStep(ctx, userID, step)
+		if err != nil {
+			return false, internalError("2fa_verify_failed", "failed to verify two-factor code", err)
+		}
+		return ok, nil
+	}
+
+	ok, err := s.twoFactorDB.UseRecoveryCode(ctx, userID, hashRecoveryCode(code))
+	if err != nil {
+		return false, internalError("2fa_verify_failed", "failed to verify two-factor code", err)
+	}
+	return ok, nil
+}
+
+// matchTOTP шукає серед поточного та сусідніх інтервалів той, якому відповідає код
+func matchTOTP(secret, code string, now time.Time) (int64, bool) {
+	current := now.Unix() / totpPeriod
+	for step := current - totpSkew; step <= current+totpSkew; step++ {
+		expected, err := totp.GenerateCodeCustom(secret, time.Unix(step*totpPeriod, 0), totpOpts)
+		if err != nil {
+			return 0, false
+		}
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+// newRecoveryCode генерує код вигляду "a1b2c-d3e4f" (40 випадкових бітів)
+func newRecoveryCode() (string, error) {
+	raw := make([]byte, 5)
+	_, err := rand.Read(raw)
+	if err != nil {
+		return "", err
+	}
+	code := hex.EncodeToString(raw)
+	return code[:5] + "-" + code[5:], nil
+}
+
+// hashRecoveryCode нормалізує код (регістр, пробіли, дефіс), щоб користувач міг ввести його як завгодно
+func hashRecoveryCode(code string) string {
+	code = strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,191 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pquerna/otp/totp"
+
+	"github.com/ChomuCake/uni-golang-labs/models"
+)
+
+// MockTwoFactorDB імітує стовпці totp_* одного користувача і таблицю recovery_codes
+type MockTwoFactorDB struct {
+	state    models.TOTPState
+	recovery map[string]bool // хеш коду -> використано
+}
+
+func (db *MockTwoFactorDB) GetTOTP(ctx context.Context, userID int) (models.TOTPState, error) {
+	if userID != testUser.ID {
+		return models.TOTPState{}, sql.ErrNoRows
+	}
+	return db.state, nil
+}
+
+func (db *MockTwoFactorDB) SetTOTPSecret(ctx context.Context, userID int, secret string) error {
+	db.state = models.TOTPState{Secret: secret}
+	return nil
+}
+
+func (db *MockTwoFactorDB) EnableTOTP(ctx context.Context, userID int, step int64, recoveryHashes []string) error {
+	db.state.Enabled = true
+	db.state.LastStep = step
+	db.recovery = map[string]bool{}
+	for _, hash := range recoveryHashes {
+		db.recovery[hash] = false
+	}
+	return nil
+}
+
+func (db *MockTwoFactorDB) DisableTOTP(ctx context.Context, userID int) error {
+	db.state = models.TOTPState{}
+	db.recovery = nil
+	return nil
+}
+
+func (db *MockTwoFactorDB) UseTOTPStep(ctx context.Context, userID int, step int64) (bool, error) {
+	if step <= db.state.LastStep {
+		return false, nil
+	}
+	db.state.LastStep = step
+	return true, nil
+}
+
+func (db *MockTwoFactorDB) UseRecoveryCode(ctx context.Context, userID int, codeHash string) (bool, error) {
+	used, ok := db.recovery[codeHash]
+	if !ok || used {
+		return false, nil
+	}
+	db.recovery[codeHash] = true
+	return true, nil
+}
+
+// twoFactorUserDB повертає testUser з прапорцем 2FA зі стану MockTwoFactorDB
+type twoFactorUserDB struct {
+	db *MockTwoFactorDB
+}
+
+func (u twoFactorUserDB) GetUserByID(ctx context.Context, userID int) (models.User, error) {
+	user := testUser
+	user.TwoFactorEnabled = u.db.state.Enabled
+	return user, nil
+}
+
+// enableTwoFactor проводить налаштування 2FA і повертає секрет та коди відновлення
+func enableTwoFactor(t *testing.T, s *TwoFactorService) (string, []string) {
+	t.Helper()
+
+	setup, err := s.Setup(context.Background(), testUser.ID)
+	if err != nil {
+		t.Fatalf("Received an error: received %v, expected %v", err, nil)
+	}
+
+	code, _ := totp.GenerateCode(setup.Secret, time.Now())
+	recovery, err := s.Enable(context.Background(), testUser.ID, code)
+	if err != nil {
+		t.Fatalf("Received an error: received %v, expected %v", err, nil)
+	}
+
+	return setup.Secret, recovery.Codes
+}
+
+func TestTwoFactorService_Setup(t *testing.T) {
+	// Arrange
+	db := &MockTwoFactorDB{}
+	s := NewTwoFactorService(db, twoFactorUserDB{db})
+
+	// Act
+	setup, err := s.Setup(context.Background(), testUser.ID)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Received an error: received %v, expected %v", err, nil)
+	}
+	if setup.Secret == "" || !strings.HasPrefix(setup.URI, "otpauth://totp/") || !strings.Contains(setup.URI, "secret="+setup.Secret) {
+		t.Errorf("Received incorrect otpauth URI: %q", setup.URI)
+	}
+	if !strings.HasPrefix(setup.QRCode, "data:image/png;base64,") {
+		t.Errorf("Received incorrect QR code: %.40q", setup.QRCode)
+	}
+}
+
+func TestTwoFactorService_Enable_WrongCode(t *testing.T) {
+	// Arrange
+	db := &MockTwoFactorDB{}
+	s := NewTwoFactorService(db, twoFactorUserDB{db})
+	_, _ = s.Setup(context.Background(), testUser.ID)
+
+	// Act
+	_, err := s.Enable(context.Background(), testUser.ID, "000000")
+
+	// Assert
+	if !errors.Is(err, ErrInvalid) || db.state.Enabled {
+		t.Errorf("Received incorrect result: received %v, enabled %v, expected %v", err, db.state.Enabled, errIncorrectTwoFactor)
+	}
+}
+
+func TestTwoFactorService_VerifyCode_RejectsReplay(t *testing.T) {
+	// Arrange: код, яким підтверджено налаштування, для входу вже не годиться
+	db := &MockTwoFactorDB{}
+	s := NewTwoFactorService(db, twoFactorUserDB{db})
+	secret, _ := enableTwoFactor(t, s)
+	next, _ := totp.GenerateCode(secret, time.Now().Add(totpPeriod*time.Second))
+
+	// Act
+	first, firstErr := s.VerifyCode(context.Background(), testUser.ID, next)
+	second, secondErr := s.VerifyCode(context.Background(), testUser.ID, next)
+
+	// Assert
+	if firstErr != nil || !first {
+		t.Errorf("Received incorrect result: received %v, %v, expected the code to be accepted", first, firstErr)
+	}
+	if secondErr != nil || second {
+		t.Errorf("Reused code must be rejected: received %v, %v", second, secondErr)
+	}
+}
+
+func TestTwoFactorService_VerifyCode_RecoveryCodeOnce(t *testing.T) {
+	// Arrange
+	db := &MockTwoFactorDB{}
+	s := NewTwoFactorService(db, twoFactorUserDB{db})
+	_, codes := enableTwoFactor(t, s)
+
+	// Act: коди відновлення приймаються без дефіса і в будь-якому регістрі
+	first, _ := s.VerifyCode(context.Background(), testUser.ID, strings.ToUpper(strings.ReplaceAll(codes[0], "-", "")))
+	second, _ := s.VerifyCode(context.Background(), testUser.ID, codes[0])
+	other, _ := s.VerifyCode(context.Background(), testUser.ID, codes[1])
+
+	// Assert
+	if len(codes) != recoveryCodeCount || len(db.recovery) != recoveryCodeCount {
+		t.Fatalf("Received incorrect recovery codes: received %d codes, %d stored", len(codes), len(db.recovery))
+	}
+	if _, stored := db.recovery[codes[0]]; stored {
+		t.Errorf("Recovery codes must be stored only as hashes")
+	}
+	if !first || second || !other {
+		t.Errorf("Received incorrect result: received %v, %v, %v, expected true, false, true", first, second, other)
+	}
+}
+
+func TestTwoFactorService_Disable(t *testing.T) {
+	// Arrange
+	db := &MockTwoFactorDB{}
+	s := NewTwoFactorService(db, twoFactorUserDB{db})
+	_, codes := enableTwoFactor(t, s)
+
+	// Act
+	wrongErr := s.Disable(context.Background(), testUser.ID, "nope")
+	err := s.Disable(context.Background(), testUser.ID, codes[0])
+
+	// Assert
+	if !errors.Is(wrongErr, ErrInvalid) {
+		t.Errorf("Received incorrect error: received %v, expected %v", wrongErr, errIncorrectTwoFactor)
+	}
+	if err != nil || db.state.Enabled || db.state.Secret != "" {
+		t.Errorf("Received incorrect result: received %v, state %+v, expected 2FA to be disabled", err, db.state)
+	}
+}
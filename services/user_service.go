@@ -1,54 +1,387 @@
 package services
 
 import (
+	"context"
 	"database/sql"
 	"errors"
+	"time"
 
 	"github.com/ChomuCake/uni-golang-labs/models"
 )
 
 type detailUserDB interface {
-	AddUser(user models.User) error
-	GetUserByUsernameAndPassword(username, password string) (models.User, error)
-	GetUserByUsername(username string) (models.User, error)
-	GetUserByID(userID int) (models.User, error)
+	AddUser(ctx context.Context, user models.User) error
+	GetUserByUsernameAndPassword(ctx context.Context, username, password string) (models.User, error)
+	GetUserByUsername(ctx context.Context, username string) (models.User, error)
+	GetUserByEmail(ctx context.Context, email string) (models.User, error)
+	GetUserByID(ctx context.Context, userID int) (models.User, error)
+	GetLoginState(ctx context.Context, username string) (models.LoginState, error)
+	RecordLoginFailure(ctx context.Context, username string) (int, error)
+	LockUser(ctx context.Context, username string, until time.Time) error
+	ResetLoginFailures(ctx context.Context, username string) error
+	UpdateUser(ctx context.Context, user models.User) error
+	ChangePassword(ctx context.Context, userID int, password string) (int, error)
+	DeleteUser(ctx context.Context, userID int) (bool, error)
+}
+
+// Прогресивне блокування від підбору пароля: після lockoutThreshold невдалих спроб поспіль
+// вхід блокується на lockoutBase, і кожна наступна невдача подвоює блокування до lockoutMax
+const (
+	lockoutThreshold = 5
+	lockoutBase      = time.Minute
+	lockoutMax       = time.Hour
+)
+
+// lockoutDuration повертає тривалість блокування після failures невдалих спроб поспіль
+func lockoutDuration(failures int) time.Duration {
+	if failures < lockoutThreshold {
+		return 0
+	}
+
+	d := lockoutBase
+	for i := lockoutThreshold; i < failures && d < lockoutMax; i++ {
+		d *= 2
+	}
+	if d > lockoutMax {
+		d = lockoutMax
+	}
+	return d
+}
+
+func errAccountLocked(retryAfter time.Duration) *Error {
+	err := newError(ErrTooMany, "account_locked", "too many failed login attempts, try again later")
+	err.RetryAfter = retryAfter
+	return err
+}
+
+// LoginMetrics рахує невдалі спроби входу (реалізується пакетом metrics)
+type LoginMetrics interface {
+	LoginFailed()
+}
+
+// TwoFactorVerifier перевіряє другий фактор входу (реалізується TwoFactorService)
+type TwoFactorVerifier interface {
+	VerifyCode(ctx context.Context, userID int, code string) (bool, error)
+}
+
+// AccessTokenRevoker відкликає всі персональні токени доступу користувача (реалізується AccessTokenService)
+type AccessTokenRevoker interface {
+	RevokeAllTokens(ctx context.Context, userID int) error
 }
 
 type UserService struct {
-	userDB detailUserDB
+	userDB       detailUserDB
+	metrics      LoginMetrics
+	twoFactor    TwoFactorVerifier
+	accessTokens AccessTokenRevoker
 }
 
 func NewUserService(userDB detailUserDB) *UserService {
-	return &UserService{userDB}
+	return &UserService{userDB: userDB}
+}
+
+// SetMetrics вмикає облік невдалих спроб входу; без нього сервіс працює без метрик
+func (s *UserService) SetMetrics(metrics LoginMetrics) {
+	s.metrics = metrics
 }
 
-func (s *UserService) RegisterUser(user models.User) error {
+// SetTwoFactor вмикає другий крок входу для користувачів з 2FA; без нього CompleteLogin завжди відмовляє
+func (s *UserService) SetTwoFactor(twoFactor TwoFactorVerifier) {
+	s.twoFactor = twoFactor
+}
+
+// SetAccessTokens вмикає відкликання персональних токенів доступу під час зміни пароля
+func (s *UserService) SetAccessTokens(accessTokens AccessTokenRevoker) {
+	s.accessTokens = accessTokens
+}
+
+func (s *UserService) RegisterUser(ctx context.Context, user models.User) (err error) {
+	ctx, end := startSpan(ctx, "UserService.RegisterUser")
+	defer end(&err)
+
+	err = validateUser(user)
+	if err != nil {
+		return err
+	}
+
+	if user.TimeZone == "" {
+		user.TimeZone = "UTC"
+	}
 
-	_, err := s.userDB.GetUserByUsername(user.Username)
+	_, err = s.userDB.GetUserByUsername(ctx, user.Username)
 	if err == nil {
-		return errors.New("user with such name is already exists")
+		return errUsernameAlreadyExists
 	}
 
-	err = s.userDB.AddUser(user)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			return errors.New("errNoRows")
+	if user.Email != "" {
+		_, err = s.userDB.GetUserByEmail(ctx, user.Email)
+		if err == nil {
+			return errEmailAlreadyExists
 		}
-		return errors.New("registration failed")
+	}
+
+	err = s.userDB.AddUser(ctx, user)
+	if err != nil {
+		return internalError("registration_failed", "registration failed", err)
 	}
 
 	return nil
 }
 
-func (s *UserService) LoginUser(user models.User) (models.User, error) {
+func (s *UserService) LoginUser(ctx context.Context, user models.User) (_ models.User, err error) {
+	ctx, end := startSpan(ctx, "UserService.LoginUser")
+	defer end(&err)
 
-	existingUser, err := s.userDB.GetUserByUsernameAndPassword(user.Username, user.Password)
+	// Користувачі, створені під час входу через OIDC, мають порожній пароль і входять лише через постачальника
+	if user.Password == "" {
+		return models.User{}, errInvalidCredentials
+	}
+
+	// Стан ведеться за ім'ям і для неіснуючих користувачів: інакше блокування лише справжніх
+	// облікових записів підтверджувало б, що ім'я зареєстроване
+	state, err := s.userDB.GetLoginState(ctx, user.Username)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return models.User{}, errors.New("errNoRows")
+		return models.User{}, internalError("login_failed", "login failed", err)
+	}
+
+	// Заблокований обліковий запис не перевіряє пароль, інакше блокування не зупиняло б перебір
+	if wait := time.Until(state.LockedUntil); wait > 0 {
+		if s.metrics != nil {
+			s.metrics.LoginFailed()
 		}
-		return models.User{}, errors.New("user with that name isn't exists")
+		return models.User{}, errAccountLocked(wait)
+	}
+
+	existingUser, err := s.userDB.GetUserByUsernameAndPassword(ctx, user.Username, user.Password)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			return models.User{}, internalError("login_failed", "login failed", err)
+		}
+
+		if s.metrics != nil {
+			s.metrics.LoginFailed()
+		}
+		err = s.recordFailure(ctx, user.Username)
+		if err != nil {
+			return models.User{}, err
+		}
+		return models.User{}, errInvalidCredentials
+	}
+
+	// З 2FA вхід ще не завершено: лічильник скидається лише після правильного коду,
+	// інакше знання пароля дозволяло б підбирати код без блокування
+	if existingUser.TwoFactorEnabled {
+		return existingUser, nil
+	}
+
+	err = s.resetFailures(ctx, user.Username, state)
+	if err != nil {
+		return models.User{}, err
 	}
 
 	return existingUser, nil
 }
+
+// CompleteLogin - другий крок входу для користувача з 2FA, якого LoginUser уже перевірив паролем.
+// Невдалі коди рахуються разом із невдалими паролями і так само призводять до блокування
+func (s *UserService) CompleteLogin(ctx context.Context, userID int, code string) (_ models.User, err error) {
+	ctx, end := startSpan(ctx, "UserService.CompleteLogin")
+	defer end(&err)
+
+	user, err := s.userDB.GetUserByID(ctx, userID)
+	if err != nil || !user.TwoFactorEnabled || s.twoFactor == nil {
+		return models.User{}, errInvalidTwoFactorCode
+	}
+
+	state, err := s.userDB.GetLoginState(ctx, user.Username)
+	if err != nil {
+		return models.User{}, internalError("login_failed", "login failed", err)
+	}
+	if wait := time.Until(state.LockedUntil); wait > 0 {
+		if s.metrics != nil {
+			s.metrics.LoginFailed()
+		}
+		return models.User{}, errAccountLocked(wait)
+	}
+
+	ok, err := s.twoFactor.VerifyCode(ctx, userID, code)
+	if err != nil {
+		return models.User{}, err
+	}
+	if !ok {
+		if s.metrics != nil {
+			s.metrics.LoginFailed()
+		}
+		err = s.recordFailure(ctx, user.Username)
+		if err != nil {
+			return models.User{}, err
+		}
+		return models.User{}, errInvalidTwoFactorCode
+	}
+
+	err = s.resetFailures(ctx, user.Username, state)
+	if err != nil {
+		return models.User{}, err
+	}
+
+	return user, nil
+}
+
+// resetFailures знімає лічильник і блокування після успішного входу
+func (s *UserService) resetFailures(ctx context.Context, username string, state models.LoginState) error {
+	if state.FailedLogins == 0 && state.LockedUntil.IsZero() {
+		return nil
+	}
+
+	err := s.userDB.ResetLoginFailures(ctx, username)
+	if err != nil {
+		return internalError("login_failed", "login failed", err)
+	}
+
+	return nil
+}
+
+// recordFailure рахує невдалу спробу і блокує вхід, коли їх набралося lockoutThreshold поспіль
+func (s *UserService) recordFailure(ctx context.Context, username string) error {
+	failures, err := s.userDB.RecordLoginFailure(ctx, username)
+	if err != nil {
+		return internalError("login_failed", "login failed", err)
+	}
+
+	lockout := lockoutDuration(failures)
+	if lockout == 0 {
+		return nil
+	}
+
+	err = s.userDB.LockUser(ctx, username, time.Now().Add(lockout))
+	if err != nil {
+		return internalError("login_failed", "login failed", err)
+	}
+
+	return nil
+}
+
+func (s *UserService) GetProfile(ctx context.Context, userID int) (_ models.User, err error) {
+	ctx, end := startSpan(ctx, "UserService.GetProfile")
+	defer end(&err)
+
+	user, err := s.userDB.GetUserByID(ctx, userID)
+	if err != nil {
+		return models.User{}, errUserNotFound
+	}
+
+	return user, nil
+}
+
+// UpdateProfile змінює ім'я та/або часовий пояс; нове ім'я не повинно належати іншому користувачу
+func (s *UserService) UpdateProfile(ctx context.Context, userID int, update models.ProfileUpdate) (_ models.User, err error) {
+	ctx, end := startSpan(ctx, "UserService.UpdateProfile")
+	defer end(&err)
+
+	err = validateProfile(update)
+	if err != nil {
+		return models.User{}, err
+	}
+
+	user, err := s.userDB.GetUserByID(ctx, userID)
+	if err != nil {
+		return models.User{}, errUserNotFound
+	}
+
+	if update.Username != nil && *update.Username != user.Username {
+		existing, err := s.userDB.GetUserByUsername(ctx, *update.Username)
+		if err == nil && existing.ID != userID {
+			return models.User{}, errUsernameAlreadyExists
+		}
+		user.Username = *update.Username
+	}
+	if update.Email != nil && *update.Email != user.Email {
+		if *update.Email != "" {
+			existing, err := s.userDB.GetUserByEmail(ctx, *update.Email)
+			if err == nil && existing.ID != userID {
+				return models.User{}, errEmailAlreadyExists
+			}
+		}
+		user.Email = *update.Email
+	}
+	if update.TimeZone != nil {
+		user.TimeZone = *update.TimeZone
+		if user.TimeZone == "" {
+			user.TimeZone = "UTC"
+		}
+	}
+
+	err = s.userDB.UpdateUser(ctx, user)
+	if err != nil {
+		return models.User{}, internalError("profile_update_failed", "failed to update profile", err)
+	}
+
+	return user, nil
+}
+
+// ChangePassword перевіряє поточний пароль і встановлює новий. Версія токенів збільшується,
+// тож усі раніше видані токени відкликаються; повернений користувач має нову версію для нового токена
+func (s *UserService) ChangePassword(ctx context.Context, userID int, change models.PasswordChange) (_ models.User, err error) {
+	ctx, end := startSpan(ctx, "UserService.ChangePassword")
+	defer end(&err)
+
+	v := &validator{}
+	v.password("new_password", change.NewPassword)
+	err = v.err()
+	if err != nil {
+		return models.User{}, err
+	}
+
+	user, err := s.userDB.GetUserByID(ctx, userID)
+	if err != nil {
+		return models.User{}, errUserNotFound
+	}
+
+	// Користувач OIDC не має пароля, який можна було б підтвердити (встановити його можна відновленням пароля)
+	if change.CurrentPassword == "" {
+		return models.User{}, errInvalidPassword
+	}
+
+	_, err = s.userDB.GetUserByUsernameAndPassword(ctx, user.Username, change.CurrentPassword)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.User{}, errInvalidPassword
+		}
+		return models.User{}, internalError("password_change_failed", "failed to change password", err)
+	}
+
+	// Персональні токени відкликаються разом з JWT: ними міг скористатися той, хто знав старий пароль.
+	// Це робиться до зміни пароля, щоб збій не лишив старі токени чинними поряд з новим паролем
+	if s.accessTokens != nil {
+		err = s.accessTokens.RevokeAllTokens(ctx, userID)
+		if err != nil {
+			return models.User{}, internalError("password_change_failed", "failed to change password", err)
+		}
+	}
+
+	user.TokenVersion, err = s.userDB.ChangePassword(ctx, userID, change.NewPassword)
+	if err != nil {
+		return models.User{}, internalError("password_change_failed", "failed to change password", err)
+	}
+
+	return user, nil
+}
+
+// DeleteAccount видаляє користувача з його особистими даними; внесок у спільні журнали
+// залишається за знеособленим записом, щоб не змінити баланси інших учасників
+func (s *UserService) DeleteAccount(ctx context.Context, userID int) (err error) {
+	ctx, end := startSpan(ctx, "UserService.DeleteAccount")
+	defer end(&err)
+
+	_, err = s.userDB.GetUserByID(ctx, userID)
+	if err != nil {
+		return errUserNotFound
+	}
+
+	_, err = s.userDB.DeleteUser(ctx, userID)
+	if err != nil {
+		return internalError("account_delete_failed", "failed to delete account", err)
+	}
+
+	return nil
+}
@@ -1,9 +1,11 @@
 package services
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/ChomuCake/uni-golang-labs/models"
 )
@@ -12,37 +14,101 @@ type MockUserDBDetail struct {
 	mockAddUser                      func(user models.User) error
 	mockGetUserByUsernameAndPassword func(username, password string) (models.User, error)
 	mockGetUserByUsername            func(username string) (models.User, error)
+	mockGetUserByEmail               func(email string) (models.User, error)
 	mockGetUserByID                  func(userID int) (models.User, error)
+	mockGetLoginState                func(username string) (models.LoginState, error)
+	mockRecordLoginFailure           func(username string) (int, error)
+	mockLockUser                     func(username string, until time.Time) error
+	mockResetLoginFailures           func(username string) error
+	mockUpdateUser                   func(user models.User) error
+	mockChangePassword               func(userID int, password string) (int, error)
+	mockDeleteUser                   func(userID int) (bool, error)
 }
 
-func (m *MockUserDBDetail) AddUser(user models.User) error {
+func (m *MockUserDBDetail) AddUser(ctx context.Context, user models.User) error {
 	if m.mockAddUser != nil {
 		return m.mockAddUser(user)
 	}
 	return nil
 }
 
-func (m *MockUserDBDetail) GetUserByUsernameAndPassword(username, password string) (models.User, error) {
+func (m *MockUserDBDetail) GetUserByUsernameAndPassword(ctx context.Context, username, password string) (models.User, error) {
 	if m.mockGetUserByUsernameAndPassword != nil {
 		return m.mockGetUserByUsernameAndPassword(username, password)
 	}
 	return models.User{}, nil
 }
 
-func (m *MockUserDBDetail) GetUserByUsername(username string) (models.User, error) {
+func (m *MockUserDBDetail) GetUserByUsername(ctx context.Context, username string) (models.User, error) {
 	if m.mockGetUserByUsername != nil {
 		return m.mockGetUserByUsername(username)
 	}
 	return models.User{}, nil
 }
 
-func (m *MockUserDBDetail) GetUserByID(userID int) (models.User, error) {
+func (m *MockUserDBDetail) GetUserByEmail(ctx context.Context, email string) (models.User, error) {
+	if m.mockGetUserByEmail != nil {
+		return m.mockGetUserByEmail(email)
+	}
+	return models.User{}, nil
+}
+
+func (m *MockUserDBDetail) GetUserByID(ctx context.Context, userID int) (models.User, error) {
 	if m.mockGetUserByID != nil {
 		return m.mockGetUserByID(userID)
 	}
 	return models.User{}, nil
 }
 
+func (m *MockUserDBDetail) GetLoginState(ctx context.Context, username string) (models.LoginState, error) {
+	if m.mockGetLoginState != nil {
+		return m.mockGetLoginState(username)
+	}
+	return models.LoginState{}, nil
+}
+
+func (m *MockUserDBDetail) RecordLoginFailure(ctx context.Context, username string) (int, error) {
+	if m.mockRecordLoginFailure != nil {
+		return m.mockRecordLoginFailure(username)
+	}
+	return 1, nil
+}
+
+func (m *MockUserDBDetail) LockUser(ctx context.Context, username string, until time.Time) error {
+	if m.mockLockUser != nil {
+		return m.mockLockUser(username, until)
+	}
+	return nil
+}
+
+func (m *MockUserDBDetail) ResetLoginFailures(ctx context.Context, username string) error {
+	if m.mockResetLoginFailures != nil {
+		return m.mockResetLoginFailures(username)
+	}
+	return nil
+}
+
+func (m *MockUserDBDetail) UpdateUser(ctx context.Context, user models.User) error {
+	if m.mockUpdateUser != nil {
+		return m.mockUpdateUser(user)
+	}
+	return nil
+}
+
+func (m *MockUserDBDetail) ChangePassword(ctx context.Context, userID int, password string) (int, error) {
+	if m.mockChangePassword != nil {
+		return m.mockChangePassword(userID, password)
+	}
+	return 1, nil
+}
+
+func (m *MockUserDBDetail) DeleteUser(ctx context.Context, userID int) (bool, error) {
+	if m.mockDeleteUser != nil {
+		return m.mockDeleteUser(userID)
+	}
+	return false, nil
+}
+
 func TestUserService_RegisterUser_Success(t *testing.T) {
 	// Arrange
 	MockUserDBDetail := &MockUserDBDetail{
@@ -56,7 +122,7 @@ func TestUserService_RegisterUser_Success(t *testing.T) {
 	s := NewUserService(MockUserDBDetail)
 
 	// Act
-	err := s.RegisterUser(testUser)
+	err := s.RegisterUser(context.Background(), testUser)
 
 	// Assert
 	if err != nil {
@@ -74,7 +140,7 @@ func TestUserService_RegisterUser_UserExists(t *testing.T) {
 	s := NewUserService(MockUserDBDetail)
 
 	// Act
-	err := s.RegisterUser(testUser)
+	err := s.RegisterUser(context.Background(), testUser)
 
 	// Assert
 	expectedError := "user with such name is already exists"
@@ -96,7 +162,7 @@ func TestUserService_RegisterUser_AddUserError(t *testing.T) {
 	s := NewUserService(MockUserDBDetail)
 
 	// Act
-	err := s.RegisterUser(testUser)
+	err := s.RegisterUser(context.Background(), testUser)
 
 	// Assert
 	expectedError := "registration failed"
@@ -115,7 +181,7 @@ func TestUserService_LoginUser_Success(t *testing.T) {
 	s := NewUserService(MockUserDBDetail)
 
 	// Act
-	user, err := s.LoginUser(testUser)
+	user, err := s.LoginUser(context.Background(), testUser)
 
 	// Assert
 	if err != nil {
@@ -137,11 +203,476 @@ func TestUserService_LoginUser_UserNotFound(t *testing.T) {
 	s := NewUserService(MockUserDBDetail)
 
 	// Act
-	_, err := s.LoginUser(testUser)
+	_, err := s.LoginUser(context.Background(), testUser)
 
 	// Assert
-	expectedError := "errNoRows"
-	if err == nil || err.Error() != expectedError {
-		t.Errorf("Received incorrect error: received %v, expected %v", err, expectedError)
+	var serviceErr *Error
+	if !errors.As(err, &serviceErr) || !errors.Is(err, ErrUnauthorized) || serviceErr.Code != "invalid_credentials" {
+		t.Errorf("Received incorrect error: received %v, expected %v", err, errInvalidCredentials)
+	}
+}
+
+type countingLoginMetrics struct {
+	failed int
+}
+
+func (m *countingLoginMetrics) LoginFailed() { m.failed++ }
+
+func TestUserService_LoginUser_CountsFailedLogins(t *testing.T) {
+	// Arrange
+	metrics := &countingLoginMetrics{}
+	s := NewUserService(&MockUserDBDetail{
+		mockGetUserByUsernameAndPassword: func(username, password string) (models.User, error) {
+			if password == testUser.Password {
+				return testUser, nil
+			}
+			return models.User{}, sql.ErrNoRows
+		},
+	})
+	s.SetMetrics(metrics)
+	wrongPassword := testUser
+	wrongPassword.Password = "wrong"
+
+	// Act
+	_, _ = s.LoginUser(context.Background(), wrongPassword)
+	_, _ = s.LoginUser(context.Background(), testUser)
+
+	// Assert
+	if metrics.failed != 1 {
+		t.Errorf("Received incorrect failed logins: received %v, expected %v", metrics.failed, 1)
+	}
+}
+
+func TestUserService_LoginUser_DBError(t *testing.T) {
+	// Arrange
+	MockUserDBDetail := &MockUserDBDetail{
+		mockGetUserByUsernameAndPassword: func(username, password string) (models.User, error) {
+			return models.User{}, errors.New("connection refused")
+		},
+	}
+	s := NewUserService(MockUserDBDetail)
+
+	// Act
+	_, err := s.LoginUser(context.Background(), testUser)
+
+	// Assert
+	if !errors.Is(err, ErrInternal) {
+		t.Errorf("Received incorrect error: received %v, expected %v", err, ErrInternal)
+	}
+}
+
+func TestUserService_RegisterUser_Validation(t *testing.T) {
+	// Arrange
+	s := NewUserService(&MockUserDBDetail{
+		mockAddUser: func(user models.User) error {
+			t.Errorf("Invalid user must not be saved")
+			return nil
+		},
+	})
+
+	// Act
+	err := s.RegisterUser(context.Background(), models.User{Username: "", Password: "short"})
+
+	// Assert
+	var serviceErr *Error
+	if !errors.As(err, &serviceErr) || !errors.Is(err, ErrInvalid) {
+		t.Fatalf("Received incorrect error: received %v, expected validation error", err)
+	}
+
+	fields := map[string]bool{}
+	for _, field := range serviceErr.Fields {
+		fields[field.Field+"/"+field.Code] = true
+	}
+	for _, expected := range []string{"username/invalid_length", "password/invalid_length", "password/weak_password"} {
+		if !fields[expected] {
+			t.Errorf("Missing field error %v in %v", expected, serviceErr.Fields)
+		}
+	}
+}
+
+func TestUserService_RegisterUser_UnknownTimeZone(t *testing.T) {
+	// Arrange
+	user := testUser
+	user.TimeZone = "Mars/Olympus"
+	s := NewUserService(&MockUserDBDetail{})
+
+	// Act
+	err := s.RegisterUser(context.Background(), user)
+
+	// Assert
+	serviceErr, ok := err.(*Error)
+	if !ok || len(serviceErr.Fields) != 1 || serviceErr.Fields[0].Field != "time_zone" {
+		t.Errorf("Received incorrect error: received %v, expected time zone validation error", err)
+	}
+}
+
+// lockoutUserDB імітує запис таблиці login_failures для імені одного користувача
+func lockoutUserDB(state *models.LoginState) *MockUserDBDetail {
+	return &MockUserDBDetail{
+		mockGetLoginState: func(username string) (models.LoginState, error) {
+			return *state, nil
+		},
+		mockGetUserByUsernameAndPassword: func(username, password string) (models.User, error) {
+			if password == testUser.Password {
+				return testUser, nil
+			}
+			return models.User{}, sql.ErrNoRows
+		},
+		mockRecordLoginFailure: func(username string) (int, error) {
+			state.FailedLogins++
+			return state.FailedLogins, nil
+		},
+		mockLockUser: func(username string, until time.Time) error {
+			state.LockedUntil = until
+			return nil
+		},
+		mockResetLoginFailures: func(username string) error {
+			*state = models.LoginState{}
+			return nil
+		},
+	}
+}
+
+func TestUserService_LoginUser_LocksOutAfterRepeatedFailures(t *testing.T) {
+	// Arrange
+	state := &models.LoginState{}
+	s := NewUserService(lockoutUserDB(state))
+	wrongPassword := testUser
+	wrongPassword.Password = "wrong"
+
+	// Act
+	for i := 0; i < lockoutThreshold; i++ {
+		_, err := s.LoginUser(context.Background(), wrongPassword)
+		if !errors.Is(err, ErrUnauthorized) {
+			t.Fatalf("Received incorrect error for attempt %d: received %v, expected %v", i+1, err, errInvalidCredentials)
+		}
+	}
+	_, err := s.LoginUser(context.Background(), testUser)
+
+	// Assert
+	var serviceErr *Error
+	if !errors.As(err, &serviceErr) || !errors.Is(err, ErrTooMany) || serviceErr.Code != "account_locked" {
+		t.Fatalf("Received incorrect error: received %v, expected account_locked", err)
+	}
+	if serviceErr.RetryAfter <= 0 || serviceErr.RetryAfter > lockoutBase {
+		t.Errorf("Received incorrect retry after: received %v, expected up to %v", serviceErr.RetryAfter, lockoutBase)
+	}
+	if state.FailedLogins != lockoutThreshold {
+		t.Errorf("Locked account must not count attempts: received %v, expected %v", state.FailedLogins, lockoutThreshold)
+	}
+}
+
+func TestUserService_LoginUser_UnknownUsernameLocksOutAlike(t *testing.T) {
+	// Arrange: ім'я не зареєстроване, тож жоден пароль не підходить
+	state := &models.LoginState{}
+	db := lockoutUserDB(state)
+	db.mockGetUserByUsernameAndPassword = func(username, password string) (models.User, error) {
+		return models.User{}, sql.ErrNoRows
+	}
+	s := NewUserService(db)
+	unknown := models.User{Username: "nobody", Password: "guess"}
+
+	// Act
+	for i := 0; i < lockoutThreshold; i++ {
+		_, _ = s.LoginUser(context.Background(), unknown)
+	}
+	_, err := s.LoginUser(context.Background(), unknown)
+
+	// Assert
+	var serviceErr *Error
+	if !errors.As(err, &serviceErr) || serviceErr.Code != "account_locked" || serviceErr.RetryAfter <= 0 {
+		t.Errorf("Unknown username must be locked out like an existing one: received %v, expected account_locked", err)
+	}
+}
+
+func TestUserService_LoginUser_SuccessResetsFailures(t *testing.T) {
+	// Arrange: блокування вже минуло, але лічильник ще не скинуто
+	state := &models.LoginState{FailedLogins: lockoutThreshold, LockedUntil: time.Now().Add(-time.Second)}
+	s := NewUserService(lockoutUserDB(state))
+
+	// Act
+	_, err := s.LoginUser(context.Background(), testUser)
+
+	// Assert
+	if err != nil {
+		t.Errorf("Received an error: received %v, expected %v", err, nil)
+	}
+	if *state != (models.LoginState{}) {
+		t.Errorf("Received incorrect login state: received %+v, expected %+v", *state, models.LoginState{})
+	}
+}
+
+// codeVerifier приймає лише один код, як TwoFactorService з налаштованим автентифікатором
+type codeVerifier string
+
+func (v codeVerifier) VerifyCode(ctx context.Context, userID int, code string) (bool, error) {
+	return code == string(v), nil
+}
+
+func TestUserService_TwoStepLogin(t *testing.T) {
+	// Arrange: після двох невдалих паролів правильний пароль ще не скидає лічильник
+	state := &models.LoginState{FailedLogins: 2}
+	db := lockoutUserDB(state)
+	twoFactorUser := testUser
+	twoFactorUser.TwoFactorEnabled = true
+	db.mockGetUserByUsernameAndPassword = func(username, password string) (models.User, error) {
+		return twoFactorUser, nil
+	}
+	db.mockGetUserByID = func(userID int) (models.User, error) {
+		return twoFactorUser, nil
+	}
+	s := NewUserService(db)
+	s.SetTwoFactor(codeVerifier("123456"))
+
+	// Act
+	user, loginErr := s.LoginUser(context.Background(), testUser)
+	failuresAfterPassword := state.FailedLogins
+	_, wrongErr := s.CompleteLogin(context.Background(), user.ID, "654321")
+	completed, err := s.CompleteLogin(context.Background(), user.ID, "123456")
+
+	// Assert
+	if loginErr != nil || !user.TwoFactorEnabled || failuresAfterPassword != 2 {
+		t.Errorf("Received incorrect first step: received %v, %+v, %d failures, expected 2 failures", loginErr, user, failuresAfterPassword)
+	}
+	if !errors.Is(wrongErr, ErrUnauthorized) {
+		t.Errorf("Received incorrect error: received %v, expected %v", wrongErr, errInvalidTwoFactorCode)
+	}
+	if err != nil || completed.ID != testUser.ID || *state != (models.LoginState{}) {
+		t.Errorf("Received incorrect result: received %v, %+v, state %+v, expected reset failures", err, completed, *state)
+	}
+}
+
+func TestUserService_CompleteLogin_LocksOutAfterWrongCodes(t *testing.T) {
+	// Arrange
+	state := &models.LoginState{}
+	db := lockoutUserDB(state)
+	db.mockGetUserByID = func(userID int) (models.User, error) {
+		user := testUser
+		user.TwoFactorEnabled = true
+		return user, nil
+	}
+	s := NewUserService(db)
+	s.SetTwoFactor(codeVerifier("123456"))
+
+	// Act
+	for i := 0; i < lockoutThreshold; i++ {
+		_, _ = s.CompleteLogin(context.Background(), testUser.ID, "000000")
+	}
+	_, err := s.CompleteLogin(context.Background(), testUser.ID, "123456")
+
+	// Assert
+	if !errors.Is(err, ErrTooMany) {
+		t.Errorf("Received incorrect error: received %v, expected account_locked", err)
+	}
+}
+
+func TestLockoutDuration(t *testing.T) {
+	cases := map[int]time.Duration{
+		lockoutThreshold - 1:  0,
+		lockoutThreshold:      lockoutBase,
+		lockoutThreshold + 1:  2 * lockoutBase,
+		lockoutThreshold + 3:  8 * lockoutBase,
+		lockoutThreshold + 50: lockoutMax,
+	}
+
+	for failures, expected := range cases {
+		if received := lockoutDuration(failures); received != expected {
+			t.Errorf("Received incorrect lockout for %d failures: received %v, expected %v", failures, received, expected)
+		}
+	}
+}
+
+func TestUserService_UpdateProfile(t *testing.T) {
+	// Arrange
+	var saved models.User
+	s := NewUserService(&MockUserDBDetail{
+		mockGetUserByID: func(userID int) (models.User, error) {
+			return models.User{ID: userID, Username: "alice", TimeZone: "UTC"}, nil
+		},
+		mockGetUserByUsername: func(username string) (models.User, error) {
+			return models.User{}, sql.ErrNoRows
+		},
+		mockUpdateUser: func(user models.User) error {
+			saved = user
+			return nil
+		},
+	})
+	username, timeZone := "alice_k", "Europe/Kyiv"
+
+	// Act
+	user, err := s.UpdateProfile(context.Background(), 7, models.ProfileUpdate{Username: &username, TimeZone: &timeZone})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Received an error: received %v, expected %v", err, nil)
+	}
+	expected := models.User{ID: 7, Username: username, TimeZone: timeZone}
+	if user != expected || saved != expected {
+		t.Errorf("Received incorrect profile: received %+v, saved %+v, expected %+v", user, saved, expected)
+	}
+}
+
+func TestUserService_UpdateProfile_UsernameTaken(t *testing.T) {
+	// Arrange
+	s := NewUserService(&MockUserDBDetail{
+		mockGetUserByID: func(userID int) (models.User, error) {
+			return models.User{ID: userID, Username: "alice"}, nil
+		},
+		mockGetUserByUsername: func(username string) (models.User, error) {
+			return models.User{ID: 8, Username: username}, nil
+		},
+		mockUpdateUser: func(user models.User) error {
+			t.Errorf("Profile with a taken username must not be saved")
+			return nil
+		},
+	})
+	username := "bob"
+
+	// Act
+	_, err := s.UpdateProfile(context.Background(), 7, models.ProfileUpdate{Username: &username})
+
+	// Assert
+	if !errors.Is(err, ErrConflict) {
+		t.Errorf("Received incorrect error: received %v, expected %v", err, errUsernameAlreadyExists)
+	}
+}
+
+func TestUserService_ChangePassword(t *testing.T) {
+	tests := []struct {
+		name          string
+		change        models.PasswordChange
+		expectedError error
+	}{
+		{"success", models.PasswordChange{CurrentPassword: testUser.Password, NewPassword: "n3w-password"}, nil},
+		{"wrong current password", models.PasswordChange{CurrentPassword: "wrong", NewPassword: "n3w-password"}, ErrForbidden},
+		{"weak new password", models.PasswordChange{CurrentPassword: testUser.Password, NewPassword: "short"}, ErrInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			changed := ""
+			s := NewUserService(&MockUserDBDetail{
+				mockGetUserByID: func(userID int) (models.User, error) {
+					return models.User{ID: userID, Username: testUser.Username, TokenVersion: 2}, nil
+				},
+				mockGetUserByUsernameAndPassword: func(username, password string) (models.User, error) {
+					if password == testUser.Password {
+						return testUser, nil
+					}
+					return models.User{}, sql.ErrNoRows
+				},
+				mockChangePassword: func(userID int, password string) (int, error) {
+					changed = password
+					return 3, nil
+				},
+			})
+
+			// Act
+			user, err := s.ChangePassword(context.Background(), 7, tt.change)
+
+			// Assert
+			if tt.expectedError != nil {
+				if !errors.Is(err, tt.expectedError) || changed != "" {
+					t.Errorf("Received incorrect error: received %v, expected %v", err, tt.expectedError)
+				}
+				return
+			}
+			if err != nil || changed != tt.change.NewPassword || user.TokenVersion != 3 {
+				t.Errorf("Received incorrect result: received %+v, %v, expected token version %v", user, err, 3)
+			}
+		})
+	}
+}
+
+func TestUserService_ChangePassword_RevokesAccessTokens(t *testing.T) {
+	// Arrange
+	tokenDB := &MockAccessTokenDB{}
+	accessTokens := NewAccessTokenService(tokenDB)
+	own, _ := accessTokens.CreateToken(context.Background(), 7, models.AccessTokenRequest{Name: "script", Scopes: []string{models.ScopeExpensesRead}})
+	other, _ := accessTokens.CreateToken(context.Background(), 8, models.AccessTokenRequest{Name: "script", Scopes: []string{models.ScopeExpensesRead}})
+	s := NewUserService(&MockUserDBDetail{
+		mockGetUserByID: func(userID int) (models.User, error) {
+			return models.User{ID: userID, Username: testUser.Username}, nil
+		},
+		mockGetUserByUsernameAndPassword: func(username, password string) (models.User, error) {
+			return testUser, nil
+		},
+		mockChangePassword: func(userID int, password string) (int, error) {
+			return 1, nil
+		},
+	})
+	s.SetAccessTokens(accessTokens)
+
+	// Act
+	_, err := s.ChangePassword(context.Background(), 7, models.PasswordChange{CurrentPassword: testUser.Password, NewPassword: "n3w-password"})
+	_, ownErr := accessTokens.AuthenticateAccessToken(context.Background(), own.Token, models.ScopeExpensesRead)
+	_, otherErr := accessTokens.AuthenticateAccessToken(context.Background(), other.Token, models.ScopeExpensesRead)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Received an error: received %v, expected %v", err, nil)
+	}
+	if !errors.Is(ownErr, ErrUnauthorized) {
+		t.Errorf("Received an error: received %v, expected %v", ownErr, errInvalidAccessToken)
+	}
+	if otherErr != nil {
+		t.Errorf("Received an error for another user's token: received %v, expected %v", otherErr, nil)
+	}
+}
+
+func TestUserService_DeleteAccount(t *testing.T) {
+	// Arrange
+	deleted := 0
+	s := NewUserService(&MockUserDBDetail{
+		mockGetUserByID: func(userID int) (models.User, error) {
+			if userID != 7 {
+				return models.User{}, errors.New("user not found")
+			}
+			return models.User{ID: userID}, nil
+		},
+		mockDeleteUser: func(userID int) (bool, error) {
+			deleted = userID
+			return true, nil
+		},
+	})
+
+	// Act
+	err := s.DeleteAccount(context.Background(), 7)
+	missingErr := s.DeleteAccount(context.Background(), 8)
+
+	// Assert
+	if err != nil || deleted != 7 {
+		t.Errorf("Received incorrect result: received %v, deleted %v, expected %v", err, deleted, 7)
+	}
+	if !errors.Is(missingErr, ErrNotFound) {
+		t.Errorf("Received incorrect error: received %v, expected %v", missingErr, errUserNotFound)
+	}
+}
+
+func TestUserService_RegisterUser_EmailTaken(t *testing.T) {
+	// Arrange
+	user := testUser
+	user.Email = "alice@example.com"
+	s := NewUserService(&MockUserDBDetail{
+		mockGetUserByUsername: func(username string) (models.User, error) {
+			return models.User{}, sql.ErrNoRows
+		},
+		mockGetUserByEmail: func(email string) (models.User, error) {
+			return models.User{ID: 8, Email: email}, nil
+		},
+		mockAddUser: func(user models.User) error {
+			t.Errorf("User with a taken email must not be saved")
+			return nil
+		},
+	})
+
+	// Act
+	err := s.RegisterUser(context.Background(), user)
+
+	// Assert
+	var serviceErr *Error
+	if !errors.As(err, &serviceErr) || serviceErr.Code != "email_taken" {
+		t.Errorf("Received incorrect error: received %v, expected %v", err, errEmailAlreadyExists)
 	}
 }
@@ -0,0 +1,171 @@
+package services
+
+import (
+	"fmt"
+	"net/mail"
+	"regexp"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/ChomuCake/uni-golang-labs/models"
+)
+
+// Обмеження для вхідних даних
+const (
+	minExpenseAmount  = 1
+	maxExpenseAmount  = 100000000
+	maxCategoryLength = 64
+	minUsernameLength = 3
+	maxUsernameLength = 32
+	minPasswordLength = 8
+	maxPasswordLength = 72
+	maxEmailLength    = 254
+)
+
+// maxFutureDate - наскільки далеко в майбутньому може бути дата запису
+var maxFutureDate = 365 * 24 * time.Hour
+
+var (
+	categoryPattern = regexp.MustCompile(`^[\p{L}\p{N} _&'.,()-]+$`)
+	usernamePattern = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+)
+
+// validator накопичує всі порушення, щоб повідомити про них клієнту разом
+type validator struct {
+	fields []FieldError
+}
+
+func (v *validator) add(field, code, message string) {
+	v.fields = append(v.fields, FieldError{Field: field, Code: code, Message: message})
+}
+
+func (v *validator) intRange(field string, value, min, max int) {
+	if value < min || value > max {
+		v.add(field, "out_of_range", fmt.Sprintf("must be between %d and %d", min, max))
+	}
+}
+
+func (v *validator) length(field, value string, min, max int) {
+	n := utf8.RuneCountInString(value)
+	if n < min || n > max {
+		v.add(field, "invalid_length", fmt.Sprintf("must be between %d and %d characters long", min, max))
+	}
+}
+
+func (v *validator) matches(field, value string, pattern *regexp.Regexp, message string) {
+	if value != "" && !pattern.MatchString(value) {
+		v.add(field, "invalid_format", message)
+	}
+}
+
+func (v *validator) check(ok bool, field, code, message string) {
+	if !ok {
+		v.add(field, code, message)
+	}
+}
+
+func (v *validator) notAfter(field string, value, limit time.Time) {
+	if value.After(limit) {
+		v.add(field, "too_far_in_future", "must not be more than a year in the future")
+	}
+}
+
+// err повертає помилку валідації з переліком усіх порушень або nil
+func (v *validator) err() error {
+	if len(v.fields) == 0 {
+		return nil
+	}
+
+	return &Error{
+		Kind:    ErrInvalid,
+		Code:    "validation_failed",
+		Message: "validation failed",
+		Fields:  v.fields,
+	}
+}
+
+func validateExpense(expense models.Expense) error {
+	v := &validator{}
+	v.intRange("amount", expense.Amount, minExpenseAmount, maxExpenseAmount)
+	v.length("category", expense.Category, 1, maxCategoryLength)
+	v.matches("category", expense.Category, categoryPattern, "may contain only letters, digits, spaces and _&'.,()-")
+	v.notAfter("date", expense.Date, time.Now().Add(maxFutureDate))
+	return v.err()
+}
+
+func validateBudget(budget models.Budget) error {
+	v := &validator{}
+	v.intRange("amount", budget.Amount, minExpenseAmount, maxExpenseAmount)
+	v.length("category", budget.Category, 1, maxCategoryLength)
+	v.matches("category", budget.Category, categoryPattern, "may contain only letters, digits, spaces and _&'.,()-")
+	return v.err()
+}
+
+func validateUser(user models.User) error {
+	v := &validator{}
+	v.username("username", user.Username)
+	v.password("password", user.Password)
+	v.email("email", user.Email)
+	v.timeZone("time_zone", user.TimeZone)
+	return v.err()
+}
+
+// validateProfile перевіряє ті поля профілю, які користувач змінює
+func validateProfile(update models.ProfileUpdate) error {
+	v := &validator{}
+	if update.Username != nil {
+		v.username("username", *update.Username)
+	}
+	if update.Email != nil {
+		v.email("email", *update.Email)
+	}
+	if update.TimeZone != nil {
+		v.timeZone("time_zone", *update.TimeZone)
+	}
+	return v.err()
+}
+
+func (v *validator) username(field, value string) {
+	v.length(field, value, minUsernameLength, maxUsernameLength)
+	v.matches(field, value, usernamePattern, "may contain only latin letters, digits and _.-")
+}
+
+func (v *validator) password(field, value string) {
+	v.length(field, value, minPasswordLength, maxPasswordLength)
+	v.check(containsRune(value, unicode.IsLetter) && containsRune(value, unicode.IsDigit),
+		field, "weak_password", "must contain at least one letter and one digit")
+}
+
+// email перевіряє необов'язкову адресу: лише сама адреса, без імені та кутових дужок
+func (v *validator) email(field, value string) {
+	if value == "" {
+		return
+	}
+
+	address, err := mail.ParseAddress(value)
+	v.check(err == nil && address.Address == value && len(value) <= maxEmailLength,
+		field, "invalid_format", "must be an email address, e.g. name@example.com")
+}
+
+func (v *validator) timeZone(field, value string) {
+	v.check(validTimeZone(value), field, "unknown_time_zone", "must be an IANA time zone name, e.g. Europe/Kyiv")
+}
+
+func containsRune(value string, predicate func(rune) bool) bool {
+	for _, r := range value {
+		if predicate(r) {
+			return true
+		}
+	}
+	return false
+}
+
+func validTimeZone(name string) bool {
+	if name == "" {
+		return true
+	}
+
+	_, err := time.LoadLocation(name)
+	return err == nil
+}
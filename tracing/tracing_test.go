@@ -0,0 +1,79 @@
+package tracing
+
+import (
+	"context"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	collectortrace "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// Трасування, передане клієнтом у заголовку traceparent
+const (
+	incomingTraceID     = "4bf92f3577b34da6a3ce929d0e0e4736"
+	incomingTraceparent = "00-" + incomingTraceID + "-00f067aa0ba902b7-01"
+)
+
+func TestSetup_ExportsServerSpanToOTLPCollector(t *testing.T) {
+	// Arrange: локальний колектор приймає OTLP/HTTP у форматі protobuf
+	received := make(chan *collectortrace.ExportTraceServiceRequest, 1)
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var req collectortrace.ExportTraceServiceRequest
+		if r.URL.Path == "/v1/traces" && proto.Unmarshal(body, &req) == nil {
+			received <- &req
+		}
+		w.Header().Set("Content-Type", "application/x-protobuf")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer collector.Close()
+
+	shutdown, err := Setup(context.Background(), Config{
+		Exporter:     ExporterOTLP,
+		OTLPEndpoint: strings.TrimPrefix(collector.URL, "http://"),
+		OTLPInsecure: true,
+	})
+	if err != nil {
+		t.Fatalf("Received an error: received %v, expected %v", err, nil)
+	}
+
+	handler := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	req := httptest.NewRequest(http.MethodGet, "/expenses", nil)
+	req.Header.Set("traceparent", incomingTraceparent)
+
+	// Act
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	err = shutdown(context.Background())
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Received an error on shutdown: received %v, expected %v", err, nil)
+	}
+
+	select {
+	case export := <-received:
+		span := export.ResourceSpans[0].ScopeSpans[0].Spans[0]
+		if span.Name != "HTTP GET" || hex.EncodeToString(span.TraceId) != incomingTraceID {
+			t.Errorf("Received incorrect span: name %q, trace %x", span.Name, span.TraceId)
+		}
+	default:
+		t.Errorf("Collector received no spans")
+	}
+}
+
+func TestSetup_UnknownExporter(t *testing.T) {
+	// Act
+	_, err := Setup(context.Background(), Config{Exporter: "jaeger"})
+
+	// Assert
+	if err == nil {
+		t.Errorf("Received an error: received %v, expected unknown exporter error", err)
+	}
+}
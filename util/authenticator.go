@@ -0,0 +1,51 @@
+package util
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/ChomuCake/uni-golang-labs/logging"
+	"github.com/ChomuCake/uni-golang-labs/models"
+)
+
+// інтерфейс AccessTokens описується в тому ж файлі що і використовується
+type AccessTokens interface {
+	AuthenticateAccessToken(ctx context.Context, token, scope string) (int, error)
+}
+
+type scopeKey struct{}
+
+// WithScope позначає, який scope персонального токена потрібен маршруту
+func WithScope(ctx context.Context, scope string) context.Context {
+	return context.WithValue(ctx, scopeKey{}, scope)
+}
+
+// RequiredScope повертає scope маршруту; порожній - маршрут не приймає персональні токени
+func RequiredScope(ctx context.Context) string {
+	scope, _ := ctx.Value(scopeKey{}).(string)
+	return scope
+}
+
+// Authenticator приймає як JWT, так і персональні токени доступу (з префіксом models.AccessTokenPrefix);
+// видачу JWT і другий крок входу успадковує від JWTTokenManager
+type Authenticator struct {
+	JWTTokenManager
+	AccessTokens AccessTokens
+}
+
+func (a Authenticator) ExtractUserIDFromRequest(r *http.Request) (int, error) {
+	tokenString := a.ExtractToken(r)
+	if !strings.HasPrefix(tokenString, models.AccessTokenPrefix) || a.AccessTokens == nil {
+		return a.JWTTokenManager.ExtractUserIDFromRequest(r)
+	}
+
+	userID, err := a.AccessTokens.AuthenticateAccessToken(r.Context(), tokenString, RequiredScope(r.Context()))
+	if err != nil {
+		return 0, err
+	}
+
+	// Користувач потрапляє в журнал доступу і в записи про помилки цього запиту
+	logging.SetUserID(r.Context(), userID)
+	return userID, nil
+}
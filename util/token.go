@@ -1,24 +1,68 @@
 package util
 
 import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"fmt"
 	"net/http"
 	"strings"
 	"time"
 
+	"github.com/ChomuCake/uni-golang-labs/logging"
 	"github.com/ChomuCake/uni-golang-labs/models"
 	"github.com/dgrijalva/jwt-go"
 	_ "github.com/go-sql-driver/mysql"
 )
 
-type JWTTokenManager struct{}
+// інтерфейс TokenVersions описується в тому ж файлі що і використовується
+type TokenVersions interface {
+	GetTokenVersion(ctx context.Context, userID int) (int, error)
+}
+
+// JWTTokenManager видає і перевіряє JWT. Якщо задано Versions, токен приймається лише з поточною
+// версією токенів користувача: зміна пароля чи видалення облікового запису відкликає видані раніше токени
+type JWTTokenManager struct {
+	Versions TokenVersions
+}
 
 var secretKey = []byte("fd9f5dc52a0b5728c5182c593e0fae7d821e6c7a0fe64b78e67450a0a6860d63")
 
+// challengeType - значення claim "typ" токена другого кроку входу; звичайні токени цього claim не мають
+const challengeType = "2fa"
+
+// challengeTTL - скільки часу є на введення коду 2FA після пароля
+const challengeTTL = 5 * time.Minute
+
+// TokenTTL - термін дії JWT, а отже і cookie-сесії
+const TokenTTL = 24 * time.Hour
+
+// Cookie-сесія браузерного інтерфейсу: JWT лежить у HttpOnly cookie, недоступному для скриптів.
+// Такий cookie браузер додає і до підроблених запитів з інших сайтів, тож зміни стану вимагають
+// ще й CSRF-токен: скрипт інтерфейсу читає його з CSRFCookie і повторює в заголовку CSRFHeader
+const (
+	SessionCookie = "fintrack_session"
+	CSRFCookie    = "fintrack_csrf"
+	CSRFHeader    = "X-CSRF-Token"
+)
+
+// ErrCSRFToken - запит через cookie-сесію змінює стан без правильного CSRF-токена
+var ErrCSRFToken = errors.New("missing or invalid CSRF token")
+
+// ErrTokenCheck - версію токенів не вдалося перевірити (база недоступна, тайм-аут). Це збій
+// сервера, а не недійсний токен: клієнт не повинен відкидати сесію
+var ErrTokenCheck = errors.New("failed to check token version")
+
 func (tm JWTTokenManager) GenerateToken(user models.User) (string, error) {
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
 		"id":       user.ID,
 		"username": user.Username,
-		"exp":      time.Now().Add(time.Hour * 24).Unix(), // Токен дійсний протягом 24 годин
+		"ver":      user.TokenVersion,
+		"exp":      time.Now().Add(TokenTTL).Unix(),
 	})
 
 	tokenString, err := token.SignedString(secretKey)
@@ -29,6 +73,33 @@ func (tm JWTTokenManager) GenerateToken(user models.User) (string, error) {
 	return tokenString, nil
 }
 
+// GenerateChallengeToken видає короткочасний токен, який підтверджує, що пароль уже перевірено;
+// він не дає доступу до API і приймається лише на POST /login/2fa
+func (tm JWTTokenManager) GenerateChallengeToken(user models.User) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"id":  user.ID,
+		"typ": challengeType,
+		"exp": time.Now().Add(challengeTTL).Unix(),
+	})
+
+	return token.SignedString(secretKey)
+}
+
+// VerifyChallengeToken перевіряє токен другого кроку входу і повертає айді користувача
+func (tm JWTTokenManager) VerifyChallengeToken(tokenString string) (int, error) {
+	token, err := tm.VerifyToken(tokenString)
+	if err != nil {
+		return 0, err
+	}
+
+	claims := token.(*jwt.Token).Claims.(jwt.MapClaims)
+	if typ, _ := claims["typ"].(string); typ != challengeType {
+		return 0, jwt.ErrInvalidKey
+	}
+
+	return tm.ExtractUserIDFromToken(token)
+}
+
 func (tm JWTTokenManager) VerifyToken(tokenString string) (interface{}, error) {
 	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
 		return secretKey, nil
@@ -64,14 +135,68 @@ func (tm JWTTokenManager) ExtractUserIDFromToken(token interface{}) (int, error)
 	return int(userID), nil
 }
 
+// CSRFToken виводить CSRF-токен із JWT сесії: токен з чужого cookie (підкинутого, наприклад,
+// з піддомену) до сесії користувача не підійде, і сервер нічого не зберігає
+func CSRFToken(sessionToken string) string {
+	mac := hmac.New(sha256.New, secretKey)
+	mac.Write([]byte("csrf:" + sessionToken))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// SessionCookieUsed повідомляє, що запит автентифікується cookie-сесією: заголовок Authorization
+// має перевагу, тож API-клієнти з токеном працюють як раніше
+func SessionCookieUsed(r *http.Request) bool {
+	if r.Header.Get("Authorization") != "" {
+		return false
+	}
+	_, err := r.Cookie(SessionCookie)
+	return err == nil
+}
+
 func (tm JWTTokenManager) ExtractToken(r *http.Request) string {
+	if SessionCookieUsed(r) {
+		cookie, _ := r.Cookie(SessionCookie)
+		return cookie.Value
+	}
+
 	// Отримання токена з заголовка авторизації
 	tokenString := strings.TrimSpace(strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "))
 	return tokenString
 }
 
+// checkCSRF пропускає безпечні методи і запити з токеном у заголовку Authorization
+func checkCSRF(r *http.Request, tokenString string) error {
+	if !SessionCookieUsed(r) {
+		return nil
+	}
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return nil
+	}
+
+	header := r.Header.Get(CSRFHeader)
+	if header == "" || subtle.ConstantTimeCompare([]byte(header), []byte(CSRFToken(tokenString))) != 1 {
+		return ErrCSRFToken
+	}
+	return nil
+}
+
+// CheckCSRF - перевірка CSRF для маршрутів cookie-сесії, яким не потрібен чинний JWT (вихід):
+// токен порівнюється з поточним значенням cookie сесії
+func CheckCSRF(r *http.Request) error {
+	tokenString := ""
+	if cookie, err := r.Cookie(SessionCookie); err == nil {
+		tokenString = cookie.Value
+	}
+	return checkCSRF(r, tokenString)
+}
+
 func (tm JWTTokenManager) ExtractUserIDFromRequest(r *http.Request) (int, error) {
 	tokenString := tm.ExtractToken(r)
+	err := checkCSRF(r, tokenString)
+	if err != nil {
+		return 0, err
+	}
 
 	token, err := tm.VerifyToken(tokenString)
 	if err != nil {
@@ -82,5 +207,31 @@ func (tm JWTTokenManager) ExtractUserIDFromRequest(r *http.Request) (int, error)
 	if err != nil {
 		return 0, err
 	}
+
+	// Токен другого кроку входу (пароль перевірено, код 2FA - ні) не дає доступу до API
+	claims := token.(*jwt.Token).Claims.(jwt.MapClaims)
+	if _, ok := claims["typ"]; ok {
+		return 0, jwt.ErrInvalidKey
+	}
+
+	if tm.Versions != nil {
+		// Токени, видані до появи версій, не мають claim "ver" і відповідають версії 0
+		tokenVersion, _ := claims["ver"].(float64)
+
+		// Недійсним токен робить лише видалений користувач або змінена версія
+		version, err := tm.Versions.GetTokenVersion(r.Context(), userID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, jwt.ErrInvalidKey
+		}
+		if err != nil {
+			return 0, fmt.Errorf("%w: %w", ErrTokenCheck, err)
+		}
+		if int(tokenVersion) != version {
+			return 0, jwt.ErrInvalidKey
+		}
+	}
+
+	// Користувач потрапляє в журнал доступу і в записи про помилки цього запиту
+	logging.SetUserID(r.Context(), userID)
 	return userID, nil
 }